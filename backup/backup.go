@@ -0,0 +1,150 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// FormatVersion is the version of the backup layout written by Write. Backups written before
+// manifests were introduced have no manifest and are read as version 0.
+const FormatVersion = 1
+
+const (
+	// ManifestFile describes the backup and holds the checksums of the other files
+	ManifestFile = "manifest.json"
+	// DocumentsFile holds the documents ordered by ID
+	DocumentsFile = "documents.json"
+	// EdgesFile holds the edges ordered by ID
+	EdgesFile = "edges.json"
+)
+
+// Manifest describes where and when a backup was taken and what it contains
+type Manifest struct {
+	Version   int               `json:"version"`
+	CreatedAt time.Time         `json:"created_at"`
+	ChainID   string            `json:"chain_id"`
+	Contract  string            `json:"contract"`
+	HeadBlock uint32            `json:"head_block"`
+	Documents int               `json:"documents"`
+	Edges     int               `json:"edges"`
+	Checksums map[string]string `json:"checksums"`
+}
+
+// Snapshot is a backup read into memory
+type Snapshot struct {
+	Dir       string
+	Manifest  Manifest
+	Documents []docgraph.Document
+	Edges     []docgraph.Edge
+}
+
+// Write writes the documents, edges and a manifest describing them into dir, which must exist.
+// The manifest's counts, checksums and version are filled in from the data.
+func Write(dir string, manifest Manifest, documents []docgraph.Document, edges []docgraph.Edge) (Manifest, error) {
+	sort.Slice(documents, func(i, j int) bool { return documents[i].ID < documents[j].ID })
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+
+	manifest.Version = FormatVersion
+	manifest.Documents = len(documents)
+	manifest.Edges = len(edges)
+	manifest.Checksums = make(map[string]string)
+
+	for fileName, data := range map[string]interface{}{DocumentsFile: documents, EdgesFile: edges} {
+		checksum, err := writeJSON(filepath.Join(dir, fileName), data)
+		if err != nil {
+			return Manifest{}, err
+		}
+		manifest.Checksums[fileName] = checksum
+	}
+
+	_, err := writeJSON(filepath.Join(dir, ManifestFile), manifest)
+	if err != nil {
+		return Manifest{}, err
+	}
+	return manifest, nil
+}
+
+func writeJSON(fileName string, data interface{}) (string, error) {
+	content, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("cannot marshal %v: %v", filepath.Base(fileName), err)
+	}
+
+	err = ioutil.WriteFile(fileName, content, 0644)
+	if err != nil {
+		return "", fmt.Errorf("cannot write file: %v %v", fileName, err)
+	}
+	return checksum(content), nil
+}
+
+func checksum(content []byte) string {
+	sum := sha256.Sum256(content)
+	return hex.EncodeToString(sum[:])
+}
+
+// Read reads the backup in dir and verifies the checksums of its files against the manifest
+func Read(dir string) (*Snapshot, error) {
+	snapshot := Snapshot{Dir: dir}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, ManifestFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("cannot read manifest: %v", err)
+	}
+	if err == nil {
+		err = json.Unmarshal(data, &snapshot.Manifest)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse manifest: %v %v", filepath.Join(dir, ManifestFile), err)
+		}
+		if snapshot.Manifest.Version > FormatVersion {
+			return nil, fmt.Errorf("backup %v has format version %v, this daoctl reads up to version %v",
+				dir, snapshot.Manifest.Version, FormatVersion)
+		}
+	}
+
+	err = readJSON(dir, DocumentsFile, snapshot.Manifest, &snapshot.Documents)
+	if err != nil {
+		return nil, err
+	}
+	err = readJSON(dir, EdgesFile, snapshot.Manifest, &snapshot.Edges)
+	if err != nil {
+		return nil, err
+	}
+
+	if snapshot.Manifest.Version == 0 {
+		snapshot.Manifest.Documents = len(snapshot.Documents)
+		snapshot.Manifest.Edges = len(snapshot.Edges)
+	} else if snapshot.Manifest.Documents != len(snapshot.Documents) || snapshot.Manifest.Edges != len(snapshot.Edges) {
+		return nil, fmt.Errorf("backup %v holds %v documents and %v edges, but its manifest lists %v and %v",
+			dir, len(snapshot.Documents), len(snapshot.Edges), snapshot.Manifest.Documents, snapshot.Manifest.Edges)
+	}
+	return &snapshot, nil
+}
+
+func readJSON(dir, fileName string, manifest Manifest, v interface{}) error {
+	content, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+	if err != nil {
+		return fmt.Errorf("cannot read backup file: %v", err)
+	}
+
+	if manifest.Version > 0 {
+		if expected := manifest.Checksums[fileName]; checksum(content) != expected {
+			return fmt.Errorf("checksum of %v does not match the manifest, the backup is corrupt or was modified",
+				filepath.Join(dir, fileName))
+		}
+	}
+
+	err = json.Unmarshal(content, v)
+	if err != nil {
+		return fmt.Errorf("cannot parse backup file: %v %v", filepath.Join(dir, fileName), err)
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package backup
+
+import (
+	"crypto/sha256"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// testDocument returns a document whose hash is derived from the content, so that documents with
+// the same ID and different content have different hashes
+func testDocument(id uint64, content string) docgraph.Document {
+	hash := sha256.Sum256([]byte(content))
+	return docgraph.Document{
+		ID:      id,
+		Hash:    eos.Checksum256(hash[:]),
+		Creator: "dao.hypha",
+		ContentGroups: []docgraph.ContentGroup{{
+			{Label: "content", Value: &docgraph.FlexValue{BaseVariant: eos.BaseVariant{
+				TypeID: docgraph.GetVariants().TypeID("string"),
+				Impl:   content,
+			}}},
+		}},
+	}
+}
+
+func testEdge(id uint64, from, to docgraph.Document, name string) docgraph.Edge {
+	return docgraph.Edge{ID: id, FromNode: from.Hash, ToNode: to.Hash, EdgeName: eos.Name(name)}
+}
+
+func writeTestBackup(t *testing.T) (string, []docgraph.Document, []docgraph.Edge) {
+	t.Helper()
+	dho := testDocument(1, "dho")
+	role := testDocument(2, "role")
+	documents := []docgraph.Document{role, dho}
+	edges := []docgraph.Edge{testEdge(20, dho, role, "role"), testEdge(10, role, dho, "dao")}
+
+	dir := t.TempDir()
+	_, err := Write(dir, Manifest{
+		CreatedAt: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		ChainID:   "1eaa0824707c8c16bd25145493bf062aecddfeb56c736f6ba6397f3195f33c9f",
+		Contract:  "dao.hypha",
+		HeadBlock: 1000,
+	}, documents, edges)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir, documents, edges
+}
+
+func TestWriteRead(t *testing.T) {
+	dir, documents, _ := writeTestBackup(t)
+	hashes := make(map[uint64]string)
+	for _, document := range documents {
+		hashes[document.ID] = document.Hash.String()
+	}
+
+	snapshot, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	manifest := snapshot.Manifest
+	if manifest.Version != FormatVersion || manifest.Documents != 2 || manifest.Edges != 2 ||
+		manifest.Contract != "dao.hypha" || manifest.HeadBlock != 1000 {
+		t.Errorf("manifest: got %+v", manifest)
+	}
+	for _, fileName := range []string{DocumentsFile, EdgesFile} {
+		content, err := ioutil.ReadFile(filepath.Join(dir, fileName))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if manifest.Checksums[fileName] != checksum(content) {
+			t.Errorf("checksum of %v: got %v, want %v", fileName, manifest.Checksums[fileName], checksum(content))
+		}
+	}
+
+	// documents and edges are written in ID order
+	var documentIDs, edgeIDs []uint64
+	for _, document := range snapshot.Documents {
+		documentIDs = append(documentIDs, document.ID)
+		if document.Hash.String() != hashes[document.ID] {
+			t.Errorf("document %v: got hash %v, want %v", document.ID, document.Hash.String(), hashes[document.ID])
+		}
+	}
+	for _, edge := range snapshot.Edges {
+		edgeIDs = append(edgeIDs, edge.ID)
+	}
+	if !reflect.DeepEqual(documentIDs, []uint64{1, 2}) || !reflect.DeepEqual(edgeIDs, []uint64{10, 20}) {
+		t.Errorf("got document IDs %v and edge IDs %v, want [1 2] and [10 20]", documentIDs, edgeIDs)
+	}
+}
+
+func TestReadRejectsCorruptBackups(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(t *testing.T, dir string)
+		err     string
+	}{
+		{"modified documents", func(t *testing.T, dir string) {
+			replaceInFile(t, filepath.Join(dir, DocumentsFile), `"role"`, `"rule"`)
+		}, "checksum of"},
+		{"truncated edges", func(t *testing.T, dir string) {
+			content, _ := ioutil.ReadFile(filepath.Join(dir, EdgesFile))
+			ioutil.WriteFile(filepath.Join(dir, EdgesFile), content[:len(content)/2], 0644)
+		}, "checksum of"},
+		{"missing edges", func(t *testing.T, dir string) {
+			os.Remove(filepath.Join(dir, EdgesFile))
+		}, "cannot read backup file"},
+		{"counts that do not match", func(t *testing.T, dir string) {
+			replaceInFile(t, filepath.Join(dir, ManifestFile), `"documents": 2`, `"documents": 3`)
+		}, "its manifest lists 3 and 2"},
+		{"newer format", func(t *testing.T, dir string) {
+			replaceInFile(t, filepath.Join(dir, ManifestFile), `"version": 1`, `"version": 9`)
+		}, "has format version 9"},
+		{"unparsable manifest", func(t *testing.T, dir string) {
+			ioutil.WriteFile(filepath.Join(dir, ManifestFile), []byte("{"), 0644)
+		}, "cannot parse manifest"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir, _, _ := writeTestBackup(t)
+			test.corrupt(t, dir)
+
+			_, err := Read(dir)
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got error %v, want one containing %q", err, test.err)
+			}
+		})
+	}
+}
+
+func TestReadBackupWithoutManifest(t *testing.T) {
+	dir, _, _ := writeTestBackup(t)
+	os.Remove(filepath.Join(dir, ManifestFile))
+	// without a manifest there are no checksums to verify
+	replaceInFile(t, filepath.Join(dir, DocumentsFile), "  ", "    ")
+
+	snapshot, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if snapshot.Manifest.Version != 0 || snapshot.Manifest.Documents != 2 || snapshot.Manifest.Edges != 2 {
+		t.Errorf("manifest of a backup without one: got %+v", snapshot.Manifest)
+	}
+}
+
+func replaceInFile(t *testing.T, fileName, old, new string) {
+	t.Helper()
+	content, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(content), old) {
+		t.Fatalf("%v does not contain %v", fileName, old)
+	}
+	err = ioutil.WriteFile(fileName, []byte(strings.Replace(string(content), old, new, 1)), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	dho := testDocument(1, "dho")
+	role := testDocument(2, "role")
+	editedRole := testDocument(2, "role, edited")
+	assignment := testDocument(3, "assignment")
+	badge := testDocument(4, "badge")
+
+	a := &Snapshot{
+		Documents: []docgraph.Document{dho, role, assignment},
+		Edges: []docgraph.Edge{
+			testEdge(1, dho, role, "role"),
+			testEdge(2, dho, assignment, "assignment"),
+		},
+	}
+	b := &Snapshot{
+		Documents: []docgraph.Document{dho, editedRole, badge},
+		Edges: []docgraph.Edge{
+			// the same edge under a new ID is not a change
+			testEdge(7, dho, role, "role"),
+			testEdge(8, dho, badge, "badge"),
+		},
+	}
+
+	ids := func(documents []docgraph.Document) []uint64 {
+		var ids []uint64
+		for _, document := range documents {
+			ids = append(ids, document.ID)
+		}
+		return ids
+	}
+	edgeIDs := func(edges []docgraph.Edge) []uint64 {
+		var ids []uint64
+		for _, edge := range edges {
+			ids = append(ids, edge.ID)
+		}
+		return ids
+	}
+
+	diff := Compare(a, b)
+	if !reflect.DeepEqual(ids(diff.AddedDocuments), []uint64{4}) {
+		t.Errorf("added documents: got %v", ids(diff.AddedDocuments))
+	}
+	if !reflect.DeepEqual(ids(diff.RemovedDocuments), []uint64{3}) {
+		t.Errorf("removed documents: got %v", ids(diff.RemovedDocuments))
+	}
+	if len(diff.ChangedDocuments) != 1 || diff.ChangedDocuments[0].Before.Hash.String() != role.Hash.String() ||
+		diff.ChangedDocuments[0].After.Hash.String() != editedRole.Hash.String() {
+		t.Errorf("changed documents: got %+v", diff.ChangedDocuments)
+	}
+	if !reflect.DeepEqual(edgeIDs(diff.AddedEdges), []uint64{8}) {
+		t.Errorf("added edges: got %v", edgeIDs(diff.AddedEdges))
+	}
+	if !reflect.DeepEqual(edgeIDs(diff.RemovedEdges), []uint64{2}) {
+		t.Errorf("removed edges: got %v", edgeIDs(diff.RemovedEdges))
+	}
+	if diff.Empty() {
+		t.Error("diff with changes is empty")
+	}
+
+	if same := Compare(a, a); !same.Empty() {
+		t.Errorf("diff of a snapshot with itself: got %+v", same)
+	}
+}
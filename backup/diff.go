@@ -0,0 +1,81 @@
+package backup
+
+import (
+	"sort"
+
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// DocumentChange is a document whose ID is in both snapshots with different content
+type DocumentChange struct {
+	Before docgraph.Document
+	After  docgraph.Document
+}
+
+// Diff lists what changed between two snapshots. Documents are matched by ID, so a document
+// edited in place is changed; edges are matched by from node, to node and edge name.
+type Diff struct {
+	AddedDocuments   []docgraph.Document
+	RemovedDocuments []docgraph.Document
+	ChangedDocuments []DocumentChange
+	AddedEdges       []docgraph.Edge
+	RemovedEdges     []docgraph.Edge
+}
+
+// Empty reports whether the snapshots hold the same documents and edges
+func (d *Diff) Empty() bool {
+	return len(d.AddedDocuments) == 0 && len(d.RemovedDocuments) == 0 && len(d.ChangedDocuments) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0
+}
+
+// Compare computes the changes from snapshot a to snapshot b
+func Compare(a, b *Snapshot) Diff {
+	var diff Diff
+
+	before := make(map[uint64]docgraph.Document)
+	for _, document := range a.Documents {
+		before[document.ID] = document
+	}
+	after := make(map[uint64]docgraph.Document)
+	for _, document := range b.Documents {
+		after[document.ID] = document
+
+		previous, found := before[document.ID]
+		if !found {
+			diff.AddedDocuments = append(diff.AddedDocuments, document)
+		} else if previous.Hash.String() != document.Hash.String() {
+			diff.ChangedDocuments = append(diff.ChangedDocuments, DocumentChange{Before: previous, After: document})
+		}
+	}
+	for _, document := range a.Documents {
+		if _, found := after[document.ID]; !found {
+			diff.RemovedDocuments = append(diff.RemovedDocuments, document)
+		}
+	}
+
+	beforeEdges := make(map[string]bool)
+	for _, edge := range a.Edges {
+		beforeEdges[edgeKey(&edge)] = true
+	}
+	afterEdges := make(map[string]bool)
+	for _, edge := range b.Edges {
+		afterEdges[edgeKey(&edge)] = true
+		if !beforeEdges[edgeKey(&edge)] {
+			diff.AddedEdges = append(diff.AddedEdges, edge)
+		}
+	}
+	for _, edge := range a.Edges {
+		if !afterEdges[edgeKey(&edge)] {
+			diff.RemovedEdges = append(diff.RemovedEdges, edge)
+		}
+	}
+
+	sort.Slice(diff.ChangedDocuments, func(i, j int) bool {
+		return diff.ChangedDocuments[i].After.ID < diff.ChangedDocuments[j].After.ID
+	})
+	return diff
+}
+
+func edgeKey(edge *docgraph.Edge) string {
+	return edge.FromNode.String() + "/" + edge.ToNode.String() + "/" + string(edge.EdgeName)
+}
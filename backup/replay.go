@@ -0,0 +1,229 @@
+package backup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// CheckpointFile is kept in the backup folder while a replay is in progress
+const CheckpointFile = "replay-checkpoint.json"
+
+// Checkpoint records how many documents and edges of a backup were replayed onto an endpoint,
+// so that an interrupted replay resumes after them
+type Checkpoint struct {
+	Endpoint  string    `json:"endpoint"`
+	Documents int       `json:"documents"`
+	Edges     int       `json:"edges"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// ReadCheckpoint returns the replay checkpoint of the backup for the endpoint. A missing
+// checkpoint, or one for another endpoint, starts from the beginning.
+func ReadCheckpoint(dir, endpoint string) (Checkpoint, error) {
+	start := Checkpoint{Endpoint: endpoint}
+
+	data, err := ioutil.ReadFile(filepath.Join(dir, CheckpointFile))
+	if os.IsNotExist(err) {
+		return start, nil
+	}
+	if err != nil {
+		return start, fmt.Errorf("cannot read replay checkpoint: %v", err)
+	}
+
+	var checkpoint Checkpoint
+	err = json.Unmarshal(data, &checkpoint)
+	if err != nil {
+		return start, fmt.Errorf("cannot parse replay checkpoint: %v %v", filepath.Join(dir, CheckpointFile), err)
+	}
+	if checkpoint.Endpoint != endpoint {
+		return start, nil
+	}
+	return checkpoint, nil
+}
+
+// WriteCheckpoint saves the replay checkpoint in the backup folder
+func WriteCheckpoint(dir string, checkpoint Checkpoint) error {
+	checkpoint.UpdatedAt = time.Now().UTC()
+	_, err := writeJSON(filepath.Join(dir, CheckpointFile), checkpoint)
+	return err
+}
+
+// RemoveCheckpoint deletes the replay checkpoint once a replay completed
+func RemoveCheckpoint(dir string) error {
+	err := os.Remove(filepath.Join(dir, CheckpointFile))
+	if err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("cannot remove replay checkpoint: %v", err)
+	}
+	return nil
+}
+
+// ReplayOrder returns the documents of the snapshot in creation order and the edges that can be
+// recreated after them, also in creation order. Edges with a node that is not in the snapshot
+// cannot be recreated and are returned separately.
+func ReplayOrder(snapshot *Snapshot) ([]docgraph.Document, []docgraph.Edge, []docgraph.Edge) {
+	documents := make([]docgraph.Document, len(snapshot.Documents))
+	copy(documents, snapshot.Documents)
+	sort.Slice(documents, func(i, j int) bool { return documents[i].ID < documents[j].ID })
+
+	hashes := make(map[string]bool)
+	for _, document := range documents {
+		hashes[document.Hash.String()] = true
+	}
+
+	var edges, dangling []docgraph.Edge
+	for _, edge := range snapshot.Edges {
+		if hashes[edge.FromNode.String()] && hashes[edge.ToNode.String()] {
+			edges = append(edges, edge)
+		} else {
+			dangling = append(dangling, edge)
+		}
+	}
+	sort.Slice(edges, func(i, j int) bool { return edges[i].ID < edges[j].ID })
+	return documents, edges, dangling
+}
+
+// ReplayTarget is the chain a backup is replayed onto
+type ReplayTarget interface {
+	chain.ChainReader
+	// PushActions pushes the actions in one transaction
+	PushActions(ctx context.Context, actions []*eos.Action) error
+}
+
+// Replay recreates the documents and edges of a backup on a target chain, documents with the
+// contract's create action and edges with newedge, in transactions of BatchSize actions signed by
+// the Creator. The checkpoint is saved in Dir after each transaction.
+type Replay struct {
+	Dir       string
+	Target    ReplayTarget
+	Contract  eos.AccountName
+	Creator   eos.AccountName
+	BatchSize int
+	Out       io.Writer
+}
+
+type createDocument struct {
+	Creator       eos.AccountName         `json:"creator"`
+	ContentGroups []docgraph.ContentGroup `json:"content_groups"`
+}
+
+type newEdge struct {
+	Creator  eos.AccountName `json:"creator"`
+	FromNode eos.Checksum256 `json:"from_node"`
+	ToNode   eos.Checksum256 `json:"to_node"`
+	EdgeName eos.Name        `json:"edge_name"`
+}
+
+// Run replays the documents and edges, in the order of ReplayOrder, after those the checkpoint
+// counts. Edges refer to documents by hash, so before any edge is created every document must be
+// on the target under its hash in the backup.
+func (r *Replay) Run(ctx context.Context, documents []docgraph.Document, edges []docgraph.Edge, checkpoint Checkpoint) error {
+	for checkpoint.Documents < len(documents) {
+		end := r.batchEnd(checkpoint.Documents, len(documents))
+		var actions []*eos.Action
+		for _, document := range documents[checkpoint.Documents:end] {
+			actions = append(actions, r.action("create", createDocument{
+				Creator:       r.Creator,
+				ContentGroups: document.ContentGroups,
+			}))
+		}
+
+		err := r.push(ctx, actions, &checkpoint, end, checkpoint.Edges)
+		if err != nil {
+			return fmt.Errorf("cannot create documents %v to %v: %v", documents[checkpoint.Documents].ID, documents[end-1].ID, err)
+		}
+		fmt.Fprintf(r.Out, "Created documents %v/%v\n", checkpoint.Documents, len(documents))
+	}
+
+	err := VerifyDocuments(ctx, r.Target, r.Contract, documents)
+	if err != nil {
+		return err
+	}
+
+	for checkpoint.Edges < len(edges) {
+		end := r.batchEnd(checkpoint.Edges, len(edges))
+		var actions []*eos.Action
+		for _, edge := range edges[checkpoint.Edges:end] {
+			actions = append(actions, r.action("newedge", newEdge{
+				Creator:  r.Creator,
+				FromNode: edge.FromNode,
+				ToNode:   edge.ToNode,
+				EdgeName: edge.EdgeName,
+			}))
+		}
+
+		err := r.push(ctx, actions, &checkpoint, checkpoint.Documents, end)
+		if err != nil {
+			return fmt.Errorf("cannot create edges %v to %v: %v", edges[checkpoint.Edges].ID, edges[end-1].ID, err)
+		}
+		fmt.Fprintf(r.Out, "Created edges %v/%v\n", checkpoint.Edges, len(edges))
+	}
+	return RemoveCheckpoint(r.Dir)
+}
+
+func (r *Replay) batchEnd(start, total int) int {
+	if start+r.BatchSize > total {
+		return total
+	}
+	return start + r.BatchSize
+}
+
+func (r *Replay) action(name string, data interface{}) *eos.Action {
+	return &eos.Action{
+		Account:       r.Contract,
+		Name:          eos.ActN(name),
+		Authorization: []eos.PermissionLevel{{Actor: r.Creator, Permission: eos.PN("active")}},
+		ActionData:    eos.NewActionData(data),
+	}
+}
+
+// push pushes one transaction and, once it is accepted, advances and saves the checkpoint
+func (r *Replay) push(ctx context.Context, actions []*eos.Action, checkpoint *Checkpoint, documents, edges int) error {
+	err := r.Target.PushActions(ctx, actions)
+	if err != nil {
+		return err
+	}
+	checkpoint.Documents = documents
+	checkpoint.Edges = edges
+	return WriteCheckpoint(r.Dir, *checkpoint)
+}
+
+// VerifyDocuments returns an error listing the documents that are not on the target under their
+// hash, e.g. because the target contract hashes content differently
+func VerifyDocuments(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, documents []docgraph.Document) error {
+	recreated, err := reader.GetAllDocuments(ctx, contract)
+	if err != nil {
+		return fmt.Errorf("cannot read the recreated documents: %v", err)
+	}
+	hashes := make(map[string]bool)
+	for _, document := range recreated {
+		hashes[document.Hash.String()] = true
+	}
+
+	var missing []string
+	for _, document := range documents {
+		if !hashes[document.Hash.String()] {
+			missing = append(missing, fmt.Sprintf("%v %v", document.ID, document.Hash.String()))
+		}
+	}
+	count := len(missing)
+	if count == 0 {
+		return nil
+	}
+	if count > 5 {
+		missing = append(missing[:5], "...")
+	}
+	return fmt.Errorf("%v of %v documents are not on the target under their backup hash, so their edges cannot be replayed: %v",
+		count, len(documents), strings.Join(missing, ", "))
+}
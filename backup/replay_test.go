@@ -0,0 +1,226 @@
+package backup
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// fakeTarget is a chain that applies create and newedge actions to a fixture
+type fakeTarget struct {
+	*chain.FixtureReader
+	// hash returns the hash the contract gives to the content of a new document
+	hash         func(content []docgraph.ContentGroup) eos.Checksum256
+	transactions [][]*eos.Action
+	// failAt fails the transaction with this index, counting from 1
+	failAt int
+}
+
+func newFakeTarget() *fakeTarget {
+	return &fakeTarget{
+		FixtureReader: chain.NewFixtureReaderFromFixture(chain.NewFixture()),
+		hash: func(content []docgraph.ContentGroup) eos.Checksum256 {
+			// the hash of testDocument
+			hash := sha256.Sum256([]byte(content[0][0].Value.String()))
+			return eos.Checksum256(hash[:])
+		},
+	}
+}
+
+func (t *fakeTarget) PushActions(ctx context.Context, actions []*eos.Action) error {
+	if len(t.transactions)+1 == t.failAt {
+		return fmt.Errorf("transaction failed")
+	}
+	t.transactions = append(t.transactions, actions)
+
+	fixture := t.Fixture
+	for _, action := range actions {
+		switch data := action.ActionData.Data.(type) {
+		case createDocument:
+			fixture.Documents[action.Account] = append(fixture.Documents[action.Account], docgraph.Document{
+				ID:            uint64(len(fixture.Documents[action.Account]) + 1),
+				Hash:          t.hash(data.ContentGroups),
+				Creator:       data.Creator,
+				ContentGroups: data.ContentGroups,
+			})
+		case newEdge:
+			fixture.Edges[action.Account] = append(fixture.Edges[action.Account], docgraph.Edge{
+				ID:       uint64(len(fixture.Edges[action.Account]) + 1),
+				FromNode: data.FromNode,
+				ToNode:   data.ToNode,
+				EdgeName: data.EdgeName,
+				Creator:  eos.Name(data.Creator),
+			})
+		default:
+			return fmt.Errorf("unexpected action %v", action.Name)
+		}
+	}
+	return nil
+}
+
+// actionNames returns the action names of each transaction
+func (t *fakeTarget) actionNames() [][]string {
+	var names [][]string
+	for _, transaction := range t.transactions {
+		var batch []string
+		for _, action := range transaction {
+			batch = append(batch, string(action.Name))
+		}
+		names = append(names, batch)
+	}
+	return names
+}
+
+func testReplay(t *testing.T, target *fakeTarget) (*Replay, []docgraph.Document, []docgraph.Edge) {
+	t.Helper()
+	dir, _, _ := writeTestBackup(t)
+	snapshot, err := Read(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	documents, edges, _ := ReplayOrder(snapshot)
+	return &Replay{
+		Dir:       dir,
+		Target:    target,
+		Contract:  "dao.hypha",
+		Creator:   "replayer",
+		BatchSize: 1,
+		Out:       &bytes.Buffer{},
+	}, documents, edges
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+	target := newFakeTarget()
+	replay, documents, edges := testReplay(t, target)
+
+	err := replay.Run(ctx, documents, edges, Checkpoint{Endpoint: "http://localhost:8888"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := [][]string{{"create"}, {"create"}, {"newedge"}, {"newedge"}}
+	if got := target.actionNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got transactions %v, want %v", got, want)
+	}
+	recreated, _ := target.GetAllDocuments(ctx, "dao.hypha")
+	for i, document := range recreated {
+		if document.Hash.String() != documents[i].Hash.String() || document.Creator != "replayer" {
+			t.Errorf("document %v: got hash %v by %v, want %v", i, document.Hash.String(), document.Creator,
+				documents[i].Hash.String())
+		}
+	}
+	replayed, _ := target.GetAllEdges(ctx, "dao.hypha")
+	for i, edge := range replayed {
+		if edge.FromNode.String() != edges[i].FromNode.String() || edge.ToNode.String() != edges[i].ToNode.String() ||
+			edge.EdgeName != edges[i].EdgeName {
+			t.Errorf("edge %v: got %+v, want %+v", i, edge, edges[i])
+		}
+	}
+	if len(replayed) != len(edges) {
+		t.Errorf("got %v edges, want %v", len(replayed), len(edges))
+	}
+	out := replay.Out.(*bytes.Buffer).String()
+	if !strings.Contains(out, "Created documents 2/2\n") || !strings.Contains(out, "Created edges 2/2\n") {
+		t.Errorf("got output %q", out)
+	}
+
+	// a completed replay removes its checkpoint
+	checkpoint, err := ReadCheckpoint(replay.Dir, "http://localhost:8888")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Documents != 0 || checkpoint.Edges != 0 {
+		t.Errorf("got checkpoint %+v after a completed replay", checkpoint)
+	}
+}
+
+func TestReplayStopsWhenHashesDiffer(t *testing.T) {
+	ctx := context.Background()
+	target := newFakeTarget()
+	// a contract that hashes content differently gives every document a new hash
+	target.hash = func(content []docgraph.ContentGroup) eos.Checksum256 {
+		hash := sha256.Sum256([]byte("other " + content[0][0].Value.String()))
+		return eos.Checksum256(hash[:])
+	}
+	replay, documents, edges := testReplay(t, target)
+
+	err := replay.Run(ctx, documents, edges, Checkpoint{Endpoint: "http://localhost:8888"})
+	want := "2 of 2 documents are not on the target under their backup hash"
+	if err == nil || !strings.Contains(err.Error(), want) {
+		t.Fatalf("got error %v, want one containing %q", err, want)
+	}
+	if !strings.Contains(err.Error(), fmt.Sprintf("1 %v", documents[0].Hash.String())) {
+		t.Errorf("error does not list the first document: %v", err)
+	}
+	if got := target.actionNames(); !reflect.DeepEqual(got, [][]string{{"create"}, {"create"}}) {
+		t.Errorf("got transactions %v, want no newedge", got)
+	}
+
+	// the checkpoint is kept, so that a replay onto a fixed target does not create the documents again
+	checkpoint, err := ReadCheckpoint(replay.Dir, "http://localhost:8888")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Documents != 2 || checkpoint.Edges != 0 {
+		t.Errorf("got checkpoint %+v, want 2 documents and 0 edges", checkpoint)
+	}
+}
+
+func TestReplayResumesFromCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	target := newFakeTarget()
+	target.failAt = 3
+	replay, documents, edges := testReplay(t, target)
+
+	err := replay.Run(ctx, documents, edges, Checkpoint{Endpoint: "http://localhost:8888"})
+	if err == nil || !strings.Contains(err.Error(), "cannot create edges 10 to 10: transaction failed") {
+		t.Fatalf("got error %v", err)
+	}
+	checkpoint, err := ReadCheckpoint(replay.Dir, "http://localhost:8888")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if checkpoint.Documents != 2 || checkpoint.Edges != 0 {
+		t.Fatalf("got checkpoint %+v, want 2 documents and 0 edges", checkpoint)
+	}
+
+	target.failAt = 0
+	err = replay.Run(ctx, documents, edges, checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := [][]string{{"create"}, {"create"}, {"newedge"}, {"newedge"}}
+	if got := target.actionNames(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got transactions %v, want %v", got, want)
+	}
+}
+
+func TestVerifyDocuments(t *testing.T) {
+	ctx := context.Background()
+	target := newFakeTarget()
+	var documents []docgraph.Document
+	for i := 1; i <= 7; i++ {
+		documents = append(documents, testDocument(uint64(i), fmt.Sprintf("document %v", i)))
+	}
+	target.Fixture.Documents["dao.hypha"] = documents[:1]
+
+	err := VerifyDocuments(ctx, target, "dao.hypha", documents)
+	if err == nil || !strings.HasPrefix(err.Error(), "6 of 7 documents are not on the target") ||
+		!strings.HasSuffix(err.Error(), documents[5].Hash.String()+", ...") {
+		t.Errorf("got error %v", err)
+	}
+
+	target.Fixture.Documents["dao.hypha"] = documents
+	if err := VerifyDocuments(ctx, target, "dao.hypha", documents); err != nil {
+		t.Error(err)
+	}
+}
@@ -0,0 +1,152 @@
+package chain
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"go.uber.org/zap"
+)
+
+// Interaction is a single recorded HTTP request and the response the server returned
+type Interaction struct {
+	Method       string      `json:"method"`
+	URL          string      `json:"url"`
+	RequestBody  string      `json:"request_body,omitempty"`
+	StatusCode   int         `json:"status_code"`
+	Header       http.Header `json:"header,omitempty"`
+	ResponseBody string      `json:"response_body"`
+}
+
+// Cassette is an http.RoundTripper that either records every request to a directory
+// or replays previously recorded responses from it. Interactions are keyed by the
+// request method, path, query and body, so the same cassette can be replayed against
+// any endpoint.
+type Cassette struct {
+	Dir       string
+	Replaying bool
+	Transport http.RoundTripper
+}
+
+// NewRecordingCassette returns a cassette that sends requests through the transport
+// and writes each interaction to dir
+func NewRecordingCassette(dir string, transport http.RoundTripper) (*Cassette, error) {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	err := os.MkdirAll(dir, 0755)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cassette directory: %v %v", dir, err)
+	}
+	return &Cassette{Dir: dir, Transport: transport}, nil
+}
+
+// NewReplayingCassette returns a cassette that serves every request from the interactions
+// recorded in dir and never touches the network
+func NewReplayingCassette(dir string) (*Cassette, error) {
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("cannot open cassette directory: %v %v", dir, err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("cassette is not a directory: %v", dir)
+	}
+	return &Cassette{Dir: dir, Replaying: true}, nil
+}
+
+// RoundTrip records or replays a single request
+func (c *Cassette) RoundTrip(request *http.Request) (*http.Response, error) {
+	var requestBody []byte
+	if request.Body != nil {
+		var err error
+		requestBody, err = ioutil.ReadAll(request.Body)
+		if err != nil {
+			return nil, fmt.Errorf("cannot read request body: %v", err)
+		}
+		request.Body.Close()
+		request.Body = ioutil.NopCloser(bytes.NewReader(requestBody))
+	}
+
+	fileName := filepath.Join(c.Dir, interactionKey(request, requestBody)+".json")
+	if c.Replaying {
+		return c.replay(request, fileName)
+	}
+	return c.record(request, requestBody, fileName)
+}
+
+func (c *Cassette) replay(request *http.Request, fileName string) (*http.Response, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("no recorded response in cassette for %v %v", request.Method, request.URL.RequestURI())
+	}
+
+	var interaction Interaction
+	err = json.Unmarshal(data, &interaction)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal cassette interaction: %v %v", fileName, err)
+	}
+
+	zlog.Debug("replaying interaction", zap.String("method", request.Method), zap.String("uri", request.URL.RequestURI()))
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.StatusCode, http.StatusText(interaction.StatusCode)),
+		StatusCode:    interaction.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        interaction.Header,
+		Body:          ioutil.NopCloser(bytes.NewBufferString(interaction.ResponseBody)),
+		ContentLength: int64(len(interaction.ResponseBody)),
+		Request:       request,
+	}, nil
+}
+
+func (c *Cassette) record(request *http.Request, requestBody []byte, fileName string) (*http.Response, error) {
+	response, err := c.Transport.RoundTrip(request)
+	if err != nil {
+		return nil, err
+	}
+
+	responseBody, err := ioutil.ReadAll(response.Body)
+	response.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("cannot read response body: %v", err)
+	}
+	response.Body = ioutil.NopCloser(bytes.NewReader(responseBody))
+
+	interaction := Interaction{
+		Method:       request.Method,
+		URL:          request.URL.String(),
+		RequestBody:  string(requestBody),
+		StatusCode:   response.StatusCode,
+		Header:       response.Header,
+		ResponseBody: string(responseBody),
+	}
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal cassette interaction: %v", err)
+	}
+
+	err = ioutil.WriteFile(fileName, data, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("cannot write cassette interaction: %v %v", fileName, err)
+	}
+	zlog.Debug("recorded interaction", zap.String("method", request.Method), zap.String("uri", request.URL.RequestURI()))
+	return response, nil
+}
+
+func interactionKey(request *http.Request, body []byte) string {
+	hash := sha256.New()
+	hash.Write([]byte(request.Method))
+	hash.Write([]byte{0})
+	hash.Write([]byte(request.URL.RequestURI()))
+	hash.Write([]byte{0})
+	hash.Write(body)
+	return hex.EncodeToString(hash.Sum(nil))
+}
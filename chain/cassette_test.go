@@ -0,0 +1,112 @@
+package chain
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// cassetteDo sends a request through the cassette and returns the status and body of the response
+func cassetteDo(t *testing.T, client *http.Client, method, url, body string) (int, string, error) {
+	t.Helper()
+	request, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return 0, "", err
+	}
+	defer response.Body.Close()
+	data, err := ioutil.ReadAll(response.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return response.StatusCode, string(data), nil
+}
+
+func TestCassetteRecordReplay(t *testing.T) {
+	hits := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		body, _ := ioutil.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Path == "/v1/chain/missing" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+		w.Write([]byte(`{"path":"` + r.URL.RequestURI() + `","body":"` + string(body) + `"}`))
+	}))
+
+	requests := []struct {
+		method, path, body string
+		status             int
+		response           string
+	}{
+		{"POST", "/v1/chain/get_info", "", 200, `{"path":"/v1/chain/get_info","body":""}`},
+		{"POST", "/v1/chain/get_table_rows", "members", 200, `{"path":"/v1/chain/get_table_rows","body":"members"}`},
+		{"POST", "/v1/chain/get_table_rows", "voters", 200, `{"path":"/v1/chain/get_table_rows","body":"voters"}`},
+		{"GET", "/v2/history/get_deltas?code=dao.hypha", "", 200, `{"path":"/v2/history/get_deltas?code=dao.hypha","body":""}`},
+		{"POST", "/v1/chain/missing", "", 404, `{"path":"/v1/chain/missing","body":""}`},
+	}
+
+	dir := filepath.Join(t.TempDir(), "cassette")
+	recording, err := NewRecordingCassette(dir, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: recording}
+	for _, request := range requests {
+		status, body, err := cassetteDo(t, client, request.method, server.URL+request.path, request.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != request.status || body != request.response {
+			t.Errorf("recording %v %v: got %v %v", request.method, request.path, status, body)
+		}
+	}
+	server.Close()
+	if hits != len(requests) {
+		t.Fatalf("server got %v requests while recording, want %v", hits, len(requests))
+	}
+
+	replaying, err := NewReplayingCassette(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client = &http.Client{Transport: replaying}
+	// the server is gone, and replay matches requests on any endpoint
+	for _, request := range requests {
+		status, body, err := cassetteDo(t, client, request.method, "http://replay.invalid"+request.path, request.body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if status != request.status || body != request.response {
+			t.Errorf("replaying %v %v: got %v %v, want %v %v", request.method, request.path, status, body,
+				request.status, request.response)
+		}
+	}
+	if hits != len(requests) {
+		t.Errorf("server got %v requests, want none while replaying", hits-len(requests))
+	}
+
+	// a request that differs in its body or query was not recorded
+	for _, request := range []struct{ method, path, body string }{
+		{"POST", "/v1/chain/get_table_rows", "payments"},
+		{"GET", "/v2/history/get_deltas?code=dao.hypha&skip=100", ""},
+	} {
+		_, _, err := cassetteDo(t, client, request.method, "http://replay.invalid"+request.path, request.body)
+		want := "no recorded response in cassette for " + request.method + " " + request.path
+		if err == nil || !strings.Contains(err.Error(), want) {
+			t.Errorf("got error %v, want one containing %q", err, want)
+		}
+	}
+}
+
+func TestReplayingCassetteNeedsDirectory(t *testing.T) {
+	if _, err := NewReplayingCassette(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("expected an error for a missing cassette directory")
+	}
+}
@@ -0,0 +1,85 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dfuse-io/logging"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"go.uber.org/zap"
+)
+
+var zlog *zap.Logger
+
+func init() {
+	logging.Register("github.com/hypha-dao/daoctl/chain", &zlog)
+}
+
+// ChainReader is the read-only view of the blockchain used by the models, the graph
+// cache and the query commands. It is satisfied by a live node (NodeReader) and by a
+// recorded snapshot of chain state (FixtureReader).
+type ChainReader interface {
+	GetInfo(ctx context.Context) (*eos.InfoResp, error)
+	GetAccount(ctx context.Context, name eos.AccountName) (*eos.AccountResp, error)
+	GetCurrencyBalance(ctx context.Context, account eos.AccountName, symbol string, code eos.AccountName) ([]eos.Asset, error)
+	GetTableRows(ctx context.Context, request eos.GetTableRowsRequest) (*eos.GetTableRowsResp, error)
+	GetTableByScope(ctx context.Context, request eos.GetTableByScopeRequest) (*eos.GetTableByScopeResp, error)
+
+	GetAllDocuments(ctx context.Context, contract eos.AccountName) ([]docgraph.Document, error)
+	GetDocumentRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Document, bool, error)
+	GetLastDocument(ctx context.Context, contract eos.AccountName) (docgraph.Document, error)
+	LoadDocument(ctx context.Context, contract eos.AccountName, hash string) (docgraph.Document, error)
+	GetAllEdges(ctx context.Context, contract eos.AccountName) ([]docgraph.Edge, error)
+	GetEdgeRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Edge, bool, error)
+	GetEdgesFromDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error)
+	GetEdgesToDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error)
+}
+
+// GetEdgesFromDocumentWithEdge returns the edges from the document that have the provided edge name
+func GetEdgesFromDocumentWithEdge(ctx context.Context, reader ChainReader, contract eos.AccountName, document docgraph.Document, edgeName eos.Name) ([]docgraph.Edge, error) {
+	edges, err := reader.GetEdgesFromDocument(ctx, contract, document)
+	if err != nil {
+		return []docgraph.Edge{}, fmt.Errorf("cannot get edges from document: %v %v", document.Hash.String(), err)
+	}
+	return filterEdges(edges, edgeName), nil
+}
+
+// GetEdgesToDocumentWithEdge returns the edges to the document that have the provided edge name
+func GetEdgesToDocumentWithEdge(ctx context.Context, reader ChainReader, contract eos.AccountName, document docgraph.Document, edgeName eos.Name) ([]docgraph.Edge, error) {
+	edges, err := reader.GetEdgesToDocument(ctx, contract, document)
+	if err != nil {
+		return []docgraph.Edge{}, fmt.Errorf("cannot get edges to document: %v %v", document.Hash.String(), err)
+	}
+	return filterEdges(edges, edgeName), nil
+}
+
+// GetDocumentsOfType returns all documents with the provided type
+func GetDocumentsOfType(ctx context.Context, reader ChainReader, contract eos.AccountName, docType eos.Name) ([]docgraph.Document, error) {
+	docs, err := reader.GetAllDocuments(ctx, contract)
+	if err != nil {
+		return []docgraph.Document{}, fmt.Errorf("cannot get all documents: %v", err)
+	}
+
+	var filteredDocs []docgraph.Document
+	for _, doc := range docs {
+		typeFV, err := doc.GetContent("type")
+		if err != nil {
+			continue
+		}
+		if name, ok := typeFV.Impl.(eos.Name); ok && name == docType {
+			filteredDocs = append(filteredDocs, doc)
+		}
+	}
+	return filteredDocs, nil
+}
+
+func filterEdges(edges []docgraph.Edge, edgeName eos.Name) []docgraph.Edge {
+	var namedEdges []docgraph.Edge
+	for _, edge := range edges {
+		if edge.EdgeName == edgeName {
+			namedEdges = append(namedEdges, edge)
+		}
+	}
+	return namedEdges
+}
@@ -0,0 +1,257 @@
+package chain
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"sort"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"go.uber.org/zap"
+)
+
+// Fixture is a recorded snapshot of chain state that can be replayed without a node
+type Fixture struct {
+	Info      *eos.InfoResp                           `json:"info,omitempty"`
+	Accounts  map[eos.AccountName]*eos.AccountResp    `json:"accounts"`
+	Balances  []BalanceRecord                         `json:"balances"`
+	Tables    []TableRecord                           `json:"tables"`
+	Scopes    []ScopeRecord                           `json:"scopes"`
+	Documents map[eos.AccountName][]docgraph.Document `json:"documents"`
+	Edges     map[eos.AccountName][]docgraph.Edge     `json:"edges"`
+}
+
+// BalanceRecord is a recorded get_currency_balance response
+type BalanceRecord struct {
+	Account  eos.AccountName `json:"account"`
+	Symbol   string          `json:"symbol"`
+	Code     eos.AccountName `json:"code"`
+	Balances []eos.Asset     `json:"balances"`
+}
+
+// TableRecord is a recorded get_table_rows request and its response
+type TableRecord struct {
+	Request  eos.GetTableRowsRequest `json:"request"`
+	Response *eos.GetTableRowsResp   `json:"response"`
+}
+
+// ScopeRecord is a recorded get_table_by_scope request and its response
+type ScopeRecord struct {
+	Request  eos.GetTableByScopeRequest `json:"request"`
+	Response *eos.GetTableByScopeResp   `json:"response"`
+}
+
+// NewFixture returns an empty fixture
+func NewFixture() *Fixture {
+	return &Fixture{
+		Accounts:  make(map[eos.AccountName]*eos.AccountResp),
+		Documents: make(map[eos.AccountName][]docgraph.Document),
+		Edges:     make(map[eos.AccountName][]docgraph.Edge),
+	}
+}
+
+// LoadFixture reads a fixture from a JSON file
+func LoadFixture(fileName string) (*Fixture, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read fixture file: %v %v", fileName, err)
+	}
+
+	fixture := NewFixture()
+	err = json.Unmarshal(data, fixture)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal fixture file: %v %v", fileName, err)
+	}
+	return fixture, nil
+}
+
+// Save writes the fixture to a JSON file
+func (f *Fixture) Save(fileName string) error {
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal fixture to json: %v", err)
+	}
+
+	err = ioutil.WriteFile(fileName, data, 0644)
+	if err != nil {
+		return fmt.Errorf("cannot write fixture file: %v %v", fileName, err)
+	}
+	return nil
+}
+
+func tableKey(request interface{}) string {
+	key, _ := json.Marshal(request)
+	return string(key)
+}
+
+// FixtureReader serves chain state from a recorded Fixture
+type FixtureReader struct {
+	Fixture *Fixture
+	tables  map[string]*eos.GetTableRowsResp
+	scopes  map[string]*eos.GetTableByScopeResp
+}
+
+// NewFixtureReader loads the fixture file and returns a ChainReader that serves from it
+func NewFixtureReader(fileName string) (*FixtureReader, error) {
+	fixture, err := LoadFixture(fileName)
+	if err != nil {
+		return nil, err
+	}
+	zlog.Debug("loaded chain fixture", zap.String("file", fileName), zap.Int("tables", len(fixture.Tables)))
+	return NewFixtureReaderFromFixture(fixture), nil
+}
+
+// NewFixtureReaderFromFixture returns a ChainReader that serves from an in-memory fixture
+func NewFixtureReaderFromFixture(fixture *Fixture) *FixtureReader {
+	r := FixtureReader{
+		Fixture: fixture,
+		tables:  make(map[string]*eos.GetTableRowsResp),
+		scopes:  make(map[string]*eos.GetTableByScopeResp),
+	}
+	for _, record := range fixture.Tables {
+		r.tables[tableKey(record.Request)] = record.Response
+	}
+	for _, record := range fixture.Scopes {
+		r.scopes[tableKey(record.Request)] = record.Response
+	}
+	return &r
+}
+
+// GetInfo ...
+func (r *FixtureReader) GetInfo(ctx context.Context) (*eos.InfoResp, error) {
+	if r.Fixture.Info == nil {
+		return nil, fmt.Errorf("fixture does not contain chain info")
+	}
+	return r.Fixture.Info, nil
+}
+
+// GetAccount ...
+func (r *FixtureReader) GetAccount(ctx context.Context, name eos.AccountName) (*eos.AccountResp, error) {
+	account, found := r.Fixture.Accounts[name]
+	if !found {
+		return nil, fmt.Errorf("account not found in fixture: %v", name)
+	}
+	return account, nil
+}
+
+// GetCurrencyBalance ...
+func (r *FixtureReader) GetCurrencyBalance(ctx context.Context, account eos.AccountName, symbol string, code eos.AccountName) ([]eos.Asset, error) {
+	for _, balance := range r.Fixture.Balances {
+		if balance.Account == account && balance.Symbol == symbol && balance.Code == code {
+			return balance.Balances, nil
+		}
+	}
+	return nil, fmt.Errorf("currency balance not found in fixture: account: %v symbol: %v code: %v", account, symbol, code)
+}
+
+// GetTableRows ...
+func (r *FixtureReader) GetTableRows(ctx context.Context, request eos.GetTableRowsRequest) (*eos.GetTableRowsResp, error) {
+	response, found := r.tables[tableKey(request)]
+	if !found {
+		return nil, fmt.Errorf("table rows not found in fixture: code: %v scope: %v table: %v", request.Code, request.Scope, request.Table)
+	}
+	return response, nil
+}
+
+// GetTableByScope ...
+func (r *FixtureReader) GetTableByScope(ctx context.Context, request eos.GetTableByScopeRequest) (*eos.GetTableByScopeResp, error) {
+	response, found := r.scopes[tableKey(request)]
+	if !found {
+		return nil, fmt.Errorf("table scopes not found in fixture: code: %v table: %v", request.Code, request.Table)
+	}
+	return response, nil
+}
+
+// GetAllDocuments ...
+func (r *FixtureReader) GetAllDocuments(ctx context.Context, contract eos.AccountName) ([]docgraph.Document, error) {
+	documents := append([]docgraph.Document{}, r.Fixture.Documents[contract]...)
+	sort.SliceStable(documents, func(i, j int) bool {
+		return documents[i].ID < documents[j].ID
+	})
+	return documents, nil
+}
+
+// GetDocumentRange ...
+func (r *FixtureReader) GetDocumentRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Document, bool, error) {
+	documents, _ := r.GetAllDocuments(ctx, contract)
+	start := sort.Search(len(documents), func(i int) bool {
+		return documents[i].ID >= lowerBound
+	})
+	documents = documents[start:]
+	if len(documents) > limit {
+		return documents[:limit], true, nil
+	}
+	return documents, false, nil
+}
+
+// GetLastDocument ...
+func (r *FixtureReader) GetLastDocument(ctx context.Context, contract eos.AccountName) (docgraph.Document, error) {
+	documents := r.Fixture.Documents[contract]
+	if len(documents) == 0 {
+		return docgraph.Document{}, fmt.Errorf("fixture does not contain documents for contract: %v", contract)
+	}
+
+	last := documents[0]
+	for _, document := range documents {
+		if document.ID > last.ID {
+			last = document
+		}
+	}
+	return last, nil
+}
+
+// LoadDocument ...
+func (r *FixtureReader) LoadDocument(ctx context.Context, contract eos.AccountName, hash string) (docgraph.Document, error) {
+	for _, document := range r.Fixture.Documents[contract] {
+		if document.Hash.String() == hash {
+			return document, nil
+		}
+	}
+	return docgraph.Document{}, fmt.Errorf("document not found in fixture: %v", hash)
+}
+
+// GetAllEdges ...
+func (r *FixtureReader) GetAllEdges(ctx context.Context, contract eos.AccountName) ([]docgraph.Edge, error) {
+	edges := append([]docgraph.Edge{}, r.Fixture.Edges[contract]...)
+	sort.SliceStable(edges, func(i, j int) bool {
+		return edges[i].ID < edges[j].ID
+	})
+	return edges, nil
+}
+
+// GetEdgeRange ...
+func (r *FixtureReader) GetEdgeRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Edge, bool, error) {
+	edges, _ := r.GetAllEdges(ctx, contract)
+	start := sort.Search(len(edges), func(i int) bool {
+		return edges[i].ID >= lowerBound
+	})
+	edges = edges[start:]
+	if len(edges) > limit {
+		return edges[:limit], true, nil
+	}
+	return edges, false, nil
+}
+
+// GetEdgesFromDocument ...
+func (r *FixtureReader) GetEdgesFromDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	var edges []docgraph.Edge
+	for _, edge := range r.Fixture.Edges[contract] {
+		if edge.FromNode.String() == document.Hash.String() {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
+
+// GetEdgesToDocument ...
+func (r *FixtureReader) GetEdgesToDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	var edges []docgraph.Edge
+	for _, edge := range r.Fixture.Edges[contract] {
+		if edge.ToNode.String() == document.Hash.String() {
+			edges = append(edges, edge)
+		}
+	}
+	return edges, nil
+}
@@ -0,0 +1,118 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// NodeReader reads chain state from a live nodeos API endpoint
+type NodeReader struct {
+	API *eos.API
+}
+
+// NewNodeReader returns a ChainReader backed by the provided API
+func NewNodeReader(api *eos.API) *NodeReader {
+	return &NodeReader{API: api}
+}
+
+// GetInfo ...
+func (n *NodeReader) GetInfo(ctx context.Context) (*eos.InfoResp, error) {
+	return n.API.GetInfo(ctx)
+}
+
+// GetAccount ...
+func (n *NodeReader) GetAccount(ctx context.Context, name eos.AccountName) (*eos.AccountResp, error) {
+	return n.API.GetAccount(ctx, name)
+}
+
+// GetCurrencyBalance ...
+func (n *NodeReader) GetCurrencyBalance(ctx context.Context, account eos.AccountName, symbol string, code eos.AccountName) ([]eos.Asset, error) {
+	return n.API.GetCurrencyBalance(ctx, account, symbol, code)
+}
+
+// GetTableRows ...
+func (n *NodeReader) GetTableRows(ctx context.Context, request eos.GetTableRowsRequest) (*eos.GetTableRowsResp, error) {
+	return n.API.GetTableRows(ctx, request)
+}
+
+// GetTableByScope ...
+func (n *NodeReader) GetTableByScope(ctx context.Context, request eos.GetTableByScopeRequest) (*eos.GetTableByScopeResp, error) {
+	return n.API.GetTableByScope(ctx, request)
+}
+
+// GetAllDocuments ...
+func (n *NodeReader) GetAllDocuments(ctx context.Context, contract eos.AccountName) ([]docgraph.Document, error) {
+	return docgraph.GetAllDocuments(ctx, n.API, contract)
+}
+
+// GetDocumentRange returns up to limit documents with an ID of at least lowerBound, and whether more remain
+func (n *NodeReader) GetDocumentRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Document, bool, error) {
+	var documents []docgraph.Document
+	response, err := n.API.GetTableRows(ctx, rangeRequest(contract, "documents", lowerBound, limit))
+	if err != nil {
+		return []docgraph.Document{}, false, fmt.Errorf("cannot get document range: %v", err)
+	}
+
+	err = response.JSONToStructs(&documents)
+	if err != nil {
+		return []docgraph.Document{}, false, fmt.Errorf("cannot unmarshal document range: %v", err)
+	}
+	return documents, response.More, nil
+}
+
+// GetLastDocument ...
+func (n *NodeReader) GetLastDocument(ctx context.Context, contract eos.AccountName) (docgraph.Document, error) {
+	return docgraph.GetLastDocument(ctx, n.API, contract)
+}
+
+// LoadDocument ...
+func (n *NodeReader) LoadDocument(ctx context.Context, contract eos.AccountName, hash string) (docgraph.Document, error) {
+	return docgraph.LoadDocument(ctx, n.API, contract, hash)
+}
+
+// GetAllEdges ...
+func (n *NodeReader) GetAllEdges(ctx context.Context, contract eos.AccountName) ([]docgraph.Edge, error) {
+	return docgraph.GetAllEdges(ctx, n.API, contract)
+}
+
+// GetEdgeRange returns up to limit edges with an ID of at least lowerBound, and whether more remain
+func (n *NodeReader) GetEdgeRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Edge, bool, error) {
+	var edges []docgraph.Edge
+	response, err := n.API.GetTableRows(ctx, rangeRequest(contract, "edges", lowerBound, limit))
+	if err != nil {
+		return []docgraph.Edge{}, false, fmt.Errorf("cannot get edge range: %v", err)
+	}
+
+	err = response.JSONToStructs(&edges)
+	if err != nil {
+		return []docgraph.Edge{}, false, fmt.Errorf("cannot unmarshal edge range: %v", err)
+	}
+	return edges, response.More, nil
+}
+
+// GetEdgesFromDocument ...
+func (n *NodeReader) GetEdgesFromDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	return docgraph.GetEdgesFromDocument(ctx, n.API, contract, document)
+}
+
+// GetEdgesToDocument ...
+func (n *NodeReader) GetEdgesToDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	return docgraph.GetEdgesToDocument(ctx, n.API, contract, document)
+}
+
+func rangeRequest(contract eos.AccountName, table string, lowerBound uint64, limit int) eos.GetTableRowsRequest {
+	var request eos.GetTableRowsRequest
+	if lowerBound > 0 {
+		request.LowerBound = strconv.FormatUint(lowerBound, 10)
+	}
+	request.Code = string(contract)
+	request.Scope = string(contract)
+	request.Table = table
+	request.Limit = uint32(limit)
+	request.JSON = true
+	return request
+}
@@ -0,0 +1,220 @@
+package chain
+
+import (
+	"context"
+	"sync"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Recorder is a ChainReader that passes reads through to another reader and keeps
+// every response in a Fixture, so that the same reads can later be replayed offline.
+// A read repeated with the same request replaces the earlier response.
+type Recorder struct {
+	Reader  ChainReader
+	Fixture *Fixture
+	lock    sync.Mutex
+
+	balances map[string]int
+	tables   map[string]int
+	scopes   map[string]int
+}
+
+// NewRecorder returns a Recorder that wraps the provided reader
+func NewRecorder(reader ChainReader) *Recorder {
+	return &Recorder{
+		Reader:   reader,
+		Fixture:  NewFixture(),
+		balances: make(map[string]int),
+		tables:   make(map[string]int),
+		scopes:   make(map[string]int),
+	}
+}
+
+// Save writes the recorded fixture to a JSON file
+func (r *Recorder) Save(fileName string) error {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	return r.Fixture.Save(fileName)
+}
+
+func (r *Recorder) addDocuments(contract eos.AccountName, documents ...docgraph.Document) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	known := make(map[string]bool)
+	for _, document := range r.Fixture.Documents[contract] {
+		known[document.Hash.String()] = true
+	}
+	for _, document := range documents {
+		if !known[document.Hash.String()] {
+			r.Fixture.Documents[contract] = append(r.Fixture.Documents[contract], document)
+			known[document.Hash.String()] = true
+		}
+	}
+}
+
+func (r *Recorder) addEdges(contract eos.AccountName, edges ...docgraph.Edge) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	known := make(map[uint64]bool)
+	for _, edge := range r.Fixture.Edges[contract] {
+		known[edge.ID] = true
+	}
+	for _, edge := range edges {
+		if !known[edge.ID] {
+			r.Fixture.Edges[contract] = append(r.Fixture.Edges[contract], edge)
+			known[edge.ID] = true
+		}
+	}
+}
+
+// GetInfo ...
+func (r *Recorder) GetInfo(ctx context.Context) (*eos.InfoResp, error) {
+	info, err := r.Reader.GetInfo(ctx)
+	if err == nil {
+		r.lock.Lock()
+		r.Fixture.Info = info
+		r.lock.Unlock()
+	}
+	return info, err
+}
+
+// GetAccount ...
+func (r *Recorder) GetAccount(ctx context.Context, name eos.AccountName) (*eos.AccountResp, error) {
+	account, err := r.Reader.GetAccount(ctx, name)
+	if err == nil {
+		r.lock.Lock()
+		r.Fixture.Accounts[name] = account
+		r.lock.Unlock()
+	}
+	return account, err
+}
+
+// GetCurrencyBalance ...
+func (r *Recorder) GetCurrencyBalance(ctx context.Context, account eos.AccountName, symbol string, code eos.AccountName) ([]eos.Asset, error) {
+	balances, err := r.Reader.GetCurrencyBalance(ctx, account, symbol, code)
+	if err == nil {
+		record := BalanceRecord{Account: account, Symbol: symbol, Code: code, Balances: balances}
+		key := tableKey([]interface{}{account, symbol, code})
+		r.lock.Lock()
+		if i, found := r.balances[key]; found {
+			r.Fixture.Balances[i] = record
+		} else {
+			r.balances[key] = len(r.Fixture.Balances)
+			r.Fixture.Balances = append(r.Fixture.Balances, record)
+		}
+		r.lock.Unlock()
+	}
+	return balances, err
+}
+
+// GetTableRows ...
+func (r *Recorder) GetTableRows(ctx context.Context, request eos.GetTableRowsRequest) (*eos.GetTableRowsResp, error) {
+	response, err := r.Reader.GetTableRows(ctx, request)
+	if err == nil {
+		record := TableRecord{Request: request, Response: response}
+		key := tableKey(request)
+		r.lock.Lock()
+		if i, found := r.tables[key]; found {
+			r.Fixture.Tables[i] = record
+		} else {
+			r.tables[key] = len(r.Fixture.Tables)
+			r.Fixture.Tables = append(r.Fixture.Tables, record)
+		}
+		r.lock.Unlock()
+	}
+	return response, err
+}
+
+// GetTableByScope ...
+func (r *Recorder) GetTableByScope(ctx context.Context, request eos.GetTableByScopeRequest) (*eos.GetTableByScopeResp, error) {
+	response, err := r.Reader.GetTableByScope(ctx, request)
+	if err == nil {
+		record := ScopeRecord{Request: request, Response: response}
+		key := tableKey(request)
+		r.lock.Lock()
+		if i, found := r.scopes[key]; found {
+			r.Fixture.Scopes[i] = record
+		} else {
+			r.scopes[key] = len(r.Fixture.Scopes)
+			r.Fixture.Scopes = append(r.Fixture.Scopes, record)
+		}
+		r.lock.Unlock()
+	}
+	return response, err
+}
+
+// GetAllDocuments ...
+func (r *Recorder) GetAllDocuments(ctx context.Context, contract eos.AccountName) ([]docgraph.Document, error) {
+	documents, err := r.Reader.GetAllDocuments(ctx, contract)
+	if err == nil {
+		r.addDocuments(contract, documents...)
+	}
+	return documents, err
+}
+
+// GetDocumentRange ...
+func (r *Recorder) GetDocumentRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Document, bool, error) {
+	documents, more, err := r.Reader.GetDocumentRange(ctx, contract, lowerBound, limit)
+	if err == nil {
+		r.addDocuments(contract, documents...)
+	}
+	return documents, more, err
+}
+
+// GetLastDocument ...
+func (r *Recorder) GetLastDocument(ctx context.Context, contract eos.AccountName) (docgraph.Document, error) {
+	document, err := r.Reader.GetLastDocument(ctx, contract)
+	if err == nil {
+		r.addDocuments(contract, document)
+	}
+	return document, err
+}
+
+// LoadDocument ...
+func (r *Recorder) LoadDocument(ctx context.Context, contract eos.AccountName, hash string) (docgraph.Document, error) {
+	document, err := r.Reader.LoadDocument(ctx, contract, hash)
+	if err == nil {
+		r.addDocuments(contract, document)
+	}
+	return document, err
+}
+
+// GetAllEdges ...
+func (r *Recorder) GetAllEdges(ctx context.Context, contract eos.AccountName) ([]docgraph.Edge, error) {
+	edges, err := r.Reader.GetAllEdges(ctx, contract)
+	if err == nil {
+		r.addEdges(contract, edges...)
+	}
+	return edges, err
+}
+
+// GetEdgeRange ...
+func (r *Recorder) GetEdgeRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Edge, bool, error) {
+	edges, more, err := r.Reader.GetEdgeRange(ctx, contract, lowerBound, limit)
+	if err == nil {
+		r.addEdges(contract, edges...)
+	}
+	return edges, more, err
+}
+
+// GetEdgesFromDocument ...
+func (r *Recorder) GetEdgesFromDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	edges, err := r.Reader.GetEdgesFromDocument(ctx, contract, document)
+	if err == nil {
+		r.addEdges(contract, edges...)
+	}
+	return edges, err
+}
+
+// GetEdgesToDocument ...
+func (r *Recorder) GetEdgesToDocument(ctx context.Context, contract eos.AccountName, document docgraph.Document) ([]docgraph.Edge, error) {
+	edges, err := r.Reader.GetEdgesToDocument(ctx, contract, document)
+	if err == nil {
+		r.addEdges(contract, edges...)
+	}
+	return edges, err
+}
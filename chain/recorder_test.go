@@ -0,0 +1,67 @@
+package chain
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+func TestRecorderDedupesRepeatedReads(t *testing.T) {
+	ctx := context.Background()
+	source := NewFixture()
+	members := eos.GetTableRowsRequest{Code: "dao.hypha", Scope: "dao.hypha", Table: "members", Limit: 1000, JSON: true}
+	voters := eos.GetTableRowsRequest{Code: "trailservice", Scope: "alice", Table: "voters", Limit: 1, JSON: true}
+	source.Tables = []TableRecord{
+		{Request: members, Response: &eos.GetTableRowsResp{Rows: []byte(`[{"member":"alice"}]`)}},
+		{Request: voters, Response: &eos.GetTableRowsResp{Rows: []byte(`[{"liquid":"300.00 HVOICE"}]`)}},
+	}
+	scope := eos.GetTableByScopeRequest{Code: "trailservice", Table: "voters"}
+	source.Scopes = []ScopeRecord{{Request: scope, Response: &eos.GetTableByScopeResp{}}}
+	source.Balances = []BalanceRecord{{Account: "alice", Symbol: "HYPHA", Code: "token.hypha", Balances: []eos.Asset{}}}
+
+	recorder := NewRecorder(NewFixtureReaderFromFixture(source))
+	for i := 0; i < 3; i++ {
+		for _, request := range []eos.GetTableRowsRequest{members, voters} {
+			if _, err := recorder.GetTableRows(ctx, request); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if _, err := recorder.GetTableByScope(ctx, scope); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := recorder.GetCurrencyBalance(ctx, "alice", "HYPHA", "token.hypha"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	fixture := recorder.Fixture
+	if len(fixture.Tables) != 2 || len(fixture.Scopes) != 1 || len(fixture.Balances) != 1 {
+		t.Fatalf("got %v tables, %v scopes and %v balances, want 2, 1 and 1",
+			len(fixture.Tables), len(fixture.Scopes), len(fixture.Balances))
+	}
+
+	// the recorded fixture replays the same reads
+	fileName := filepath.Join(t.TempDir(), "fixture.json")
+	if err := recorder.Save(fileName); err != nil {
+		t.Fatal(err)
+	}
+	replay, err := NewFixtureReader(fileName)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response, err := replay.GetTableRows(ctx, voters)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var rows []struct {
+		Liquid eos.Asset `json:"liquid"`
+	}
+	if err := response.JSONToStructs(&rows); err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 1 || rows[0].Liquid.String() != "300.00 HVOICE" {
+		t.Errorf("replayed rows: got %s", response.Rows)
+	}
+}
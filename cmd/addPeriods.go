@@ -8,6 +8,7 @@ import (
 	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/models"
 	"github.com/hypha-dao/document-graph/docgraph"
 
@@ -72,7 +73,7 @@ var addPeriodsCmd = &cobra.Command{
 		ctx := context.Background()
 		api := getAPI()
 		contract := eos.AN(viper.GetString("DAOContract"))
-		period, err := getLastPeriod(ctx, api, contract)
+		period, err := getLastPeriod(ctx, getReader(), contract)
 
 		keyBag := &eos.KeyBag{}
 		keyBag.ImportPrivateKey(context.Background(), "5KFCMj1ewfRYPhP7kCp9S6FpHKheRBS9sZLLNTqZu3WHbQiVG9s")
@@ -176,7 +177,7 @@ func init() {
 	addPeriodsCmd.Flags().IntP("period-count", "p", 0, "the number of periods to add from the moonphases table")
 }
 
-func getLastPeriod(ctx context.Context, api *eos.API, contract eos.AccountName) (models.Period, error) {
+func getLastPeriod(ctx context.Context, reader chain.ChainReader, contract eos.AccountName) (models.Period, error) {
 	// rootDocument, err := docgraph.LoadDocument(ctx, api, contract, viper.GetString("RootNode"))
 	// if err != nil {
 	// 	return models.Period{}, fmt.Errorf("cannot load root document: %v", err)
@@ -191,12 +192,12 @@ func getLastPeriod(ctx context.Context, api *eos.API, contract eos.AccountName)
 	// 	return models.Period{}, fmt.Errorf("no start edge from the root node exists: %v", err)
 	// }
 
-	startPeriodDoc, err := docgraph.LoadDocument(ctx, api, contract, viper.GetString("CalendarStart")) //startEdges[0].ToNode.String())
+	startPeriodDoc, err := reader.LoadDocument(ctx, contract, viper.GetString("CalendarStart")) //startEdges[0].ToNode.String())
 	if err != nil {
 		return models.Period{}, fmt.Errorf("error loading the start period document: %v", err)
 	}
 
-	period, err := models.NewPeriod(ctx, api, contract, startPeriodDoc)
+	period, err := models.NewPeriod(ctx, reader, contract, startPeriodDoc)
 	if err != nil {
 		return models.Period{}, fmt.Errorf("cannot convert document to period type: %v", err)
 	}
@@ -2,14 +2,13 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
+	"path/filepath"
 	"time"
 
 	"github.com/eoscanada/eos-go"
-	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/hypha-dao/daoctl/backup"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,51 +16,51 @@ import (
 var backupCmd = &cobra.Command{
 	Use:   "backup",
 	Short: "creates a local backup of the environment, including all documents and edges",
-	Long:  "creates a local backup of the environment, including all documents and edges",
-	// Args:  cobra.RangeArgs(1, 1),
+	Long: `creates a local backup of the environment, including all documents and edges
+
+The backup folder holds documents.json, edges.json and a manifest.json with the chain ID, contract,
+head block, counts and file checksums. Use 'backup diff' to compare two backups and 'backup load'
+to seed the local graph cache from one.`,
+	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := getAPI()
+		reader := getReader()
 		ctx := context.Background()
 		contract := eos.AN(viper.GetString("DAOContract"))
 
-		folderName := viper.GetString("backup-cmd-output-dir") + "/dao-backup-" + time.Now().Format("2006Jan02-150405")
+		folderName := filepath.Join(viper.GetString("backup-cmd-output-dir"), "dao-backup-"+time.Now().Format("2006Jan02-150405"))
 
 		err := os.Mkdir(folderName, 0777)
 		if err != nil {
-			fmt.Println("Unable to create folder: ", folderName)
-			panic(err)
+			return fmt.Errorf("cannot create backup folder: %v", err)
 		}
 		fmt.Println("\nBacking up to folder: ", folderName)
 
-		documents, err := docgraph.GetAllDocuments(ctx, api, contract)
-		if err != nil {
-			return fmt.Errorf("cannot get all documents: %v", err)
-		}
-
-		documentsJson, err := json.MarshalIndent(documents, "", "  ")
+		info, err := reader.GetInfo(ctx)
 		if err != nil {
-			return fmt.Errorf("cannot marshal documents to json: %v", err)
+			return fmt.Errorf("cannot get chain info: %v", err)
 		}
 
-		err = ioutil.WriteFile("documents.json", documentsJson, 0644)
+		documents, err := reader.GetAllDocuments(ctx, contract)
 		if err != nil {
-			return fmt.Errorf("cannot documents to documents.json file: %v", err)
+			return fmt.Errorf("cannot get all documents: %v", err)
 		}
 
-		edges, err := docgraph.GetAllEdges(ctx, api, contract)
+		edges, err := reader.GetAllEdges(ctx, contract)
 		if err != nil {
 			return fmt.Errorf("cannot get all edges: %v", err)
 		}
 
-		edgesJson, err := json.MarshalIndent(edges, "", "  ")
+		manifest, err := backup.Write(folderName, backup.Manifest{
+			CreatedAt: time.Now().UTC(),
+			ChainID:   info.ChainID.String(),
+			Contract:  string(contract),
+			HeadBlock: info.HeadBlockNum,
+		}, documents, edges)
 		if err != nil {
-			return fmt.Errorf("cannot marshal edges to json: %v", err)
+			return fmt.Errorf("cannot write backup: %v", err)
 		}
 
-		err = ioutil.WriteFile("edges.json", edgesJson, 0644)
-		if err != nil {
-			return fmt.Errorf("cannot edges to edges.json file: %v", err)
-		}
+		fmt.Printf("Backed up %v documents and %v edges at block %v\n\n", manifest.Documents, manifest.Edges, manifest.HeadBlock)
 		return nil
 	},
 }
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/backup"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/spf13/cobra"
+)
+
+var backupDiffCmd = &cobra.Command{
+	Use:   "diff <backup-a> <backup-b>",
+	Short: "report the documents and edges that changed between two backups",
+	Long: `report the documents and edges that changed between two backups
+
+Documents are matched by ID, so a document edited in place is reported as changed. Edges are
+matched by from node, to node and edge name.`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		a, err := backup.Read(args[0])
+		if err != nil {
+			return err
+		}
+		b, err := backup.Read(args[1])
+		if err != nil {
+			return err
+		}
+
+		if a.Manifest.ChainID != "" && b.Manifest.ChainID != "" && a.Manifest.ChainID != b.Manifest.ChainID {
+			fmt.Printf("\nWARNING: the backups are from different chains: %v and %v\n", a.Manifest.ChainID, b.Manifest.ChainID)
+		}
+		if a.Manifest.Contract != "" && b.Manifest.Contract != "" && a.Manifest.Contract != b.Manifest.Contract {
+			fmt.Printf("\nWARNING: the backups are of different contracts: %v and %v\n", a.Manifest.Contract, b.Manifest.Contract)
+		}
+
+		diff := backup.Compare(a, b)
+		if diff.Empty() {
+			fmt.Println("\nThe backups hold the same documents and edges")
+			fmt.Println()
+			return nil
+		}
+
+		printDiffTable := func(title string, count int, table *simpletable.Table) {
+			if count == 0 {
+				return
+			}
+			fmt.Printf("\n%v: %v\n", title, count)
+			table.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println("\n" + table.String() + "\n")
+		}
+
+		printDiffTable("Added documents", len(diff.AddedDocuments), views.DocTable(diff.AddedDocuments))
+		printDiffTable("Removed documents", len(diff.RemovedDocuments), views.DocTable(diff.RemovedDocuments))
+		printDiffTable("Changed documents", len(diff.ChangedDocuments), views.DocumentChangeTable(diff.ChangedDocuments))
+		printDiffTable("Added edges", len(diff.AddedEdges), views.EdgeTable(diff.AddedEdges, false, false))
+		printDiffTable("Removed edges", len(diff.RemovedEdges), views.EdgeTable(diff.RemovedEdges, false, false))
+
+		fmt.Printf("Documents: %v added, %v removed, %v changed; edges: %v added, %v removed\n\n",
+			len(diff.AddedDocuments), len(diff.RemovedDocuments), len(diff.ChangedDocuments), len(diff.AddedEdges), len(diff.RemovedEdges))
+		return nil
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupDiffCmd)
+}
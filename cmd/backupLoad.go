@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hypha-dao/daoctl/backup"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var backupLoadCmd = &cobra.Command{
+	Use:   "load <backup>",
+	Short: "seed the local graph cache from a backup",
+	Long: `seed the local graph cache from a backup
+
+The cache is cleared and filled with the documents and edges of the backup, and the backup's last
+document ID becomes the sync watermark. The next sync fetches only the documents created after the
+backup, with their edges; erasures and edges added between older documents are picked up by
+'cache sync', or by the first sync once GraphCacheReconcileInterval has passed since the load.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		snapshot, err := backup.Read(args[0])
+		if err != nil {
+			return err
+		}
+
+		manifest := snapshot.Manifest
+		force := viper.GetBool("backup-load-cmd-force")
+		if !force && manifest.Contract != "" && manifest.Contract != viper.GetString("DAOContract") {
+			return fmt.Errorf("backup is of contract %v, but DAOContract is %v", manifest.Contract, viper.GetString("DAOContract"))
+		}
+		chainID := viper.GetString("ChainID")
+		if !force && manifest.ChainID != "" && chainID != "" && manifest.ChainID != chainID {
+			return fmt.Errorf("backup is from chain %v, but network %v is chain %v", manifest.ChainID, viper.GetString("Network"), chainID)
+		}
+
+		gc, err := openGraphCache()
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		err = gc.Clear()
+		if err != nil {
+			return fmt.Errorf("cannot clear graph cache: %v", err)
+		}
+		err = gc.PutDocuments(snapshot.Documents...)
+		if err != nil {
+			return fmt.Errorf("cannot load documents into graph cache: %v", err)
+		}
+		err = gc.PutEdges(snapshot.Edges...)
+		if err != nil {
+			return fmt.Errorf("cannot load edges into graph cache: %v", err)
+		}
+		if !manifest.CreatedAt.IsZero() {
+			err = gc.MarkSynced(manifest.CreatedAt, manifest.HeadBlock)
+			if err != nil {
+				return err
+			}
+		}
+		err = gc.MarkReconciled(time.Now())
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Loaded %v documents and %v edges into %v\n", len(snapshot.Documents), len(snapshot.Edges), gc.FileName)
+		return nil
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupLoadCmd)
+	backupLoadCmd.Flags().BoolP("force", "", false, "load the backup even if its chain or contract differs from the selected network")
+}
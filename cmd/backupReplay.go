@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/backup"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// replayTarget pushes the replayed actions to a node and reads the recreated documents from it
+type replayTarget struct {
+	*chain.NodeReader
+}
+
+func (t replayTarget) PushActions(ctx context.Context, actions []*eos.Action) error {
+	trxID, err := execTrx(ctx, t.API, actions)
+	if err != nil {
+		return err
+	}
+	zlog.Debug("replayed batch", zap.String("trx-id", trxID), zap.Int("actions", len(actions)))
+	return nil
+}
+
+var backupReplayCmd = &cobra.Command{
+	Use:   "replay <backup> --to <endpoint>",
+	Short: "recreate the documents and edges of a backup on another chain, e.g. a local nodeos",
+	Long: `recreate the documents and edges of a backup on another chain, e.g. a local nodeos
+
+Documents are created with the contract's create action in their original order, then edges with
+the newedge action, in transactions of --batch-size actions signed by --creator. Progress is saved
+in a checkpoint file in the backup folder after each transaction, so an interrupted replay resumes
+where it stopped. Before creating edges, the replay checks that every document was recreated
+under its hash in the backup and stops if not, as edges refer to documents by hash. Replaying
+onto the chain the backup was taken from is refused.`,
+	Example: `  daoctl backup replay ./dao-backup-2021Apr08-120000 --to http://localhost:8888 --creator dao.hypha`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		dir := args[0]
+
+		endpoint := viper.GetString("backup-replay-cmd-to")
+		if endpoint == "" {
+			return fmt.Errorf("the endpoint of the target chain is required, use --to")
+		}
+		contract := eos.AN(viper.GetString("DAOContract"))
+		if target := viper.GetString("backup-replay-cmd-contract"); target != "" {
+			contract = eos.AN(target)
+		}
+		creator := contract
+		if account := viper.GetString("backup-replay-cmd-creator"); account != "" {
+			creator = eos.AN(account)
+		}
+		batchSize := viper.GetInt("backup-replay-cmd-batch-size")
+		if batchSize < 1 {
+			return fmt.Errorf("batch size must be at least 1")
+		}
+
+		snapshot, err := backup.Read(dir)
+		if err != nil {
+			return err
+		}
+
+		api := eos.New(sanitizeAPIURL(endpoint))
+		api.Debug = viper.GetBool("global-debug")
+		info, err := api.GetInfo(ctx)
+		if err != nil {
+			return fmt.Errorf("cannot get chain info from %v: %v", endpoint, err)
+		}
+		if info.ChainID.String() == snapshot.Manifest.ChainID {
+			return fmt.Errorf("refusing to replay the backup onto chain %v, which it was taken from", snapshot.Manifest.ChainID)
+		}
+		attachWallet(api)
+
+		checkpoint := backup.Checkpoint{Endpoint: endpoint}
+		if !viper.GetBool("backup-replay-cmd-restart") {
+			checkpoint, err = backup.ReadCheckpoint(dir, endpoint)
+			if err != nil {
+				return err
+			}
+		}
+
+		documents, edges, dangling := backup.ReplayOrder(snapshot)
+		if len(dangling) > 0 {
+			fmt.Printf("Skipping %v edges with a node that is not in the backup\n", len(dangling))
+		}
+		if checkpoint.Documents > 0 || checkpoint.Edges > 0 {
+			fmt.Printf("Resuming after %v documents and %v edges\n", checkpoint.Documents, checkpoint.Edges)
+		}
+
+		replay := backup.Replay{
+			Dir:       dir,
+			Target:    replayTarget{chain.NewNodeReader(api)},
+			Contract:  contract,
+			Creator:   creator,
+			BatchSize: batchSize,
+			Out:       os.Stdout,
+		}
+		err = replay.Run(ctx, documents, edges, checkpoint)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("\nReplayed %v documents and %v edges onto %v\n\n", len(documents), len(edges), endpoint)
+		return nil
+	},
+}
+
+func init() {
+	backupCmd.AddCommand(backupReplayCmd)
+	backupReplayCmd.Flags().StringP("to", "", "", "endpoint of the chain to replay onto, e.g. http://localhost:8888")
+	backupReplayCmd.Flags().StringP("contract", "", "", "contract to replay into (default is DAOContract)")
+	backupReplayCmd.Flags().StringP("creator", "", "", "account that creates and signs for the documents and edges (default is the contract)")
+	backupReplayCmd.Flags().IntP("batch-size", "", 20, "number of actions per transaction")
+	backupReplayCmd.Flags().BoolP("restart", "", false, "ignore the checkpoint and replay from the beginning")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hypha-dao/daoctl/backup"
+	"github.com/spf13/viper"
+)
+
+func TestBackupReplayChecksTarget(t *testing.T) {
+	chainID := "1eaa0824707c8c16bd25145493bf062aecddfeb56c736f6ba6397f3195f33c9f"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v1/chain/get_info" {
+			t.Errorf("unexpected request %v", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"chain_id":"` + chainID + `","head_block_num":1000}`))
+	}))
+	defer server.Close()
+
+	dir := t.TempDir()
+	_, err := backup.Write(dir, backup.Manifest{
+		CreatedAt: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC),
+		ChainID:   chainID,
+		Contract:  "dao.hypha",
+	}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name  string
+		flags map[string]interface{}
+		err   string
+	}{
+		{"no endpoint", map[string]interface{}{}, "the endpoint of the target chain is required"},
+		{"empty batches", map[string]interface{}{"to": server.URL, "batch-size": 0}, "batch size must be at least 1"},
+		{"source chain", map[string]interface{}{"to": server.URL + "/"}, "refusing to replay the backup onto chain " + chainID},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			t.Cleanup(viper.Reset)
+			viper.Set("DAOContract", "dao.hypha")
+			viper.Set("backup-replay-cmd-batch-size", 20)
+			for flag, value := range test.flags {
+				viper.Set("backup-replay-cmd-"+flag, value)
+			}
+
+			err := backupReplayCmd.RunE(backupReplayCmd, []string{dir})
+			if err == nil || !strings.Contains(err.Error(), test.err) {
+				t.Errorf("got error %v, want one containing %q", err, test.err)
+			}
+		})
+	}
+}
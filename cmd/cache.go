@@ -0,0 +1,33 @@
+package cmd
+
+import (
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "manage the local graph cache of documents and edges",
+	Long: `manage the local graph cache of documents and edges
+
+The cache is kept under the user cache directory, one file per network and DAO contract.
+Commands add new documents and their edges once per run; 'cache sync' reconciles the whole
+cache with the chain, also removing erased documents and edges.`,
+}
+
+// openGraphCache opens the graph cache of the selected network and contract without syncing it
+func openGraphCache() (*util.GraphCache, error) {
+	contract := eos.AN(viper.GetString("DAOContract"))
+	fileName, err := util.DefaultCacheFile(viper.GetString("Network"), contract)
+	if err != nil {
+		return nil, err
+	}
+	return util.OpenGraphCache(fileName, contract)
+}
+
+func init() {
+	RootCmd.AddCommand(cacheCmd)
+}
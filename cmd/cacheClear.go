@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "remove every document and edge from the cache",
+	Long:  "remove every document, edge and the sync time from the cache; the next command that needs it rebuilds it from the chain",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gc, err := openGraphCache()
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		err = gc.Clear()
+		if err != nil {
+			return fmt.Errorf("cannot clear graph cache: %v", err)
+		}
+
+		fmt.Println("Cleared " + gc.FileName)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheClearCmd)
+}
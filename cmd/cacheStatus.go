@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+)
+
+var cacheStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "show the document and edge counts and when the cache was last synced",
+	Long:  "show the document counts per type, the edge counts per name, and the block and age of the last sync",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gc, err := openGraphCache()
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		status, err := gc.Status()
+		if err != nil {
+			return err
+		}
+
+		lastDocumentID := "none"
+		if status.HasDocumentID {
+			lastDocumentID = fmt.Sprint(status.LastDocumentID)
+		}
+		lastSyncBlock := "unknown"
+		if status.LastSyncBlock > 0 {
+			lastSyncBlock = fmt.Sprint(status.LastSyncBlock)
+		}
+
+		output := []string{
+			fmt.Sprintf("Cache File|%v", status.FileName),
+			fmt.Sprintf("Contract|%v", status.Contract),
+			fmt.Sprintf("Documents|%v", status.Documents),
+			fmt.Sprintf("Edges|%v", status.Edges),
+			fmt.Sprintf("Last Sync|%v", formatSyncTime(status.LastSyncTime)),
+			fmt.Sprintf("Last Sync Block|%v", lastSyncBlock),
+			fmt.Sprintf("Last Document ID|%v", lastDocumentID),
+			fmt.Sprintf("Last Reconcile|%v", formatSyncTime(status.LastReconcile)),
+		}
+		fmt.Println("\n" + columnize.SimpleFormat(output) + "\n")
+
+		typeTable := views.CountTable("Document Type", status.DocumentsByType)
+		typeTable.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(typeTable.String() + "\n")
+
+		edgeTable := views.CountTable("Edge Name", status.EdgesByName)
+		edgeTable.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(edgeTable.String() + "\n")
+		return nil
+	},
+}
+
+func formatSyncTime(syncTime time.Time) string {
+	if syncTime.IsZero() {
+		return "never"
+	}
+	return fmt.Sprintf("%v (%v ago)", syncTime.Local().Format("2006 Jan 02 15:04:05"), time.Since(syncTime).Round(time.Second))
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheStatusCmd)
+}
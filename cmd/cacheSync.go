@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var cacheSyncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "reconcile the cache with the chain's documents and edges",
+	Long: `reconcile the cache with the chain's documents and edges
+
+Other commands sync the cache incrementally, once per run: documents above the last synced
+document ID are added with the edges from and to them. This command reads the document and edge
+tables in full instead: documents and edges missing from the cache are added, and those erased on
+chain are removed. Commands also reconcile once the last reconcile is older than the
+GraphCacheReconcileInterval setting, 24h by default. With --full the cache is cleared and rebuilt
+first.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		reader := getReader()
+		ctx := context.Background()
+
+		gc, err := openGraphCache()
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		if viper.GetBool("cache-sync-cmd-full") {
+			err = gc.Clear()
+			if err != nil {
+				return fmt.Errorf("cannot clear graph cache: %v", err)
+			}
+		}
+
+		result, err := gc.Reconcile(ctx, reader)
+		if err != nil {
+			return fmt.Errorf("cannot sync graph cache: %v", err)
+		}
+
+		fmt.Printf("Synced %v new documents and %v new edges into %v, removed %v documents and %v edges\n",
+			result.Documents, result.Edges, gc.FileName, result.RemovedDocuments, result.RemovedEdges)
+		return nil
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheSyncCmd)
+	cacheSyncCmd.Flags().BoolP("full", "", false, "clear the cache and rebuild it from the chain")
+}
@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/spf13/cobra"
+)
+
+var cacheVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "check the cached documents and edges for integrity problems",
+	Long: `check the cached documents and edges for integrity problems
+
+Each document's hash is recomputed from its content groups and compared with the hash it is
+cached under, and each edge is checked for from and to nodes that are missing from the cache.`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gc, err := openGraphCache()
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		problems, err := gc.Verify()
+		if err != nil {
+			return err
+		}
+
+		if len(problems) == 0 {
+			fmt.Println("No problems found in " + gc.FileName)
+			return nil
+		}
+
+		problemTable := views.CacheProblemTable(problems)
+		problemTable.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("\n" + problemTable.String() + "\n")
+		return fmt.Errorf("found %v problems in graph cache: %v", len(problems), gc.FileName)
+	},
+}
+
+func init() {
+	cacheCmd.AddCommand(cacheVerifyCmd)
+}
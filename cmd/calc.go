@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var calcCmd = &cobra.Command{
+	Use:   "calc",
+	Short: "calculate compensation before proposing",
+}
+
+func init() {
+	RootCmd.AddCommand(calcCmd)
+}
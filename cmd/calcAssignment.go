@@ -0,0 +1,218 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var calcAssignmentCmd = &cobra.Command{
+	Use:   "assignment <role> --time-share <percent> --deferred <percent> --periods <count>",
+	Short: "calculate the pay of an assignment to a role",
+	Long: `calculate the pay of an assignment to a role
+
+The role's annual USD salary is converted to a salary per period and split into the HUSD,
+HYPHA, HVOICE and SEEDS amounts the contract pays, using the prices and deferral factors of the
+DAO settings. The terms are checked against the role's minimum time share and deferred pay, and
+against its full time capacity less the time share of its assignments that have not ended.
+Roles and settings are read from the graph cache.`,
+	Example: `  daoctl calc assignment Developer --time-share 50 --deferred 40 --periods 12`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		roleDoc, err := gc.ResolveDocument(args[0])
+		if err != nil {
+			return err
+		}
+		role, err := models.NewRole(roleDoc)
+		if err != nil {
+			return err
+		}
+		settings, err := cachedSettings(gc)
+		if err != nil {
+			return err
+		}
+
+		periods := int64(viper.GetInt("calc-assignment-cmd-periods"))
+		if periods < 1 {
+			return fmt.Errorf("invalid number of periods: %v, expected at least 1", periods)
+		}
+		timeShare := float64(viper.GetInt("calc-assignment-cmd-time-share")) / 100
+		deferred := role.MinDeferred
+		if viper.IsSet("calc-assignment-cmd-deferred") {
+			deferred = float64(viper.GetInt("calc-assignment-cmd-deferred")) / 100
+		}
+
+		compensation, err := models.NewCompensation(role, settings, timeShare, deferred, periods)
+		if err != nil {
+			return err
+		}
+
+		assignments, err := roleAssignments(ctx, gc, role.Hash.String())
+		if err != nil {
+			return err
+		}
+		var committed float64
+		var open int
+		now := time.Now().UTC()
+		for _, assignment := range assignments {
+			if !assignment.Ended(now) {
+				committed += assignment.TimeShare
+				open++
+			}
+		}
+
+		output := []string{
+			fmt.Sprintf("Role|%v (%v)", role.Title, role.Hash.String()[:5]),
+			fmt.Sprintf("Annual USD Salary|%v", util.FormatAsset(&role.AnnualUSDSalary, 2)),
+			fmt.Sprintf("Time Share|%v%%", percentText(timeShare)),
+			fmt.Sprintf("Deferred Pay|%v%%", percentText(deferred)),
+			fmt.Sprintf("Periods|%v", periods),
+			fmt.Sprintf("USD per Period|%v", strconv.FormatFloat(compensation.USDPerPeriod, 'f', 2, 64)),
+			fmt.Sprintf("Full Time Capacity|%v, %v committed to %v assignments, %v after this one",
+				strconv.FormatFloat(role.FullTimeCapacity, 'f', 2, 64), strconv.FormatFloat(committed, 'f', 2, 64),
+				open, strconv.FormatFloat(role.FullTimeCapacity-committed-timeShare, 'f', 2, 64)),
+		}
+		fmt.Println("\n" + columnize.SimpleFormat(output) + "\n")
+
+		table := views.CompensationTable(compensation)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(table.String() + "\n")
+
+		problems := role.CheckTerms(timeShare, deferred)
+		if committed+timeShare > role.FullTimeCapacity+1e-9 {
+			problems = append(problems, fmt.Sprintf("time share %v%% exceeds the role's remaining capacity of %v%%",
+				percentText(timeShare), percentText(role.FullTimeCapacity-committed)))
+		}
+		if len(problems) > 0 {
+			return fmt.Errorf("assignment does not fit the role:\n  %v", strings.Join(problems, "\n  "))
+		}
+		return nil
+	},
+}
+
+// percentText formats a fraction of 1 as a percentage with up to two decimals
+func percentText(fraction float64) string {
+	return strconv.FormatFloat(math.Round(fraction*10000)/100, 'f', -1, 64)
+}
+
+// cachedSettings returns the settings document of the DAO from the graph cache
+func cachedSettings(gc *util.GraphCache) (models.Settings, error) {
+	documents, err := gc.GetDocumentsByType(eos.Name("settings"))
+	if err != nil {
+		return models.Settings{}, fmt.Errorf("cannot get settings: %v", err)
+	}
+	if len(documents) == 0 {
+		return models.Settings{}, fmt.Errorf("no settings document found, run 'daoctl cache sync' to fetch it")
+	}
+	return models.NewSettings(documents[len(documents)-1])
+}
+
+// roleAssignment is an approved assignment to a role with the time its first period starts and
+// the time its last period ends, which are zero when they fall outside the calendar
+type roleAssignment struct {
+	models.Assignment
+	Start time.Time
+	End   time.Time
+}
+
+// Ended reports whether the assignment's last period ended before the time
+func (a *roleAssignment) Ended(t time.Time) bool {
+	return !a.End.IsZero() && !a.End.After(t)
+}
+
+// Started reports whether the assignment's first period started before the time
+func (a *roleAssignment) Started(t time.Time) bool {
+	return !a.Start.IsZero() && !a.Start.After(t)
+}
+
+// roleAssignments returns the approved assignments of the role, those it links to with assignment
+// edges, placed in the calendar by their start edge and period count
+func roleAssignments(ctx context.Context, gc *util.GraphCache, roleHash string) ([]roleAssignment, error) {
+	periods, err := cachedPeriods(ctx, gc)
+	if err != nil {
+		return nil, err
+	}
+	periodIndex := make(map[string]int)
+	for i, period := range periods {
+		periodIndex[period.Document.Hash.String()] = i
+	}
+
+	edges, err := gc.GetEdgesFrom(roleHash, eos.Name("assignment"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get assignments of role: %v %v", roleHash, err)
+	}
+
+	var assignments []roleAssignment
+	for _, edge := range edges {
+		document, found, err := gc.GetDocument(edge.ToNode.String())
+		if err != nil || !found {
+			continue
+		}
+		assignment, err := models.NewAssignment(document)
+		if err != nil {
+			return nil, err
+		}
+
+		a := roleAssignment{Assignment: assignment}
+		startEdges, err := gc.GetEdgesFrom(document.Hash.String(), eos.Name("start"))
+		if err != nil {
+			return nil, fmt.Errorf("cannot get start period of assignment: %v %v", document.Hash.String(), err)
+		}
+		if len(startEdges) > 0 {
+			if i, found := periodIndex[startEdges[0].ToNode.String()]; found {
+				a.Start = periods[i].StartTime
+				if end := i + int(assignment.PeriodCount); end < len(periods) {
+					a.End = periods[end].StartTime
+				}
+			}
+		}
+		assignments = append(assignments, a)
+	}
+	return assignments, nil
+}
+
+// cachedPeriods returns the periods in the graph cache ordered by start time
+func cachedPeriods(ctx context.Context, gc *util.GraphCache) ([]models.Period, error) {
+	documents, err := gc.GetDocumentsByType(eos.Name("period"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get periods: %v", err)
+	}
+
+	var periods []models.Period
+	for _, document := range documents {
+		period, err := models.NewSinglePeriod(ctx, getReader(), eos.AN(viper.GetString("DAOContract")), document)
+		if err != nil {
+			return nil, err
+		}
+		periods = append(periods, period)
+	}
+	sort.Slice(periods, func(i, j int) bool { return periods[i].StartTime.Before(periods[j].StartTime) })
+	return periods, nil
+}
+
+func init() {
+	calcCmd.AddCommand(calcAssignmentCmd)
+	calcAssignmentCmd.Flags().IntP("time-share", "", 100, "time commitment as a percentage of full time")
+	calcAssignmentCmd.Flags().IntP("deferred", "", 0, "percentage of pay deferred (default the role's minimum)")
+	calcAssignmentCmd.Flags().IntP("periods", "", 1, "number of periods")
+}
@@ -2,41 +2,129 @@ package cmd
 
 import (
 	"context"
+	"fmt"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/ryanuber/columnize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-func newCloseAction(proposalID string) *eos.Action {
+func newCloseAction(proposalHash eos.Checksum256) *eos.Action {
 	return &eos.Action{
 		Account: eos.AN(viper.GetString("DAOContract")),
 		Name:    eos.ActN("closedocprop"),
 		Authorization: []eos.PermissionLevel{
 			{Actor: eos.AN(viper.GetString("DAOUser")), Permission: eos.PN("active")},
 		},
-		ActionData: eos.NewActionData(proposalID),
+		ActionData: eos.NewActionData(proposalHash),
 	}
 }
 
 var closeCmd = &cobra.Command{
-	Use:   "close [hash]",
+	Use:   "close [hash | shorty | id | label]",
 	Short: "close a proposal",
-	Long:  "close a proposal that is linked to a ballot where the voting period has ended",
-	Args:  cobra.RangeArgs(1, 1),
+	Long: `close a proposal that is linked to a ballot where the voting period has ended
+
+With --all every open proposal whose voting period has ended is closed in one transaction, or
+with --passing-only those that would pass. If the transaction is rejected, the proposals are
+closed one at a time and those that could not be closed are reported.`,
+	Example: `  daoctl close e8d9efca
+  daoctl close --all --passing-only`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if viper.GetBool("close-cmd-all") {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(1)(cmd, args)
+	},
 	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		contract := eos.AN(viper.GetString("DAOContract"))
+
+		if viper.GetBool("close-cmd-all") {
+			errorCheck("closing proposals", closeExpiredProposals(ctx))
+			return
+		}
 
-		// proposalID, err := strconv.ParseUint(args[0], 10, 64)
-		// if err != nil {
-		// 	fmt.Println("Error reading proposal_id. ", err)
-		// 	return
-		// }
+		proposal, err := util.ResolveDocument(ctx, getReader(), contract, args[0])
+		errorCheck("resolving proposal", err)
 
-		pushEOSCActions(context.Background(), getAPI(), newCloseAction(args[0]))
+		pushEOSCActions(ctx, getAPI(), newCloseAction(proposal.Hash))
 	},
 }
 
+// closeExpiredProposals closes the open proposals whose voting period has ended
+func closeExpiredProposals(ctx context.Context) error {
+	gc, err := getGraphCache(ctx)
+	if err != nil {
+		return err
+	}
+	ballots, err := openBallots(ctx, gc)
+	gc.Close()
+	if err != nil {
+		return err
+	}
+
+	var closing []models.ProposalBallot
+	for _, ballot := range ballots {
+		if ballot.Status != proposalExpired {
+			continue
+		}
+		if viper.GetBool("close-cmd-passing-only") && !ballot.Passing() {
+			continue
+		}
+		closing = append(closing, ballot)
+	}
+	if len(closing) == 0 {
+		fmt.Println("No proposals to close")
+		return nil
+	}
+
+	var actions []*eos.Action
+	var output []string
+	for _, ballot := range closing {
+		actions = append(actions, newCloseAction(ballot.Proposal.Hash))
+		output = append(output, fmt.Sprintf("%v|%v|%v|passing: %v", ballot.Proposal.Hash.String()[:8], ballot.Type, ballot.Title, ballot.Passing()))
+	}
+	fmt.Printf("\nClosing %v proposals\n\n%v\n\n", len(closing), columnize.SimpleFormat(output))
+
+	// an unsigned or written transaction cannot be checked, so it is left to the signer
+	if viper.GetBool("global-skip-sign") || viper.GetString("global-write-transaction") != "" {
+		pushEOSCActions(ctx, getAPI(), actions...)
+		return nil
+	}
+
+	api := getAPI()
+	url, err := tryPushEOSCActions(ctx, api, actions...)
+	if err == nil {
+		fmt.Printf("Closed %v proposals\n  %v\n", len(closing), url)
+		return nil
+	}
+	fmt.Printf("Transaction closing all proposals was rejected, closing them one at a time: %v\n\n", err)
+
+	var report []string
+	failed := 0
+	for i, ballot := range closing {
+		url, err := tryPushEOSCActions(ctx, api, actions[i])
+		result := fmt.Sprintf("closed|%v", url)
+		if err != nil {
+			result = fmt.Sprintf("failed|%v", err)
+			failed++
+		}
+		report = append(report, fmt.Sprintf("%v|%v|%v", ballot.Proposal.Hash.String()[:8], ballot.Title, result))
+	}
+	fmt.Println(columnize.SimpleFormat(report) + "\n")
+
+	if failed > 0 {
+		return fmt.Errorf("cannot close %v of %v proposals", failed, len(closing))
+	}
+	return nil
+}
+
 func init() {
 	RootCmd.AddCommand(closeCmd)
-	closeCmd.Flags().BoolP("all", "a", false, "attempt to close all open proposals")
+	closeCmd.Flags().BoolP("all", "", false, "close all open proposals whose voting period has ended")
+	closeCmd.Flags().BoolP("passing-only", "", false, "with --all, only close the proposals that would pass")
 }
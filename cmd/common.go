@@ -2,12 +2,15 @@ package cmd
 
 import (
 	"context"
+	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"net/http"
 	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -20,6 +23,7 @@ import (
 	"github.com/eoscanada/eos-go/sudo"
 	"github.com/eoscanada/eosc/cli"
 	eosvault "github.com/eoscanada/eosc/vault"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/spf13/viper"
 	"github.com/tidwall/gjson"
 	"github.com/tidwall/sjson"
@@ -85,10 +89,90 @@ func getAPI() *eos.API {
 	}
 
 	api.Debug = viper.GetBool("global-debug")
+	api.HttpClient.Transport = getTransport(api.HttpClient.Transport)
+	checkChain(context.Background(), api)
 
 	return api
 }
 
+var cassette *chain.Cassette
+
+// getTransport wraps the provided transport in the cassette selected by --record or --replay,
+// so that node and Hyperion traffic is captured to, or served from, the cassette directory
+func getTransport(transport http.RoundTripper) http.RoundTripper {
+	recordDir := viper.GetString("global-record")
+	replayDir := viper.GetString("global-replay")
+	if recordDir == "" && replayDir == "" {
+		return transport
+	}
+	if recordDir != "" && replayDir != "" {
+		exitWitMessage("--record and --replay cannot be used together")
+	}
+
+	if cassette == nil {
+		var err error
+		if replayDir != "" {
+			cassette, err = chain.NewReplayingCassette(replayDir)
+		} else {
+			cassette, err = chain.NewRecordingCassette(recordDir, transport)
+		}
+		errorCheck("opening cassette", err)
+	}
+	return cassette
+}
+
+var activeReader chain.ChainReader
+var recorder *chain.Recorder
+
+// getReader returns the ChainReader used by read-only commands. When --fixture is set, chain
+// state is served from the recorded fixture file instead of the node; when --fixture-record is
+// set, every read is captured so it can be saved as a fixture once the command completes.
+func getReader() chain.ChainReader {
+	if activeReader != nil {
+		return activeReader
+	}
+
+	if fixtureFile := viper.GetString("global-fixture"); fixtureFile != "" {
+		fixtureReader, err := chain.NewFixtureReader(fixtureFile)
+		errorCheck("loading chain fixture", err)
+		activeReader = fixtureReader
+	} else {
+		activeReader = chain.NewNodeReader(getAPI())
+	}
+
+	if viper.GetString("global-fixture-record") != "" {
+		recorder = chain.NewRecorder(activeReader)
+		activeReader = recorder
+	}
+	return activeReader
+}
+
+// setFixtureCacheFile keeps the graph cache of each fixture apart from the cache of the live network
+func setFixtureCacheFile() {
+	fixtureFile := viper.GetString("global-fixture")
+	if fixtureFile == "" || viper.GetString("GraphCacheFile") != "" {
+		return
+	}
+
+	fixtureData, err := ioutil.ReadFile(fixtureFile)
+	errorCheck("reading chain fixture", err)
+	fixtureHash := sha256.Sum256(fixtureData)
+	viper.Set("GraphCacheFile", filepath.Join(os.TempDir(), "daoctl-fixture-"+hex.EncodeToString(fixtureHash[:8])+".db"))
+}
+
+func saveRecordedFixture() error {
+	if recorder == nil {
+		return nil
+	}
+
+	fixtureFile := viper.GetString("global-fixture-record")
+	if err := recorder.Save(fixtureFile); err != nil {
+		return fmt.Errorf("cannot save recorded fixture: %v", err)
+	}
+	zlog.Debug("recorded chain fixture", zap.String("file", fixtureFile))
+	return nil
+}
+
 var coreSymbolIsCached bool
 var coreSymbol eos.Symbol
 
@@ -188,6 +272,36 @@ func pushEOSCActionsAndContextFreeActions(ctx context.Context, api *eos.API, con
 		act.Authorization = nil
 	}
 
+	tx, opts := newEOSCTransaction(ctx, api, actions)
+	if len(contextFreeActions) > 0 {
+		tx.ContextFreeActions = contextFreeActions
+	}
+
+	tx = optionallySudoWrap(tx, opts)
+
+	signedTx, packedTx := optionallySignTransaction(ctx, tx, opts.ChainID, api, true)
+
+	optionallyPushTransaction(ctx, signedTx, packedTx, opts.ChainID, api)
+}
+
+// tryPushEOSCActions signs and pushes the actions in one transaction like pushEOSCActions, but
+// returns the error of a transaction the node rejects instead of exiting. It requires a signed
+// transaction, so it is not for use with --skip-sign or --write-transaction.
+func tryPushEOSCActions(ctx context.Context, api *eos.API, actions ...*eos.Action) (string, error) {
+	tx, opts := newEOSCTransaction(ctx, api, actions)
+	tx = optionallySudoWrap(tx, opts)
+
+	_, packedTx := optionallySignTransaction(ctx, tx, opts.ChainID, api, true)
+	resp, err := api.PushTransaction(ctx, packedTx)
+	if err != nil {
+		return "", err
+	}
+	return transactionURL(opts.ChainID, resp.TransactionID), nil
+}
+
+// newEOSCTransaction returns a transaction of the actions with the permissions, TaPoS and delay
+// given by the global flags
+func newEOSCTransaction(ctx context.Context, api *eos.API, actions []*eos.Action) (*eos.Transaction, *eos.TxOptions) {
 	permissions := viper.GetStringSlice("global-permission")
 	if len(permissions) != 0 {
 		levels, err := permissionsToPermissionLevels(permissions)
@@ -217,16 +331,7 @@ func pushEOSCActionsAndContextFreeActions(ctx context.Context, api *eos.API, con
 		os.Exit(1)
 	}
 
-	tx := eos.NewTransaction(actions, opts)
-	if len(contextFreeActions) > 0 {
-		tx.ContextFreeActions = contextFreeActions
-	}
-
-	tx = optionallySudoWrap(tx, opts)
-
-	signedTx, packedTx := optionallySignTransaction(ctx, tx, opts.ChainID, api, true)
-
-	optionallyPushTransaction(ctx, signedTx, packedTx, opts.ChainID, api)
+	return eos.NewTransaction(actions, opts), opts
 }
 
 func optionallySudoWrap(tx *eos.Transaction, opts *eos.TxOptions) *eos.Transaction {
@@ -312,6 +417,10 @@ func pushTransaction(ctx context.Context, api *eos.API, packedTx *eos.PackedTran
 }
 
 func transactionURL(chainID eos.SHA256Bytes, trxID string) string {
+	if url := explorerURL(chainID, "Transaction"); url != "" {
+		return fmt.Sprintf(url, trxID)
+	}
+
 	hexChain := hex.EncodeToString(chainID)
 	switch hexChain {
 	case "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906":
@@ -326,6 +435,10 @@ func transactionURL(chainID eos.SHA256Bytes, trxID string) string {
 }
 
 func blockURL(chainID eos.SHA256Bytes, blockID string) string {
+	if url := explorerURL(chainID, "Block"); url != "" {
+		return fmt.Sprintf(url, blockID)
+	}
+
 	hexChain := hex.EncodeToString(chainID)
 	switch hexChain {
 	case "aca376f206b8fc25a6ed44dbdc66547c36c6c33e3a119ffbeaef943642f0e906":
@@ -13,18 +13,18 @@ import (
 )
 
 var dumpCmd = &cobra.Command{
-	Use:   "dump [hash]",
+	Use:   "dump [hash | shorty | id | label]",
 	Short: "raw dump of the documents json",
 	Long:  "raw dump of the documents json",
 	Args:  cobra.RangeArgs(1, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 		contract := eos.AN(viper.GetString("DAOContract"))
 
-		document, err := util.Get(ctx, api, contract, args[0])
+		document, err := util.ResolveDocument(ctx, reader, contract, args[0])
 		if err != nil {
-			return fmt.Errorf("cannot find document with hash: %v %v", args[0], err)
+			return fmt.Errorf("cannot resolve document: %v", err)
 		}
 
 		docJson, err := json.Marshal(document)
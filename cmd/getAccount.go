@@ -19,10 +19,10 @@ var getAccountCmd = &cobra.Command{
 	Long:  "retrieve account information for a given name.  For a json dump, append the argument --json.",
 	Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 
 		accountName := toAccount(args[0], "account name")
-		account, err := api.GetAccount(context.Background(), accountName)
+		account, err := reader.GetAccount(context.Background(), accountName)
 		errorCheck("get account", err)
 
 		if viper.GetBool("get-account-cmd-json") == true {
@@ -24,13 +24,13 @@ var getBallotCmd = &cobra.Command{
 	Long:  "OLD - telos decide only - retrieve the ballot times, voters, voting selections, and quorum info",
 	Args:  cobra.RangeArgs(1, 1),
 	Run: func(cmd *cobra.Command, args []string) {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 		ac := accounting.NewAccounting("", 0, ",", ".", "%s %v", "%s (%v)", "%s --") // TODO: make this configurable
 
 		ballotName := eos.Name(viper.GetString("BallotPrefix") + args[0])
 
-		ballot, err := models.NewBallot(ctx, api, ballotName)
+		ballot, err := models.NewBallot(ctx, reader, ballotName)
 		if err != nil {
 			panic("Cannot read ballot: " + args[0])
 		}
@@ -38,7 +38,7 @@ var getBallotCmd = &cobra.Command{
 		fmt.Println("\n\n" + views.BallotHeader(*ballot) + "\n\n")
 		votesTable, totalVotes := views.VotesTable(ballot.Votes)
 		fmt.Println(votesTable.String())
-		hvoice, err := models.GetHvoiceSupply(ctx, api)
+		hvoice, err := models.GetHvoiceSupply(ctx, reader)
 		if err != nil {
 			fmt.Println("Cannot read HVOICE supply.")
 			return
@@ -6,9 +6,9 @@ import (
 
 	"github.com/alexeyco/simpletable"
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/models"
 	"github.com/hypha-dao/daoctl/views"
-	"github.com/hypha-dao/document-graph/docgraph"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -18,7 +18,7 @@ var getCalendarCmd = &cobra.Command{
 	Short: "print the calendar",
 	Long:  "print a table with each of the time periods",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 		contract := eos.AN(viper.GetString("DAOContract"))
 
@@ -27,13 +27,13 @@ var getCalendarCmd = &cobra.Command{
 		// 	return fmt.Errorf("cannot get cache: %v", err)
 		// }
 
-		// rootDocument, err := docgraph.LoadDocument(ctx, api, contract, viper.GetString("RootNode"))
+		// rootDocument, err := reader.LoadDocument(ctx, contract, viper.GetString("RootNode"))
 		// if err != nil {
 		// 	return fmt.Errorf("cannot load root document: %v", err)
 		// }
 		// fmt.Println(rootDocument)
 
-		// startEdges, err := docgraph.GetEdgesFromDocumentWithEdge(ctx, api, contract, rootDocument, eos.Name("start"))
+		// startEdges, err := chain.GetEdgesFromDocumentWithEdge(ctx, reader, contract, rootDocument, eos.Name("start"))
 		// if err != nil {
 		// 	return fmt.Errorf("error while retrieving start edge: %v", err)
 		// }
@@ -42,18 +42,9 @@ var getCalendarCmd = &cobra.Command{
 		// 	return fmt.Errorf("no start edge from the root node exists: %v", err)
 		// }
 
-		startPeriod := viper.GetString("CalendarStart") //"7706e72c29af438f309a99391fa8e8e3dcef0db438d0d24daf6fc4cf29697bff"
-
-		startPeriodDoc, err := docgraph.LoadDocument(ctx, api, contract, startPeriod) //startEdges[0].ToNode.String())
-
-		// startPeriodDoc, err := docgraph.LoadDocument(ctx, api, contract, startEdges[0].ToNode.String())
-		if err != nil {
-			return fmt.Errorf("error loading the start period document: %v", err)
-		}
-
-		period, err := models.NewPeriod(ctx, api, contract, startPeriodDoc)
+		period, err := getCalendar(ctx, reader, contract)
 		if err != nil {
-			return fmt.Errorf("cannot convert document to period type: %v", err)
+			return err
 		}
 
 		periodTable := views.PeriodTable(period)
@@ -65,6 +56,20 @@ var getCalendarCmd = &cobra.Command{
 	},
 }
 
+// getCalendar loads the calendar from the period configured as CalendarStart
+func getCalendar(ctx context.Context, reader chain.ChainReader, contract eos.AccountName) (models.Period, error) {
+	startPeriodDoc, err := reader.LoadDocument(ctx, contract, viper.GetString("CalendarStart"))
+	if err != nil {
+		return models.Period{}, fmt.Errorf("error loading the start period document: %v", err)
+	}
+
+	period, err := models.NewPeriod(ctx, reader, contract, startPeriodDoc)
+	if err != nil {
+		return models.Period{}, fmt.Errorf("cannot convert document to period type: %v", err)
+	}
+	return period, nil
+}
+
 func init() {
 	getCmd.AddCommand(getCalendarCmd)
 }
@@ -13,6 +13,7 @@ import (
 
 	"github.com/alexeyco/simpletable"
 	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/util"
 	"github.com/hypha-dao/daoctl/views"
 	"github.com/hypha-dao/document-graph/docgraph"
@@ -36,19 +37,27 @@ func cleanString(input string) string {
 func printContentGroups(p *Page) {
 
 	fmt.Println("ContentGroups")
-	for _, contentGroup := range p.Primary.ContentGroups {
-		fmt.Println("  ContentGroup")
+	printContentGroupList(p.Primary.ContentGroups, "  ")
+	fmt.Println()
+}
+
+func printContentGroupList(contentGroups []docgraph.ContentGroup, indent string) {
+	for _, contentGroup := range contentGroups {
+		fmt.Println(indent + "ContentGroup")
 
 		for _, content := range contentGroup {
-			fmt.Print("    ")
-			fmt.Printf("%-35v", cleanString(content.Label))
-			fmt.Printf("%-65v\n", cleanString(content.Value.String()))
+			printContentItem(indent+"  ", content.Label, content.Value.String())
 		}
 	}
-	fmt.Println()
 }
 
-func printEdges(ctx context.Context, api *eos.API, p *Page) {
+func printContentItem(prefix, label, value string) {
+	fmt.Print(prefix)
+	fmt.Printf("%-35v", cleanString(label))
+	fmt.Printf("%-65v\n", cleanString(value))
+}
+
+func printEdges(ctx context.Context, reader chain.ChainReader, p *Page) {
 
 	colorCyan := "\033[36m"
 	colorReset := "\033[0m"
@@ -92,7 +101,7 @@ func printEdges(ctx context.Context, api *eos.API, p *Page) {
 	}
 }
 
-func printDocument(ctx context.Context, api *eos.API, p *Page) {
+func printDocument(ctx context.Context, reader chain.ChainReader, p *Page) {
 	fmt.Println("Document Details")
 
 	fmt.Println()
@@ -106,7 +115,7 @@ func printDocument(ctx context.Context, api *eos.API, p *Page) {
 	fmt.Println(columnize.SimpleFormat(output))
 	fmt.Println()
 	printContentGroups(p)
-	// printEdges(ctx, api, p)
+	// printEdges(ctx, reader, p)
 }
 
 // Page ...
@@ -156,7 +165,7 @@ func (e *edgeChoice) GetWidth() int {
 	return 25
 }
 
-func getPage(ctx context.Context, api *eos.API, pageCache, documentCache *cache.Cache, contract eos.AccountName, hash string) Page {
+func getPage(ctx context.Context, reader chain.ChainReader, pageCache, documentCache *cache.Cache, contract eos.AccountName, hash string) Page {
 	pager, found := pageCache.Get(hash)
 	if found {
 		return pager.(Page)
@@ -164,14 +173,14 @@ func getPage(ctx context.Context, api *eos.API, pageCache, documentCache *cache.
 
 	var err error
 	page := Page{}
-	page.Primary = getDocument(ctx, api, documentCache, contract, hash)
+	page.Primary = getDocument(ctx, reader, documentCache, contract, hash)
 
-	page.FromEdges, err = docgraph.GetEdgesFromDocument(ctx, api, contract, page.Primary)
+	page.FromEdges, err = reader.GetEdgesFromDocument(ctx, contract, page.Primary)
 	if err != nil {
 		log.Println("ERROR: Cannot get edges from document: ", err)
 	}
 
-	page.ToEdges, err = docgraph.GetEdgesToDocument(ctx, api, contract, page.Primary)
+	page.ToEdges, err = reader.GetEdgesToDocument(ctx, contract, page.Primary)
 	if err != nil {
 		log.Println("ERROR: Cannot get edges to document: ", err)
 	}
@@ -180,7 +189,7 @@ func getPage(ctx context.Context, api *eos.API, pageCache, documentCache *cache.
 
 	for i, edge := range page.FromEdges {
 
-		document := getDocument(ctx, api, documentCache, contract, edge.ToNode.String())
+		document := getDocument(ctx, reader, documentCache, contract, edge.ToNode.String())
 
 		page.EdgePrompts[i] = edgeChoice{
 			Name:        edge.EdgeName,
@@ -197,7 +206,7 @@ func getPage(ctx context.Context, api *eos.API, pageCache, documentCache *cache.
 
 	for i, edge := range page.ToEdges {
 
-		document := getDocument(ctx, api, documentCache, contract, edge.FromNode.String())
+		document := getDocument(ctx, reader, documentCache, contract, edge.FromNode.String())
 
 		page.EdgePrompts[i+len(page.FromEdges)] = edgeChoice{
 			Name:        edge.EdgeName,
@@ -226,14 +235,14 @@ func getPage(ctx context.Context, api *eos.API, pageCache, documentCache *cache.
 	return page
 }
 
-func getDocument(ctx context.Context, api *eos.API, c *cache.Cache, contract eos.AccountName, hash string) docgraph.Document {
+func getDocument(ctx context.Context, reader chain.ChainReader, c *cache.Cache, contract eos.AccountName, hash string) docgraph.Document {
 
 	documenter, found := c.Get(hash)
 	if found {
 		return documenter.(docgraph.Document)
 	}
 
-	document, err := docgraph.LoadDocument(ctx, api, contract, hash)
+	document, err := reader.LoadDocument(ctx, contract, hash)
 	if err != nil {
 		log.Println("Document not found: " + hash)
 		return docgraph.Document{}
@@ -243,28 +252,28 @@ func getDocument(ctx context.Context, api *eos.API, c *cache.Cache, contract eos
 	return document
 }
 
-func loadCache(ctx context.Context, api *eos.API, pages, documents *cache.Cache, contract eos.AccountName, startingNode string) {
+func loadCache(ctx context.Context, reader chain.ChainReader, pages, documents *cache.Cache, contract eos.AccountName, startingNode string) {
 
 	go func() {
-		page := getPage(ctx, api, pages, documents, contract, startingNode)
+		page := getPage(ctx, reader, pages, documents, contract, startingNode)
 
 		for _, edge := range page.ToEdges {
-			getPage(ctx, api, pages, documents, contract, edge.ToNode.String())
+			getPage(ctx, reader, pages, documents, contract, edge.ToNode.String())
 		}
 
 		for _, edge := range page.FromEdges {
-			getPage(ctx, api, pages, documents, contract, edge.ToNode.String())
+			getPage(ctx, reader, pages, documents, contract, edge.ToNode.String())
 		}
 	}()
 }
 
 var getDocumentCmd = &cobra.Command{
-	Use:   "document [hash | shorty]",
+	Use:   "document [hash | shorty | id | label]",
 	Short: "retrieve document details and navigate the graph",
 	Long:  "retrieve the detailed content within a document",
 	Args:  cobra.RangeArgs(0, 1),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 		contract := eos.AN(viper.GetString("DAOContract"))
 
@@ -272,31 +281,70 @@ var getDocumentCmd = &cobra.Command{
 
 		// if last==true OR no argument, use the last document
 		if viper.GetBool("get-document-cmd-last") || len(args) == 0 {
-			lastDocument, err := docgraph.GetLastDocument(ctx, api, contract)
+			lastDocument, err := reader.GetLastDocument(ctx, contract)
 			if err != nil {
 				return fmt.Errorf("cannot get last document: %v", err)
 			}
 			hash = lastDocument.Hash.String()
+		} else {
+			document, err := util.ResolveDocument(ctx, reader, contract, args[0])
+			if err != nil {
+				return fmt.Errorf("cannot resolve document: %v", err)
+			}
+			hash = document.Hash.String()
 		}
 
 		// if getting a document with JSON, just print it out and exit
 		if viper.GetBool("get-document-cmd-json") {
-			document, err := util.Get(ctx, api, contract, hash)
+			document, err := util.Get(ctx, reader, contract, hash)
 			if err != nil {
 				return fmt.Errorf("cannot find document with hash: %v %v", hash, err)
 			}
 
 			docJson, err := json.Marshal(document)
 			if err != nil {
-				return fmt.Errorf("cannot marshall document to JSON: %v %v", args[0], err)
+				return fmt.Errorf("cannot marshall document to JSON: %v %v", hash, err)
 			}
 
 			fmt.Println(string(pretty.Color(pretty.Pretty(docJson), nil)))
 			return nil
 		}
 
-		if len(args) == 1 {
-			hash = args[0]
+		// print a tree of edges instead of navigating when asked to or when there is no terminal
+		depth := viper.GetInt("get-document-cmd-depth")
+		if depth == 0 && viper.GetBool("get-document-cmd-navigate") && !isInteractive() {
+			depth = 1
+		}
+		if depth > 0 {
+			outgoing, incoming, err := parseTreeDirection(viper.GetString("get-document-cmd-direction"))
+			if err != nil {
+				return err
+			}
+
+			gc, err := getGraphCache(ctx)
+			if err != nil {
+				return err
+			}
+			defer gc.Close()
+
+			document, found, err := gc.GetDocument(hash)
+			if err != nil {
+				return err
+			}
+			if !found {
+				document, err = reader.LoadDocument(ctx, contract, hash)
+				if err != nil {
+					return fmt.Errorf("cannot find document with hash: %v %v", hash, err)
+				}
+			}
+
+			printDocument(ctx, reader, &Page{Primary: document})
+			return printTree(gc, &document, treeOptions{
+				Depth:     depth,
+				EdgeNames: viper.GetStringSlice("get-document-cmd-edge-name"),
+				Outgoing:  outgoing,
+				Incoming:  incoming,
+			})
 		}
 
 		var page Page
@@ -305,11 +353,11 @@ var getDocumentCmd = &cobra.Command{
 
 		for {
 
-			page = getPage(ctx, api, pages, documents, contract, hash)
+			page = getPage(ctx, reader, pages, documents, contract, hash)
 
-			loadCache(ctx, api, pages, documents, contract, hash)
+			loadCache(ctx, reader, pages, documents, contract, hash)
 
-			printDocument(ctx, api, &page)
+			printDocument(ctx, reader, &page)
 
 			if viper.GetBool("get-document-cmd-navigate") {
 				fmt.Println("                          ")
@@ -357,5 +405,8 @@ func init() {
 	getDocumentCmd.Flags().BoolP("last", "l", false, "retrieve the most recently created document")
 	getDocumentCmd.Flags().BoolP("json", "j", false, "print the document to the terminal in JSON and exit")
 	getDocumentCmd.Flags().BoolP("navigate", "n", true, "show document edges and allow interactive graph navigation")
+	getDocumentCmd.Flags().IntP("depth", "", 0, "print a tree of edges this many levels deep instead of navigating (default 1 when stdin is not a terminal)")
+	getDocumentCmd.Flags().StringSliceP("edge-name", "", nil, "only print edges with these names in the tree")
+	getDocumentCmd.Flags().StringP("direction", "", "both", "edges to print in the tree: in, out or both")
 	getCmd.AddCommand(getDocumentCmd)
 }
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"golang.org/x/term"
+)
+
+// treeOptions selects the edges printed by printTree
+type treeOptions struct {
+	Depth     int
+	EdgeNames []string
+	Outgoing  bool
+	Incoming  bool
+}
+
+func (o *treeOptions) allows(edge *docgraph.Edge) bool {
+	if len(o.EdgeNames) == 0 {
+		return true
+	}
+	for _, name := range o.EdgeNames {
+		if string(edge.EdgeName) == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isInteractive reports whether stdin is a terminal that can drive the navigation prompt
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd()))
+}
+
+func treeNode(d *docgraph.Document) string {
+	return fmt.Sprintf("%v (%v)  %v  %v", getLabel(d), getType(d), d.Hash.String(),
+		d.CreatedDate.Time.Format("2006 Jan 02 15:04:05"))
+}
+
+// printTree prints the edges of the document and of the documents they lead to, down to the
+// depth of the options, as a plain text tree. A document already printed higher up is not expanded again.
+func printTree(gc *util.GraphCache, root *docgraph.Document, options treeOptions) error {
+	fmt.Println(treeNode(root))
+	expanded := map[string]bool{root.Hash.String(): true}
+	return printTreeLevel(gc, root, options, 1, "", expanded)
+}
+
+type treeBranch struct {
+	edge    docgraph.Edge
+	forward bool
+}
+
+func printTreeLevel(gc *util.GraphCache, document *docgraph.Document, options treeOptions, level int, indent string, expanded map[string]bool) error {
+	var branches []treeBranch
+	if options.Outgoing {
+		edges, err := gc.GetEdgesFrom(document.Hash.String(), "")
+		if err != nil {
+			return fmt.Errorf("cannot get edges from document: %v", err)
+		}
+		for _, edge := range edges {
+			if options.allows(&edge) {
+				branches = append(branches, treeBranch{edge: edge, forward: true})
+			}
+		}
+	}
+	if options.Incoming {
+		edges, err := gc.GetEdgesTo(document.Hash.String(), "")
+		if err != nil {
+			return fmt.Errorf("cannot get edges to document: %v", err)
+		}
+		for _, edge := range edges {
+			if options.allows(&edge) {
+				branches = append(branches, treeBranch{edge: edge, forward: false})
+			}
+		}
+	}
+
+	sort.SliceStable(branches, func(i, j int) bool {
+		if branches[i].forward != branches[j].forward {
+			return branches[i].forward
+		}
+		return branches[i].edge.CreatedDate.Before(branches[j].edge.CreatedDate.Time)
+	})
+
+	// expand the documents at this level before descending so that each document is expanded
+	// at the shallowest level it appears
+	var children []string
+	for _, branch := range branches {
+		hash := branch.edge.ToNode.String()
+		if !branch.forward {
+			hash = branch.edge.FromNode.String()
+		}
+		children = append(children, hash)
+	}
+	expand := make([]bool, len(children))
+	for i, hash := range children {
+		if !expanded[hash] && level < options.Depth {
+			expanded[hash] = true
+			expand[i] = true
+		}
+	}
+
+	for i, branch := range branches {
+		connector, childIndent := "├── ", indent+"│   "
+		if i == len(branches)-1 {
+			connector, childIndent = "└── ", indent+"    "
+		}
+
+		arrow := "--->"
+		if !branch.forward {
+			arrow = "<---"
+		}
+
+		child, found, err := gc.GetDocument(children[i])
+		if err != nil {
+			return err
+		}
+		node := "missing document " + children[i]
+		if found {
+			node = treeNode(&child)
+		}
+
+		fmt.Printf("%v%v%v %v %v  %v  edge created %v\n", indent, connector, arrow, string(branch.edge.EdgeName), arrow, node,
+			branch.edge.CreatedDate.Time.Format("2006 Jan 02 15:04:05"))
+
+		if expand[i] && found {
+			err = printTreeLevel(gc, &child, options, level+1, childIndent, expanded)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func parseTreeDirection(direction string) (bool, bool, error) {
+	switch strings.ToLower(direction) {
+	case "both":
+		return true, true, nil
+	case "out":
+		return true, false, nil
+	case "in":
+		return false, true, nil
+	}
+	return false, false, fmt.Errorf("unknown direction: %v, expected in, out or both", direction)
+}
@@ -18,10 +18,10 @@ var getDocumentsCmd = &cobra.Command{
 	Long:  "query and manage documents",
 	// Args:  cobra.RangeArgs(1, 2),
 	Run: func(cmd *cobra.Command, args []string) {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 
-		docs, err := docgraph.GetAllDocuments(ctx, api, eos.AN(viper.GetString("DAOContract")))
+		docs, err := reader.GetAllDocuments(ctx, eos.AN(viper.GetString("DAOContract")))
 		if err != nil {
 			panic(fmt.Errorf("cannot get all documents: %v", err))
 		}
@@ -7,7 +7,6 @@ import (
 	"github.com/alexeyco/simpletable"
 	"github.com/eoscanada/eos-go"
 	"github.com/hypha-dao/daoctl/views"
-	"github.com/hypha-dao/document-graph/docgraph"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
@@ -17,10 +16,10 @@ var getEdgesCmd = &cobra.Command{
 	Short: "query edges",
 	Long:  "query edges",
 	Run: func(cmd *cobra.Command, args []string) {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 
-		edges, err := docgraph.GetAllEdges(ctx, api, eos.AN(viper.GetString("DAOContract")))
+		edges, err := reader.GetAllEdges(ctx, eos.AN(viper.GetString("DAOContract")))
 		if err != nil {
 			panic(fmt.Errorf("cannot get all edges: %v", err))
 		}
@@ -0,0 +1,226 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/hypha-dao/daoctl/hyperion"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tidwall/gjson"
+)
+
+// historyActions are the DAO contract actions that create, change or link documents
+var historyActions = []string{"propose", "edit", "updatedoc", "closedocprop", "newedge", "removeedge"}
+
+// historyBlock is everything that happened to a document in one block
+type historyBlock struct {
+	BlockNum  uint32
+	Timestamp time.Time
+	Actions   []models.QrAction
+	Versions  []hyperion.Delta
+	Edges     []hyperion.Delta
+}
+
+var getHistoryCmd = &cobra.Command{
+	Use:   "history [hash | shorty | id | label]",
+	Short: "show the change history of a document",
+	Long: `show the change history of a document
+
+The versions of the document, the edges added to and removed from it, and the propose, edit,
+updatedoc, closedocprop, newedge and removeedge actions that touched it are read from Hyperion and
+printed as a timeline by block. Each new version shows the content items added (+), removed (-)
+and changed (- then +) since the previous version. The history is read from the created_date of
+the document on. A full hash is looked up in the history as is, so it may be a version that has
+since been edited or erased; as its creation is not known, the whole history of the documents
+table is then read.`,
+	Example: `  daoctl get history 96218188ba5909bbed3b0405436891522075edd5e2701e6b9b6eaa4c9f5987e3`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		contract := viper.GetString("DAOContract")
+		hash := strings.ToLower(args[0])
+		// the history starts when the document was created, or at the first delta of the table
+		// for a hash that is no longer on chain
+		var created time.Time
+		document, err := util.ResolveDocument(context.Background(), getReader(), eos.AN(contract), args[0])
+		if err == nil {
+			hash = document.Hash.String()
+			created = document.CreatedDate.Time.Add(-time.Second)
+		} else if len(hash) != 64 {
+			return err
+		}
+		now := time.Now().UTC()
+
+		documentDeltas := hyperion.NewDeltaQuery(contract, contract, "documents")
+		deltas, err := documentDeltas.Between(created, now)
+		if err != nil {
+			return fmt.Errorf("cannot get documents history from hyperion: %v", err)
+		}
+
+		keys := make(map[string]bool)
+		for _, delta := range deltas {
+			if gjson.GetBytes(delta.Data, "hash").String() == hash {
+				keys[delta.PrimaryKey] = true
+			}
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no history found for document: %v", hash)
+		}
+
+		blocks := make(map[uint32]*historyBlock)
+		block := func(blockNum uint32, timestamp time.Time) *historyBlock {
+			if _, found := blocks[blockNum]; !found {
+				blocks[blockNum] = &historyBlock{BlockNum: blockNum, Timestamp: timestamp}
+			}
+			return blocks[blockNum]
+		}
+
+		hashes := make(map[string]bool)
+		var first time.Time
+		for _, delta := range deltas {
+			if !keys[delta.PrimaryKey] {
+				continue
+			}
+			hashes[gjson.GetBytes(delta.Data, "hash").String()] = true
+			if first.IsZero() {
+				first = delta.Timestamp
+			}
+			b := block(delta.BlockNum, delta.Timestamp)
+			b.Versions = append(b.Versions, delta)
+		}
+
+		edgeDeltas := hyperion.NewDeltaQuery(contract, contract, "edges")
+		deltas, err = edgeDeltas.Between(first.Add(-time.Second), now)
+		if err != nil {
+			return fmt.Errorf("cannot get edges history from hyperion: %v", err)
+		}
+		for _, delta := range deltas {
+			if hashes[gjson.GetBytes(delta.Data, "from_node").String()] || hashes[gjson.GetBytes(delta.Data, "to_node").String()] {
+				b := block(delta.BlockNum, delta.Timestamp)
+				b.Edges = append(b.Edges, delta)
+			}
+		}
+
+		for _, actionName := range historyActions {
+			actions, err := getActionsSince(actionName, contract, first.Add(-time.Second))
+			if err != nil {
+				return fmt.Errorf("cannot get %v actions from hyperion: %v", actionName, err)
+			}
+			for _, action := range actions {
+				created := actionName == "propose" && blocks[action.BlockNum] != nil && len(blocks[action.BlockNum].Versions) > 0
+				if created || touchesHashes(action.RawData, hashes) {
+					b := block(action.BlockNum, action.Timestamp)
+					b.Actions = append(b.Actions, action)
+				}
+			}
+		}
+
+		var timeline []*historyBlock
+		for _, b := range blocks {
+			timeline = append(timeline, b)
+		}
+		sort.Slice(timeline, func(i, j int) bool { return timeline[i].BlockNum < timeline[j].BlockNum })
+		return printHistory(hash, hashes, timeline)
+	},
+}
+
+// getActionsSince returns every action of the contract with the name from the time on, oldest first
+func getActionsSince(actionName, contract string, after time.Time) ([]models.QrAction, error) {
+	query := hyperion.NewQuery(actionName, contract, "")
+	query.After = after.UTC()
+	query.Limit = 500
+	return query.Since()
+}
+
+func touchesHashes(data string, hashes map[string]bool) bool {
+	for hash := range hashes {
+		if strings.Contains(data, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+func printHistory(hash string, hashes map[string]bool, timeline []*historyBlock) error {
+	fmt.Printf("\nHistory of document %v\n", hash)
+
+	var previous *docgraph.Document
+	for _, b := range timeline {
+		fmt.Printf("\n%v  block %v\n", b.Timestamp.Format("2006 Jan 02 15:04:05"), b.BlockNum)
+
+		for _, action := range b.Actions {
+			fmt.Printf("  %-14v by %-13v trx %v\n", action.ActionName, action.Actor, action.TrxID)
+		}
+
+		for _, delta := range b.Versions {
+			if !delta.Present {
+				fmt.Printf("  removed %v\n", gjson.GetBytes(delta.Data, "hash").String())
+				previous = nil
+				continue
+			}
+
+			var document docgraph.Document
+			if err := json.Unmarshal(delta.Data, &document); err != nil {
+				return fmt.Errorf("cannot parse document version in block %v: %v", b.BlockNum, err)
+			}
+
+			if previous == nil {
+				fmt.Printf("  created %v\n", document.Hash.String())
+				printContentGroupList(document.ContentGroups, "    ")
+			} else {
+				if previous.Hash.String() != document.Hash.String() {
+					fmt.Printf("  updated %v -> %v\n", previous.Hash.String(), document.Hash.String())
+				} else {
+					fmt.Printf("  updated %v\n", document.Hash.String())
+				}
+				printFieldChanges(graph.DiffContent(previous.ContentGroups, document.ContentGroups))
+			}
+			previous = &document
+		}
+
+		for _, delta := range b.Edges {
+			change := "edge added  "
+			if !delta.Present {
+				change = "edge removed"
+			}
+			edgeName := gjson.GetBytes(delta.Data, "edge_name").String()
+			fromNode := gjson.GetBytes(delta.Data, "from_node").String()
+			toNode := gjson.GetBytes(delta.Data, "to_node").String()
+			if hashes[fromNode] {
+				fmt.Printf("  %v  %v -> %v\n", change, edgeName, toNode)
+			} else {
+				fmt.Printf("  %v  %v <- %v\n", change, edgeName, fromNode)
+			}
+		}
+	}
+	fmt.Println()
+	return nil
+}
+
+func printFieldChanges(changes []graph.FieldChange) {
+	if len(changes) == 0 {
+		fmt.Println("    no content changes")
+		return
+	}
+	for _, change := range changes {
+		if change.Before != nil {
+			printContentItem("    - ", change.Field(), change.Before.Value.String())
+		}
+		if change.After != nil {
+			printContentItem("    + ", change.Field(), change.After.Value.String())
+		}
+	}
+}
+
+func init() {
+	getCmd.AddCommand(getHistoryCmd)
+}
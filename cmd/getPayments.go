@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/models"
 	"github.com/hypha-dao/document-graph/docgraph"
 	"github.com/spf13/cobra"
@@ -33,12 +34,12 @@ var getPaymentsCmd = &cobra.Command{
 	Short: "retrieve list of payments",
 	// Args:  cobra.RangeArgs(1, 2),
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 		ctx := context.Background()
 		contract := eos.AN(viper.GetString("DAOContract"))
 
 		if viper.GetBool("get-payments-cmd-documents") {
-			paymentDocs, err := getDocumentsOfType(ctx, api, eos.Name("payment"))
+			paymentDocs, err := getDocumentsOfType(ctx, reader, eos.Name("payment"))
 			if err != nil {
 				return fmt.Errorf("cannot get all documents: %v", err)
 			}
@@ -70,14 +71,14 @@ var getPaymentsCmd = &cobra.Command{
 					pdr.PaymentDate = time.Unix(int64(paymentDateTimePoint)/1000000, 0).UTC()
 				}
 
-				edgesTo, err := docgraph.GetEdgesToDocumentWithEdge(ctx, api, contract, payment, eos.Name("payment"))
+				edgesTo, err := chain.GetEdgesToDocumentWithEdge(ctx, reader, contract, payment, eos.Name("payment"))
 				if err != nil {
 					return fmt.Errorf("cannot edges to payment with payment edge: %v", err)
 				}
 				zlog.Debug("loaded from edges for document", zap.String("payment-hash", payment.Hash.String()), zap.Int("edge-count", len(edgesTo)))
 
 				for _, edge := range edgesTo {
-					docFrom, err := docgraph.LoadDocument(ctx, api, contract, edge.FromNode.String())
+					docFrom, err := reader.LoadDocument(ctx, contract, edge.FromNode.String())
 					if err != nil {
 						return fmt.Errorf("cannot get document pointing to payment: %v", err)
 					}
@@ -85,7 +86,7 @@ var getPaymentsCmd = &cobra.Command{
 					docType, _ := docFrom.GetType()
 					typesOfFromNodes[docType]++
 					if docType == eos.Name("period") {
-						period, err := models.NewSinglePeriod(ctx, api, contract, docFrom)
+						period, err := models.NewSinglePeriod(ctx, reader, contract, docFrom)
 						if err != nil {
 							return fmt.Errorf("unable to load period: %v", err)
 						}
@@ -150,7 +151,7 @@ var getPaymentsCmd = &cobra.Command{
 			}
 			w.Flush()
 		} else {
-			payments, err := getAllPayments(ctx, api, eos.AN(viper.GetString("DAOContract")))
+			payments, err := getAllPayments(ctx, reader, eos.AN(viper.GetString("DAOContract")))
 			if err != nil {
 				panic(fmt.Errorf("cannot get all documents: %v", err))
 			}
@@ -197,7 +198,7 @@ type payment struct {
 	Memo         string             `json:"memo"`
 }
 
-func getRange(ctx context.Context, api *eos.API, contract eos.AccountName, id, count int) ([]payment, bool, error) {
+func getRange(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, id, count int) ([]payment, bool, error) {
 	var documents []payment
 	var request eos.GetTableRowsRequest
 	if id > 0 {
@@ -208,7 +209,7 @@ func getRange(ctx context.Context, api *eos.API, contract eos.AccountName, id, c
 	request.Table = "payments"
 	request.Limit = uint32(count)
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 	if err != nil {
 		return []payment{}, false, fmt.Errorf("get table rows %v", err)
 	}
@@ -220,20 +221,20 @@ func getRange(ctx context.Context, api *eos.API, contract eos.AccountName, id, c
 	return documents, response.More, nil
 }
 
-func getAllPayments(ctx context.Context, api *eos.API, contract eos.AccountName) ([]payment, error) {
+func getAllPayments(ctx context.Context, reader chain.ChainReader, contract eos.AccountName) ([]payment, error) {
 
 	var allPayments []payment
 
 	batchSize := 150
 
-	batch, more, err := getRange(ctx, api, contract, 0, batchSize)
+	batch, more, err := getRange(ctx, reader, contract, 0, batchSize)
 	if err != nil {
 		return []payment{}, fmt.Errorf("json to structs %v", err)
 	}
 	allPayments = append(allPayments, batch...)
 
 	for more {
-		batch, more, err = getRange(ctx, api, contract, int(batch[len(batch)-1].ID), batchSize)
+		batch, more, err = getRange(ctx, reader, contract, int(batch[len(batch)-1].ID), batchSize)
 		if err != nil {
 			return []payment{}, fmt.Errorf("json to structs %v", err)
 		}
@@ -243,21 +244,6 @@ func getAllPayments(ctx context.Context, api *eos.API, contract eos.AccountName)
 	return allPayments, nil
 }
 
-func getDocumentsOfType(ctx context.Context, api *eos.API, docType eos.Name) ([]docgraph.Document, error) {
-
-	docs, err := docgraph.GetAllDocuments(ctx, api, eos.AN(viper.GetString("DAOContract")))
-	if err != nil {
-		return []docgraph.Document{}, fmt.Errorf("cannot get all documents: %v", err)
-	}
-
-	var filteredDocs []docgraph.Document
-	for _, doc := range docs {
-
-		typeFV, err := doc.GetContent("type")
-		if err == nil &&
-			typeFV.Impl.(eos.Name) == docType {
-			filteredDocs = append(filteredDocs, doc)
-		}
-	}
-	return filteredDocs, nil
+func getDocumentsOfType(ctx context.Context, reader chain.ChainReader, docType eos.Name) ([]docgraph.Document, error) {
+	return chain.GetDocumentsOfType(ctx, reader, eos.AN(viper.GetString("DAOContract")), docType)
 }
@@ -0,0 +1,327 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+)
+
+var getProposalCmd = &cobra.Command{
+	Use:   "proposal [hash | shorty | id | label]",
+	Short: "show the ballot of a proposal",
+	Long: `show the ballot of a proposal
+
+The vote tally and the votes the proposal links to in the document graph are read from the graph
+cache, and the quorum and alignment thresholds from the DAO settings. Quorum is the share of the
+vote token supply that must be cast for any option; alignment is the share of the pass and fail
+vote power that must be pass votes.`,
+	Example: `  daoctl get proposal "Designer: erin"`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		proposal, err := gc.ResolveDocument(args[0])
+		if err != nil {
+			return err
+		}
+		ballot, err := loadProposalBallot(ctx, gc, proposal)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		fmt.Println("\n" + columnize.SimpleFormat(ballotSummary(ballot, now)) + "\n")
+
+		table := views.TallyTable(ballot)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(table.String() + "\n")
+
+		fmt.Println(columnize.SimpleFormat(ballotThresholds(ballot)) + "\n")
+		return nil
+	},
+}
+
+// The states of a proposal's ballot
+const (
+	proposalOpen    = "open"
+	proposalExpired = "expired"
+	proposalPassed  = "passed"
+	proposalFailed  = "failed"
+)
+
+// loadProposalBallot reads the ballot of the proposal from the graph cache, with the thresholds of
+// the DAO settings and the supply of the vote token
+func loadProposalBallot(ctx context.Context, gc *util.GraphCache, proposal docgraph.Document) (models.ProposalBallot, error) {
+	settings, err := cachedSettings(gc)
+	if err != nil {
+		return models.ProposalBallot{}, err
+	}
+	supply, err := voteTokenSupply(ctx)
+	if err != nil {
+		return models.ProposalBallot{}, err
+	}
+	return newProposalBallot(gc, proposal, settings, supply)
+}
+
+// voteTokenSupply returns the supply of the Telos Decide treasury the members' vote token
+// balances are read from
+func voteTokenSupply(ctx context.Context) (eos.Asset, error) {
+	supply, err := models.GetHvoiceSupply(ctx, getReader())
+	if err != nil {
+		return eos.Asset{}, fmt.Errorf("cannot get vote token supply, check TelosDecideContract and VoteTokenSymbol in the configuration: %v", err)
+	}
+	return *supply, nil
+}
+
+// newProposalBallot reads the ballot of the proposal from the graph cache: its latest vote tally,
+// the latest vote of each voter, and its state from the edges the DAO links to it with
+func newProposalBallot(gc *util.GraphCache, proposal docgraph.Document, settings models.Settings, supply eos.Asset) (models.ProposalBallot, error) {
+	var err error
+	ballot := models.ProposalBallot{
+		Proposal:  proposal,
+		Title:     proposalTitle(proposal),
+		Supply:    supply,
+		Quorum:    settings.VotingQuorum,
+		Alignment: settings.VotingAlignment,
+		Start:     proposal.CreatedDate.Time.UTC(),
+	}
+	ballot.Type, err = proposal.GetType()
+	if err != nil {
+		return models.ProposalBallot{}, fmt.Errorf("cannot get type of proposal: %v %v", proposal.Hash.String(), err)
+	}
+	ballot.End = ballotEnd(proposal, settings)
+
+	hash := proposal.Hash.String()
+	tallies, err := linkedDocuments(gc, hash, "votetally")
+	if err != nil {
+		return models.ProposalBallot{}, err
+	}
+	if len(tallies) > 0 {
+		ballot.Tally, err = models.NewVoteTally(tallies[len(tallies)-1])
+		if err != nil {
+			return models.ProposalBallot{}, err
+		}
+	}
+
+	ballot.Votes, err = latestVotes(gc, hash)
+	if err != nil {
+		return models.ProposalBallot{}, err
+	}
+
+	if len(tallies) == 0 {
+		ballot.Tally = tallyVotes(ballot.Votes, supply.Symbol)
+	}
+
+	ballot.Status, err = proposalStatus(gc, proposal, ballot.Type, ballot.End)
+	if err != nil {
+		return models.ProposalBallot{}, err
+	}
+	return ballot, nil
+}
+
+// ballotEnd returns the time voting on the proposal closes: its ballot expiration, or the voting
+// duration of the settings after it was created
+func ballotEnd(proposal docgraph.Document, settings models.Settings) time.Time {
+	if expiration, err := groupItem(proposal, "system", "ballot_expiration"); err == nil {
+		if timePoint, err := expiration.TimePoint(); err == nil {
+			return time.Unix(0, int64(timePoint)*1000).UTC()
+		}
+	}
+	return proposal.CreatedDate.Time.UTC().Add(time.Duration(settings.VotingDurationSec) * time.Second)
+}
+
+// latestVotes returns the latest vote of each voter on the proposal, ordered by vote power
+func latestVotes(gc *util.GraphCache, hash string) ([]models.ProposalVote, error) {
+	voteDocs, err := linkedDocuments(gc, hash, "vote")
+	if err != nil {
+		return nil, err
+	}
+	latest := make(map[eos.Name]models.ProposalVote)
+	for _, voteDoc := range voteDocs {
+		vote, err := models.NewProposalVote(voteDoc)
+		if err != nil {
+			return nil, err
+		}
+		if previous, found := latest[vote.Voter]; !found || !vote.Date.Before(previous.Date) {
+			latest[vote.Voter] = vote
+		}
+	}
+
+	var votes []models.ProposalVote
+	for _, vote := range latest {
+		votes = append(votes, vote)
+	}
+	sort.Slice(votes, func(i, j int) bool {
+		if votes[i].VotePower.Amount != votes[j].VotePower.Amount {
+			return votes[i].VotePower.Amount > votes[j].VotePower.Amount
+		}
+		return votes[i].Voter < votes[j].Voter
+	})
+	return votes, nil
+}
+
+// linkedDocuments returns the documents the edges with the name from the document point to, in
+// the order they were created
+func linkedDocuments(gc *util.GraphCache, hash string, edgeName eos.Name) ([]docgraph.Document, error) {
+	edges, err := gc.GetEdgesFrom(hash, edgeName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot get %v edges from document: %v %v", edgeName, hash, err)
+	}
+
+	var documents []docgraph.Document
+	for _, edge := range edges {
+		document, found, err := gc.GetDocument(edge.ToNode.String())
+		if err != nil {
+			return nil, err
+		}
+		if found {
+			documents = append(documents, document)
+		}
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].ID < documents[j].ID })
+	return documents, nil
+}
+
+func tallyVotes(votes []models.ProposalVote, symbol eos.Symbol) models.VoteTally {
+	tally := models.VoteTally{
+		Pass:    eos.Asset{Symbol: symbol},
+		Fail:    eos.Asset{Symbol: symbol},
+		Abstain: eos.Asset{Symbol: symbol},
+	}
+	for _, vote := range votes {
+		switch vote.Vote {
+		case models.VotePass:
+			tally.Pass.Amount += vote.VotePower.Amount
+		case models.VoteFail:
+			tally.Fail.Amount += vote.VotePower.Amount
+		case models.VoteAbstain:
+			tally.Abstain.Amount += vote.VotePower.Amount
+		}
+	}
+	return tally
+}
+
+// proposalStatus returns the state of the proposal's ballot from the edges of the DAO to it: a
+// passed or failed proposal is linked as such or, once approved, by its type
+func proposalStatus(gc *util.GraphCache, proposal docgraph.Document, docType eos.Name, end time.Time) (string, error) {
+	edges, err := gc.GetEdgesTo(proposal.Hash.String(), "")
+	if err != nil {
+		return "", fmt.Errorf("cannot get edges to proposal: %v %v", proposal.Hash.String(), err)
+	}
+
+	open := false
+	for _, edge := range edges {
+		switch edge.EdgeName {
+		case eos.Name("failedprops"):
+			return proposalFailed, nil
+		case eos.Name("passedprops"), docType:
+			if from, found, err := gc.GetDocument(edge.FromNode.String()); err == nil && found {
+				if fromType, err := from.GetType(); err == nil && fromType == eos.Name("dho") {
+					return proposalPassed, nil
+				}
+			}
+		case eos.Name("proposal"):
+			open = true
+		}
+	}
+	if open && time.Now().UTC().After(end) {
+		return proposalExpired, nil
+	}
+	if open {
+		return proposalOpen, nil
+	}
+	return "unknown", nil
+}
+
+func proposalTitle(proposal docgraph.Document) string {
+	for _, label := range []string{"title", "node_label"} {
+		for _, group := range []string{"details", "system"} {
+			if value, err := groupItem(proposal, group, label); err == nil {
+				return value.String()
+			}
+		}
+	}
+	return ""
+}
+
+func groupItem(document docgraph.Document, group, label string) (*docgraph.FlexValue, error) {
+	contentGroup, err := document.GetContentGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	return contentGroup.GetContent(label)
+}
+
+// ballotSummary returns columnize rows describing the proposal and its voting period
+func ballotSummary(ballot models.ProposalBallot, now time.Time) []string {
+	remaining := "voting has ended"
+	if d := ballot.Remaining(now); d > 0 {
+		remaining = formatRemaining(d)
+	}
+	return []string{
+		fmt.Sprintf("Proposal|%v", ballot.Proposal.Hash.String()),
+		fmt.Sprintf("Type|%v", ballot.Type),
+		fmt.Sprintf("Title|%v", ballot.Title),
+		fmt.Sprintf("Proposer|%v", ballot.Proposal.Creator),
+		fmt.Sprintf("Status|%v", ballot.Status),
+		fmt.Sprintf("Voting Opened|%v", ballot.Start.Format("2006 Jan 02 15:04:05")),
+		fmt.Sprintf("Voting Closes|%v", ballot.End.Format("2006 Jan 02 15:04:05")),
+		fmt.Sprintf("Time Remaining|%v", remaining),
+	}
+}
+
+// ballotThresholds returns columnize rows comparing the ballot with the quorum and alignment
+func ballotThresholds(ballot models.ProposalBallot) []string {
+	cast := ballot.Cast()
+	quorumVotes := ballot.QuorumVotes()
+	castShare := 0.0
+	if ballot.Supply.Amount > 0 {
+		castShare = float64(cast.Amount) / float64(ballot.Supply.Amount)
+	}
+	return []string{
+		fmt.Sprintf("Vote Supply|%v", util.FormatAsset(&ballot.Supply, 2)),
+		fmt.Sprintf("Quorum|%v%% of supply, %v|cast %v, %v%%|%v", percentText(ballot.Quorum), util.FormatAsset(&quorumVotes, 2),
+			util.FormatAsset(&cast, 2), percentText(castShare), metText(ballot.QuorumMet())),
+		fmt.Sprintf("Alignment|%v%% of pass and fail votes|pass %v%%|%v", percentText(ballot.Alignment),
+			percentText(ballot.PassShare()), metText(ballot.Aligned())),
+		fmt.Sprintf("Passing?|%v", ballot.Passing()),
+	}
+}
+
+func metText(met bool) string {
+	if met {
+		return "met"
+	}
+	return "not met"
+}
+
+// formatRemaining formats a duration in days, hours and minutes
+func formatRemaining(d time.Duration) string {
+	d = d.Round(time.Minute)
+	days := d / (24 * time.Hour)
+	hours := (d % (24 * time.Hour)) / time.Hour
+	minutes := (d % time.Hour) / time.Minute
+	if days > 0 {
+		return fmt.Sprintf("%dd %dh %dm", days, hours, minutes)
+	}
+	return fmt.Sprintf("%dh %dm", hours, minutes)
+}
+
+func init() {
+	getCmd.AddCommand(getProposalCmd)
+}
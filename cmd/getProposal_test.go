@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/spf13/viper"
+)
+
+// useTestFixture points the reader and the graph cache of the commands at the fixture of the
+// models package and a new cache file
+func useTestFixture(t *testing.T) {
+	t.Helper()
+	viper.Set("global-fixture", "../models/testdata/dao.hypha.json")
+	viper.Set("GraphCacheFile", filepath.Join(t.TempDir(), "cache.db"))
+	viper.Set("DAOContract", "dao.hypha")
+	viper.Set("TelosDecideContract", "trailservice")
+	viper.Set("VoteTokenSymbol", "HVOICE")
+	activeReader = nil
+	t.Cleanup(func() {
+		viper.Reset()
+		activeReader = nil
+	})
+}
+
+func TestLoadProposalBallot(t *testing.T) {
+	useTestFixture(t)
+	ctx := context.Background()
+	gc, err := getGraphCache(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer gc.Close()
+
+	proposal, err := gc.ResolveDocument("Designer: erin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ballot, err := loadProposalBallot(ctx, gc, proposal)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ballot.Type != "assignment" || ballot.Title != "Designer erin" || ballot.Proposal.Creator != "erin" {
+		t.Errorf("got %v proposal %q by %v", ballot.Type, ballot.Title, ballot.Proposal.Creator)
+	}
+	// the supply is the HVOICE treasury of trailservice
+	if ballot.Supply.String() != "1000.00 HVOICE" || ballot.Quorum != 0.2 || ballot.Alignment != 0.8 {
+		t.Errorf("got supply %v, quorum %v, alignment %v", ballot.Supply, ballot.Quorum, ballot.Alignment)
+	}
+	if ballot.Tally.Pass.String() != "300.00 HVOICE" || ballot.Tally.Fail.String() != "50.00 HVOICE" ||
+		ballot.Tally.Abstain.String() != "0.00 HVOICE" {
+		t.Errorf("got tally %v pass, %v fail, %v abstain", ballot.Tally.Pass, ballot.Tally.Fail, ballot.Tally.Abstain)
+	}
+	if len(ballot.Votes) != 2 || ballot.Votes[0].Voter != "alice" || ballot.Votes[0].Vote != models.VotePass ||
+		ballot.Votes[1].Voter != "bob" || ballot.Votes[1].Vote != models.VoteFail {
+		t.Errorf("got votes %+v", ballot.Votes)
+	}
+	if end := time.Date(2026, 10, 22, 0, 0, 0, 0, time.UTC); !ballot.End.Equal(end) {
+		t.Errorf("got end %v, want %v", ballot.End, end)
+	}
+	status := proposalOpen
+	if time.Now().After(ballot.End) {
+		status = proposalExpired
+	}
+	if ballot.Status != status {
+		t.Errorf("got status %v, want %v", ballot.Status, status)
+	}
+
+	thresholds := ballotThresholds(ballot)
+	want := []string{
+		"Vote Supply|1000.00 HVOICE",
+		"Quorum|20% of supply, 200.00 HVOICE|cast 350.00 HVOICE, 35%|met",
+		"Alignment|80% of pass and fail votes|pass 85.71%|met",
+		"Passing?|true",
+	}
+	for i := range want {
+		if thresholds[i] != want[i] {
+			t.Errorf("got threshold %q, want %q", thresholds[i], want[i])
+		}
+	}
+}
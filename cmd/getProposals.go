@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/spf13/cobra"
+)
+
+var getProposalsCmd = &cobra.Command{
+	Use:   "proposals",
+	Short: "print a table of the open proposals",
+	Long: `print a table of the open proposals with their current tally and whether they meet the quorum
+and would pass if voting closed now
+
+Proposals whose voting period has ended but that have not been closed are shown as expired; close
+them with 'daoctl close --all'. Proposals and votes are read from the graph cache.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		ballots, err := openBallots(ctx, gc)
+		if err != nil {
+			return err
+		}
+
+		table := views.ProposalTable(ballots)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("\nOpen Proposals\n\n" + table.String() + "\n")
+
+		expired := 0
+		for _, ballot := range ballots {
+			if ballot.Status == proposalExpired {
+				expired++
+			}
+		}
+		if expired > 0 {
+			fmt.Printf("%v proposals have ended voting and can be closed with 'daoctl close --all'\n\n", expired)
+		}
+		return nil
+	},
+}
+
+// openBallots returns the ballots of the proposals the DAO links to with proposal edges that have
+// not been closed, ordered by the time voting closes
+func openBallots(ctx context.Context, gc *util.GraphCache) ([]models.ProposalBallot, error) {
+	settings, err := cachedSettings(gc)
+	if err != nil {
+		return nil, err
+	}
+	supply, err := voteTokenSupply(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	edges, err := gc.GetEdgesByName(eos.Name("proposal"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get proposal edges: %v", err)
+	}
+
+	var ballots []models.ProposalBallot
+	seen := make(map[string]bool)
+	for _, edge := range edges {
+		hash := edge.ToNode.String()
+		if seen[hash] {
+			continue
+		}
+		from, found, err := gc.GetDocument(edge.FromNode.String())
+		if err != nil || !found {
+			continue
+		}
+		if fromType, err := from.GetType(); err != nil || fromType != eos.Name("dho") {
+			continue
+		}
+		proposal, found, err := gc.GetDocument(hash)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			continue
+		}
+		seen[hash] = true
+
+		ballot, err := newProposalBallot(gc, proposal, settings, supply)
+		if err != nil {
+			return nil, err
+		}
+		if ballot.Status == proposalOpen || ballot.Status == proposalExpired {
+			ballots = append(ballots, ballot)
+		}
+	}
+	sort.Slice(ballots, func(i, j int) bool { return ballots[i].End.Before(ballots[j].End) })
+	return ballots, nil
+}
+
+func init() {
+	getCmd.AddCommand(getProposalsCmd)
+}
@@ -0,0 +1,132 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var getRolesCmd = &cobra.Command{
+	Use:   "roles [--utilisation]",
+	Short: "print a table of the roles",
+	Long: `print a table of the approved roles, and with --include-proposals the proposed roles
+
+With --utilisation each approved role's full time capacity is compared with the time shares of
+the approved assignments linked to it: those active now, those yet to start, and the capacity
+left for new assignments, with the annual cost of the role at full capacity against the cost of
+the committed time share. Roles and assignments are read from the graph cache.`,
+	Example: `  daoctl get roles
+  daoctl get roles --utilisation`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		approved, proposed, err := cachedRoles(gc)
+		if err != nil {
+			return err
+		}
+
+		if viper.GetBool("get-roles-cmd-utilisation") {
+			now := time.Now().UTC()
+			var utilisations []models.RoleUtilisation
+			for _, role := range approved {
+				assignments, err := roleAssignments(ctx, gc, role.Hash.String())
+				if err != nil {
+					return err
+				}
+
+				u := models.RoleUtilisation{Role: role}
+				for _, assignment := range assignments {
+					switch {
+					case assignment.Ended(now):
+					case assignment.Started(now):
+						u.Active += assignment.TimeShare
+						u.ActiveAssignments++
+					default:
+						u.Upcoming += assignment.TimeShare
+					}
+				}
+				utilisations = append(utilisations, u)
+			}
+
+			table := views.RoleUtilisationTable(utilisations)
+			table.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println("\nRole Utilisation\n\n" + table.String() + "\n")
+			return nil
+		}
+
+		table := views.RoleTable(approved)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("\nApproved Roles\n\n" + table.String() + "\n")
+
+		if viper.GetBool("global-include-proposals") {
+			table := views.RoleTable(proposed)
+			table.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println("\nProposed Roles\n\n" + table.String() + "\n")
+		}
+		return nil
+	},
+}
+
+// cachedRoles returns the roles in the graph cache sorted by title, split into the approved roles,
+// which the DAO links to with a role edge, and the others
+func cachedRoles(gc *util.GraphCache) ([]models.Role, []models.Role, error) {
+	documents, err := gc.GetDocumentsByType(eos.Name("role"))
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot get roles: %v", err)
+	}
+
+	var approved, proposed []models.Role
+	for _, document := range documents {
+		role, err := models.NewRole(document)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		edges, err := gc.GetEdgesTo(document.Hash.String(), eos.Name("role"))
+		if err != nil {
+			return nil, nil, fmt.Errorf("cannot get edges to role: %v %v", document.Hash.String(), err)
+		}
+		for _, edge := range edges {
+			from, found, err := gc.GetDocument(edge.FromNode.String())
+			if err != nil || !found {
+				continue
+			}
+			if fromType, err := from.GetType(); err == nil && fromType == eos.Name("dho") {
+				role.Approved = true
+			}
+		}
+
+		if role.Approved {
+			approved = append(approved, role)
+		} else {
+			proposed = append(proposed, role)
+		}
+	}
+
+	byTitle := func(roles []models.Role) {
+		sort.Slice(roles, func(i, j int) bool { return roles[i].Title < roles[j].Title })
+	}
+	byTitle(approved)
+	byTitle(proposed)
+	return approved, proposed, nil
+}
+
+func init() {
+	getCmd.AddCommand(getRolesCmd)
+	getRolesCmd.Flags().BoolP("utilisation", "u", false, "compare each role's capacity with the time shares of its assignments")
+}
@@ -20,7 +20,7 @@ var getTreasuryCmd = &cobra.Command{
 	Short: "retrieve multi-chain balance information for the treasury",
 	// Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		reader := getReader()
 
 		addlBalance, err := eos.NewAssetFromString(viper.GetString("get-treasury-cmd-addl-balance"))
 		if err != nil {
@@ -29,13 +29,13 @@ var getTreasuryCmd = &cobra.Command{
 		}
 
 		accountName := toAccount(viper.GetString("Treasury.Contract"), "treasury contract account name")
-		account, err := api.GetAccount(context.Background(), accountName)
+		account, err := reader.GetAccount(context.Background(), accountName)
 		printTreasurers(account)
 
 		// config := models.LoadTreasConfig(context.Background(), api)
 		// fmt.Println(config)
 
-		treasury := models.Load(api, viper.GetString("Treasury.Contract"), viper.GetString("Treasury.TokenContract"), viper.GetString("Treasury.Symbol"))
+		treasury := models.Load(reader, viper.GetString("Treasury.Contract"), viper.GetString("Treasury.TokenContract"), viper.GetString("Treasury.Symbol"))
 
 		fmt.Println()
 		treasuryConfig := []string{
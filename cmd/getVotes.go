@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/spf13/cobra"
+)
+
+var getVotesCmd = &cobra.Command{
+	Use:   "votes [hash | shorty | id | label]",
+	Short: "print a table of the votes on a proposal",
+	Long: `print a table of the votes on a proposal
+
+Each member's latest vote is listed, ordered by vote power. Votes are read from the graph cache.`,
+	Example: `  daoctl get votes "Designer: erin"`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		proposal, err := gc.ResolveDocument(args[0])
+		if err != nil {
+			return err
+		}
+		ballot, err := loadProposalBallot(ctx, gc, proposal)
+		if err != nil {
+			return err
+		}
+
+		table := views.ProposalVotesTable(ballot.Votes)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Printf("\nVotes on %v (%v)\n\n%v\n\n", ballot.Title, proposal.Hash.String()[:5], table.String())
+		return nil
+	},
+}
+
+func init() {
+	getCmd.AddCommand(getVotesCmd)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+// graphCmd represents the graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "query and explore the document graph from the local cache",
+	Long: `query and explore the document graph from the local cache
+
+The graph cache is synced with the chain once per command.`,
+}
+
+// getGraphCache opens the graph cache of the selected network and contract and syncs it
+func getGraphCache(ctx context.Context) (*util.GraphCache, error) {
+	return util.GetCache(ctx, getReader(), eos.AN(viper.GetString("DAOContract")))
+}
+
+func init() {
+	RootCmd.AddCommand(graphCmd)
+}
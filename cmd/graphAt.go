@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/backup"
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/hypha-dao/daoctl/hyperion"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphAtCmd = &cobra.Command{
+	Use:   "at [expression]",
+	Short: "rebuild the document graph as of a past block or time",
+	Long: `rebuild the document graph as of a past block or time
+
+The documents and edges tables of the DAO contract are rebuilt from Hyperion history by replaying
+every row written or removed by the create, update and delete document and edge actions up to the
+block or time. Without an expression the document types and edge names are counted; with one, the
+matching documents are listed as in 'graph query'. Use --out to save the rebuilt graph as a backup
+that 'backup diff' can compare with another.`,
+	Example: `  daoctl graph at --time 2021-03-01 'type=assignment' --fields details.assignee,details.role
+  daoctl graph at --block 120000000 --out ./graph-at-120000000`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		block := uint32(viper.GetInt64("graph-at-cmd-block"))
+		var at time.Time
+		if atValue := viper.GetString("graph-at-cmd-time"); atValue != "" {
+			var ok bool
+			if at, ok = graph.ParseTime(atValue); !ok {
+				return fmt.Errorf("cannot parse time: %v, expected a date such as 2021-03-01 or 2021-03-01T12:00:00", atValue)
+			}
+		}
+		if (block == 0) == at.IsZero() {
+			return fmt.Errorf("specify either --block or --time")
+		}
+
+		var query *graph.Query
+		if len(args) > 0 {
+			var err error
+			if query, err = graph.Parse(strings.Join(args, " ")); err != nil {
+				return fmt.Errorf("cannot parse query: %v", err)
+			}
+		}
+
+		documents, edges, lastBlock, err := rebuildGraph(eos.AN(viper.GetString("DAOContract")), block, at)
+		if err != nil {
+			return err
+		}
+
+		asOf := fmt.Sprintf("block %v", block)
+		if block == 0 {
+			asOf = at.Format("2006-01-02 15:04:05") + " UTC"
+		}
+		fmt.Printf("\nGraph as of %v: %v documents and %v edges, last changed in block %v\n", asOf, len(documents), len(edges), lastBlock)
+
+		if out := viper.GetString("graph-at-cmd-out"); out != "" {
+			if err := os.MkdirAll(out, 0777); err != nil {
+				return fmt.Errorf("cannot create output folder: %v", err)
+			}
+			info, err := getReader().GetInfo(context.Background())
+			if err != nil {
+				return fmt.Errorf("cannot get chain info: %v", err)
+			}
+			_, err = backup.Write(out, backup.Manifest{
+				CreatedAt: time.Now().UTC(),
+				ChainID:   info.ChainID.String(),
+				Contract:  viper.GetString("DAOContract"),
+				HeadBlock: lastBlock,
+			}, documents, edges)
+			if err != nil {
+				return fmt.Errorf("cannot write backup: %v", err)
+			}
+			fmt.Printf("Saved to %v\n", out)
+		}
+
+		if query == nil {
+			documentTypes := make(map[string]int)
+			for _, document := range documents {
+				docType, err := document.GetType()
+				if err != nil {
+					docType = "unknown"
+				}
+				documentTypes[string(docType)]++
+			}
+			edgeNames := make(map[string]int)
+			for _, edge := range edges {
+				edgeNames[string(edge.EdgeName)]++
+			}
+
+			typeTable := views.CountTable("Document Type", documentTypes)
+			typeTable.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println("\n" + typeTable.String() + "\n")
+			edgeTable := views.CountTable("Edge Name", edgeNames)
+			edgeTable.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println(edgeTable.String() + "\n")
+			return nil
+		}
+
+		matches, err := query.Run(graph.NewMemory(documents, edges))
+		if err != nil {
+			return err
+		}
+
+		var fields []string
+		for _, field := range strings.Split(viper.GetString("graph-at-cmd-fields"), ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+		table := views.FieldTable(matches, fields)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("\n" + table.String() + "\n")
+		fmt.Printf("%v documents\n\n", len(matches))
+		return nil
+	},
+}
+
+// rebuildGraph replays the Hyperion deltas of the contract's documents and edges tables up to the
+// block, or the time when block is 0, and returns the rows present at that point with the last
+// block that changed them
+func rebuildGraph(contract eos.AccountName, block uint32, at time.Time) ([]docgraph.Document, []docgraph.Edge, uint32, error) {
+	var lastBlock uint32
+	rows := make(map[string]map[string]json.RawMessage)
+	for _, table := range []string{"documents", "edges"} {
+		query := hyperion.NewDeltaQuery(string(contract), string(contract), table)
+		deltas, err := query.Until(block, at)
+		if err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot get %v history from hyperion: %v", table, err)
+		}
+
+		rows[table] = make(map[string]json.RawMessage)
+		for _, delta := range deltas {
+			if delta.Present {
+				rows[table][delta.PrimaryKey] = delta.Data
+			} else {
+				delete(rows[table], delta.PrimaryKey)
+			}
+			if delta.BlockNum > lastBlock {
+				lastBlock = delta.BlockNum
+			}
+		}
+	}
+
+	documents := make([]docgraph.Document, 0, len(rows["documents"]))
+	for key, data := range rows["documents"] {
+		var document docgraph.Document
+		if err := json.Unmarshal(data, &document); err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot parse document %v from hyperion: %v", key, err)
+		}
+		documents = append(documents, document)
+	}
+
+	edges := make([]docgraph.Edge, 0, len(rows["edges"]))
+	for key, data := range rows["edges"] {
+		var edge docgraph.Edge
+		if err := json.Unmarshal(data, &edge); err != nil {
+			return nil, nil, 0, fmt.Errorf("cannot parse edge %v from hyperion: %v", key, err)
+		}
+		edges = append(edges, edge)
+	}
+	return documents, edges, lastBlock, nil
+}
+
+func init() {
+	graphCmd.AddCommand(graphAtCmd)
+	graphAtCmd.Flags().Int64P("block", "", 0, "rebuild the graph as of this block number")
+	graphAtCmd.Flags().StringP("time", "", "", "rebuild the graph as of this UTC date or time, e.g. 2021-03-01 or 2021-03-01T12:00:00")
+	graphAtCmd.Flags().StringP("fields", "", "", "comma separated fields to add as columns, e.g. details.assignee")
+	graphAtCmd.Flags().StringP("out", "", "", "save the rebuilt graph as a backup in this folder")
+}
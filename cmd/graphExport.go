@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphExportCmd = &cobra.Command{
+	Use:   "export [root]",
+	Short: "export the document graph for Gephi, Neo4j or Graphviz",
+	Long: `export the document graph for Gephi, Neo4j or Graphviz
+
+Writes documents, labelled with their node label and type, and edges, labelled with their edge name,
+as Graphviz dot, GraphML, Cypher statements or JSON Graph Format. Without a root the whole DAO is
+exported; with a root only the documents within --hops edges of it.`,
+	Example: `  daoctl graph export --format dot Developer --hops 2 | dot -Tsvg > developer.svg
+  daoctl graph export --format graphml --type member,role,assignment > dao.graphml
+  daoctl graph export --format cypher --edge-name assigned,role | cypher-shell`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format := viper.GetString("graph-export-cmd-format")
+		if err := graph.CheckExportFormat(format); err != nil {
+			return err
+		}
+
+		gc, err := getGraphCache(context.Background())
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		filter := graph.SubgraphFilter{
+			Types:     viper.GetStringSlice("graph-export-cmd-type"),
+			EdgeNames: viper.GetStringSlice("graph-export-cmd-edge-name"),
+		}
+
+		var subgraph graph.Subgraph
+		if len(args) == 1 {
+			root, err := gc.ResolveDocument(args[0])
+			if err != nil {
+				return err
+			}
+			subgraph, err = graph.Neighbourhood(gc, root, viper.GetInt("graph-export-cmd-hops"), filter)
+			if err != nil {
+				return err
+			}
+		} else {
+			documents, err := gc.GetAllDocuments()
+			if err != nil {
+				return fmt.Errorf("cannot get all documents: %v", err)
+			}
+			edges, err := gc.GetAllEdges()
+			if err != nil {
+				return fmt.Errorf("cannot get all edges: %v", err)
+			}
+			subgraph = graph.WholeGraph(documents, edges, filter)
+		}
+
+		out := os.Stdout
+		if fileName := viper.GetString("graph-export-cmd-out"); fileName != "" {
+			out, err = os.Create(fileName)
+			if err != nil {
+				return fmt.Errorf("cannot create export file: %v", err)
+			}
+			defer out.Close()
+		}
+
+		err = graph.Export(out, subgraph, format)
+		if err != nil {
+			return err
+		}
+		if out != os.Stdout {
+			fmt.Printf("Exported %v documents and %v edges to %v\n", len(subgraph.Documents), len(subgraph.Edges), out.Name())
+		}
+		return nil
+	},
+}
+
+func init() {
+	graphCmd.AddCommand(graphExportCmd)
+	graphExportCmd.Flags().StringP("format", "", "dot", "export format: "+strings.Join(graph.ExportFormats, ", "))
+	graphExportCmd.Flags().IntP("hops", "", 1, "number of edges to follow from the root in either direction")
+	graphExportCmd.Flags().StringSliceP("type", "", nil, "only export documents of these types")
+	graphExportCmd.Flags().StringSliceP("edge-name", "", nil, "only export edges with these names")
+	graphExportCmd.Flags().StringP("out", "", "", "write the export to this file instead of stdout")
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphPathCmd = &cobra.Command{
+	Use:   "path <from> <to>",
+	Short: "find how two documents are connected",
+	Long: `find how two documents are connected
+
+Prints the shortest path of edges between the two documents, or with --all every path that visits
+no document twice, up to --max-depth hops. Edges are followed from their from node to their to
+node unless --undirected is set. The search for all paths stops after --limit paths or --timeout,
+printing the paths found so far. Documents are referenced by hash, hash prefix, ID or label.`,
+	Example: `  daoctl graph path "payment carol 0" 7 --undirected
+  daoctl graph path bob Developer --all --max-depth 3`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gc, err := getGraphCache(context.Background())
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		from, err := gc.ResolveDocument(args[0])
+		if err != nil {
+			return err
+		}
+		to, err := gc.ResolveDocument(args[1])
+		if err != nil {
+			return err
+		}
+
+		edges, err := gc.GetAllEdges()
+		if err != nil {
+			return fmt.Errorf("cannot get all edges: %v", err)
+		}
+		adjacency := graph.NewAdjacency(edges)
+		directed := !viper.GetBool("graph-path-cmd-undirected")
+
+		var paths []graph.Path
+		var searchErr error
+		if viper.GetBool("graph-path-cmd-all") {
+			ctx, cancel := context.WithTimeout(context.Background(), viper.GetDuration("graph-path-cmd-timeout"))
+			defer cancel()
+			paths, searchErr = adjacency.AllPaths(ctx, from.Hash.String(), to.Hash.String(), directed,
+				viper.GetInt("graph-path-cmd-max-depth"), viper.GetInt("graph-path-cmd-limit"))
+		} else if path, found := adjacency.ShortestPath(from.Hash.String(), to.Hash.String(), directed); found {
+			paths = append(paths, path)
+		}
+
+		if searchErr != nil {
+			fmt.Fprintf(os.Stderr, "Search stopped after %v, showing the paths found so far; lower --max-depth or raise --timeout\n",
+				viper.GetDuration("graph-path-cmd-timeout"))
+		}
+		if len(paths) == 0 {
+			return fmt.Errorf("no path from %v (%v) to %v (%v)", getLabel(&from), getType(&from), getLabel(&to), getType(&to))
+		}
+
+		for i, path := range paths {
+			fmt.Printf("\nPath %v: %v hops\n\n", i+1, len(path))
+			err = printPath(gc, &from, path)
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Println()
+		return nil
+	},
+}
+
+// printPath prints the starting document and then one line per hop with the edge name and the
+// label and type of the document it arrives at, like the edge choices of the document navigator
+func printPath(gc *util.GraphCache, from *docgraph.Document, path graph.Path) error {
+	fmt.Printf("  %-60v %v\n", getLabel(from)+" ("+getType(from)+")", from.Hash.String())
+
+	for _, hop := range path {
+		document, found, err := gc.GetDocument(hop.To())
+		if err != nil {
+			return err
+		}
+		node := "missing document"
+		if found {
+			node = getLabel(&document) + " (" + getType(&document) + ")"
+		}
+
+		arrow := "--->"
+		if !hop.Forward {
+			arrow = "<---"
+		}
+		fmt.Printf("      %v %-12v %v %-40v %v\n", arrow, string(hop.Edge.EdgeName), arrow, node, hop.To())
+	}
+	return nil
+}
+
+func init() {
+	graphCmd.AddCommand(graphPathCmd)
+	graphPathCmd.Flags().BoolP("all", "", false, "print every simple path up to --max-depth hops instead of only the shortest")
+	graphPathCmd.Flags().IntP("max-depth", "", 4, "maximum number of hops of the paths printed with --all")
+	graphPathCmd.Flags().IntP("limit", "", 20, "maximum number of paths printed with --all")
+	graphPathCmd.Flags().DurationP("timeout", "", 10*time.Second, "time to search for paths with --all before printing those found")
+	graphPathCmd.Flags().BoolP("undirected", "", false, "also follow edges backward, from their to node to their from node")
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphQueryCmd = &cobra.Command{
+	Use:   "query <expression>",
+	Short: "find documents with a filter and edge traversal expression",
+	Long: `find documents with a filter and edge traversal expression
+
+A query is an optional filter followed by edge steps. A filter compares document fields with values,
+combined with and, or, not and parentheses. The operators are = != > >= < <= and ~ (contains).
+
+Fields are id, hash, creator, type, label, created, a content item as group.label, or a bare content
+label found in any content group. Numbers, assets and dates compare by value. A step is -> <edge name>
+to follow outgoing edges or <- <edge name> to follow incoming edges, * matching any edge name, and may
+be followed by a filter on the documents it reaches.`,
+	Example: `  daoctl graph query 'type=assignment and details.assignee=alice and created>2021-01-01'
+  daoctl graph query 'type=member and label=bob -> assigned -> role'
+  daoctl graph query 'type=role <- role type=assignment' --output csv --fields details.assignee,details.time_share_x100`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query, err := graph.Parse(strings.Join(args, " "))
+		if err != nil {
+			return fmt.Errorf("cannot parse query: %v", err)
+		}
+
+		gc, err := getGraphCache(context.Background())
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		documents, err := query.Run(gc)
+		if err != nil {
+			return err
+		}
+
+		var fields []string
+		for _, field := range strings.Split(viper.GetString("graph-query-cmd-fields"), ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				fields = append(fields, field)
+			}
+		}
+
+		switch output := viper.GetString("graph-query-cmd-output"); output {
+		case "table":
+			table := views.FieldTable(documents, fields)
+			table.SetStyle(simpletable.StyleCompactLite)
+			fmt.Println("\n" + table.String() + "\n")
+			fmt.Printf("%v documents\n\n", len(documents))
+		case "json":
+			data, err := json.MarshalIndent(documents, "", "  ")
+			if err != nil {
+				return fmt.Errorf("cannot marshal documents: %v", err)
+			}
+			fmt.Println(string(data))
+		case "csv":
+			return writeDocumentsCSV(documents, fields)
+		default:
+			return fmt.Errorf("unknown output format: %v, expected table, json or csv", output)
+		}
+		return nil
+	},
+}
+
+func writeDocumentsCSV(documents []docgraph.Document, fields []string) error {
+	w := csv.NewWriter(os.Stdout)
+	columns := append([]string{"id", "hash", "type", "label", "creator", "created"}, fields...)
+	w.Write(columns)
+	for i := range documents {
+		record := []string{strconv.FormatUint(documents[i].ID, 10)}
+		for _, column := range columns[1:] {
+			value, _ := graph.FieldValue(&documents[i], column)
+			if column == "created" {
+				value = documents[i].CreatedDate.Time.Format("2006-01-02T15:04:05")
+			}
+			record = append(record, graph.FormatValue(value))
+		}
+		w.Write(record)
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("cannot write csv: %v", err)
+	}
+	return nil
+}
+
+func init() {
+	graphCmd.AddCommand(graphQueryCmd)
+	graphQueryCmd.Flags().StringP("output", "", "table", "output format: table, json or csv")
+	graphQueryCmd.Flags().StringP("fields", "", "", "comma separated fields to add as columns, e.g. details.assignee")
+}
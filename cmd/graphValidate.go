@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var graphValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "check every document against the schema of its type",
+	Long: `check every document against the schema of its type
+
+Each document type with a schema (dho, settings, period, member, role, assignment, payout, badge,
+payment, vote.tally and vote) declares its content groups and labels, the FlexValue type of each
+item, and whether it is required. Documents missing a required item or holding an item of the
+wrong type are reported. With --strict, items the schema does not declare and documents of types
+without a schema are reported too.`,
+	Example: `  daoctl graph validate
+  daoctl graph validate --type role,assignment --strict`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		gc, err := getGraphCache(context.Background())
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		var documents []docgraph.Document
+		types := viper.GetStringSlice("graph-validate-cmd-type")
+		if len(types) == 0 {
+			documents, err = gc.GetAllDocuments()
+			if err != nil {
+				return err
+			}
+		}
+		for _, docType := range types {
+			if _, found := models.GetSchema(eos.Name(docType)); !found {
+				return fmt.Errorf("no schema for document type: %v, known types are %v", docType, models.SchemaTypes())
+			}
+			typed, err := gc.GetDocumentsByType(eos.Name(docType))
+			if err != nil {
+				return err
+			}
+			documents = append(documents, typed...)
+		}
+
+		strict := viper.GetBool("graph-validate-cmd-strict")
+		var violations []models.Violation
+		for i := range documents {
+			violations = append(violations, models.Validate(&documents[i], strict)...)
+		}
+
+		if len(violations) == 0 {
+			fmt.Printf("All %v documents conform to their schema\n", len(documents))
+			return nil
+		}
+
+		violationTable := views.ViolationTable(violations)
+		violationTable.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("\n" + violationTable.String() + "\n")
+		return fmt.Errorf("found %v schema violations in %v documents", len(violations), len(documents))
+	},
+}
+
+func init() {
+	graphCmd.AddCommand(graphValidateCmd)
+	graphValidateCmd.Flags().StringSliceP("type", "", []string{}, "only validate documents of these types")
+	graphValidateCmd.Flags().BoolP("strict", "", false, "also report items not in the schema and types without a schema")
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/spf13/viper"
+	"go.uber.org/zap"
+)
+
+// networkDefaults are the built-in network profiles. A Networks section in daoctl.yaml
+// can override any of them by name or add new ones.
+var networkDefaults = []byte(`
+Networks:
+  mainnet:
+    ChainID: 4667b205c6838ef70ff7988f6e8257e8be0e1284a2f59699054a018f743b1d11
+    Banner: "WARNING: Connecting to the Hypha Production Mainnet"
+    EosioEndpoint: https://telos.caleos.io
+    HyperionEndpoint: https://mainnet.telosusa.io/v2
+    DAOContract: dao.hypha
+    RootNode: 52a7ff82bd6f53b31285e97d6806d886eefb650e79754784e9d923d3df347c91
+    CalendarStart: 7706e72c29af438f309a99391fa8e8e3dcef0db438d0d24daf6fc4cf29697bff
+    MsigContract: msig.hypha
+    TelosDecideContract: trailservice
+    Treasury:
+      TokenContract: husd.hypha
+      Symbol: HUSD
+      Contract: bank.hypha
+    RewardToken:
+      Symbol: HYPHA
+      Contract: token.hypha
+    VoteTokenSymbol: HVOICE
+    Explorer:
+      Transaction: https://explorer.telos.net/transaction/%s
+      Block: https://explorer.telos.net/block/%s
+  testnet:
+    ChainID: 1eaa0824707c8c16bd25145493bf062aecddfeb56c736f6ba6397f3195f33c9f
+    Banner: "NETWORK: Connecting to the Hypha Test Network"
+    EosioEndpoint: https://testnet.telos.caleos.io
+    HyperionEndpoint: https://testnet.telosusa.io/v2
+    DAOContract: dao.hypha
+    RootNode: 52a7ff82bd6f53b31285e97d6806d886eefb650e79754784e9d923d3df347c91
+    CalendarStart: 09c4a000153ba36bf018089dc8411904c36a8fbd2c2c860f179c0c509e2f029a
+    MsigContract: msig.hypha
+    TelosDecideContract: trailservice
+    Treasury:
+      TokenContract: husd.hypha
+      Symbol: HUSD
+      Contract: bank.hypha
+    RewardToken:
+      Symbol: HYPHA
+      Contract: token.hypha
+    VoteTokenSymbol: HVOICE
+    Explorer:
+      Transaction: https://explorer-test.telos.net/transaction/%s
+      Block: https://explorer-test.telos.net/block/%s
+  local:
+    Banner: "NETWORK: Connecting to a local node"
+    EosioEndpoint: http://localhost:8888
+    HyperionEndpoint: http://localhost:7000/v2
+    DAOContract: dao.hypha
+    MsigContract: msig.hypha
+    TelosDecideContract: trailservice
+    Treasury:
+      TokenContract: husd.hypha
+      Symbol: HUSD
+      Contract: bank.hypha
+    RewardToken:
+      Symbol: HYPHA
+      Contract: token.hypha
+    VoteTokenSymbol: HVOICE
+`)
+
+var builtinNetworks *viper.Viper
+
+func getBuiltinNetworks() *viper.Viper {
+	if builtinNetworks == nil {
+		builtinNetworks = viper.New()
+		builtinNetworks.SetConfigType("yaml")
+		err := builtinNetworks.ReadConfig(bytes.NewBuffer(networkDefaults))
+		errorCheck("reading built-in network profiles", err)
+	}
+	return builtinNetworks
+}
+
+// getNetworkProfile returns the named profile, preferring daoctl.yaml over the built-in ones
+func getNetworkProfile(name string) *viper.Viper {
+	key := "Networks." + name
+	if viper.IsSet(key) {
+		return viper.Sub(key)
+	}
+	return getBuiltinNetworks().Sub(key)
+}
+
+func getNetworkNames() []string {
+	known := make(map[string]bool)
+	for name := range viper.GetStringMap("Networks") {
+		known[name] = true
+	}
+	for name := range getBuiltinNetworks().GetStringMap("Networks") {
+		known[name] = true
+	}
+
+	var names []string
+	for name := range known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyNetworkProfile copies the settings of the selected network profile into the top-level
+// configuration. A profile chosen with --network overrides the configuration file, while a
+// profile named by the Network key only fills in the settings the file leaves out.
+func applyNetworkProfile() error {
+	name := viper.GetString("global-network")
+	override := name != ""
+	if !override {
+		name = viper.GetString("Network")
+	}
+	if name == "" {
+		return nil
+	}
+
+	profile := getNetworkProfile(name)
+	if profile == nil {
+		return fmt.Errorf("unknown network profile: %v, available profiles: %v", name, strings.Join(getNetworkNames(), ", "))
+	}
+
+	for _, key := range profile.AllKeys() {
+		if override || !viper.IsSet(key) {
+			viper.Set(key, profile.Get(key))
+		}
+	}
+	viper.Set("Network", name)
+	zlog.Debug("applied network profile", zap.String("network", name), zap.Bool("override", override))
+	return nil
+}
+
+var chainChecked bool
+
+// checkChain verifies, once per run, that the node behind the API serves the chain expected by
+// the selected network profile, and prints the profile's banner. Commands that never talk to the
+// node never pay for this round trip. The banner and any mismatch go to stderr so that they never
+// mix with a command's output.
+func checkChain(ctx context.Context, api *eos.API) {
+	if chainChecked {
+		return
+	}
+	chainChecked = true
+
+	info, err := api.GetInfo(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR: unable to get blockchain node info from %v: %v. Please check the EosioEndpoint configuration.\n", api.BaseURL, err)
+		os.Exit(1)
+	}
+
+	chainID := hex.EncodeToString(info.ChainID)
+	expected := viper.GetString("ChainID")
+	if expected != "" && !strings.EqualFold(expected, chainID) {
+		fmt.Fprintf(os.Stderr, "ERROR: node at %v is on chain %v, but network profile %v expects chain %v\n",
+			api.BaseURL, chainID, viper.GetString("Network"), expected)
+		os.Exit(1)
+	}
+
+	if banner := viper.GetString("Banner"); banner != "" {
+		colorRed := "\033[31m"
+		colorCyan := "\033[36m"
+		colorReset := "\033[0m"
+		color := colorCyan
+		if viper.GetString("Network") == "mainnet" {
+			color = colorRed
+		}
+		fmt.Fprintln(os.Stderr, color+"\n"+banner+colorReset)
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// explorerURL returns the explorer URL template of the given kind (Transaction or Block) for
+// the network profile serving chainID, or an empty string when no profile has one
+func explorerURL(chainID eos.SHA256Bytes, kind string) string {
+	hexChain := hex.EncodeToString(chainID)
+	if strings.EqualFold(viper.GetString("ChainID"), hexChain) {
+		if url := viper.GetString("Explorer." + kind); url != "" {
+			return url
+		}
+	}
+
+	for _, name := range getNetworkNames() {
+		profile := getNetworkProfile(name)
+		if profile != nil && strings.EqualFold(profile.GetString("ChainID"), hexChain) {
+			return profile.GetString("Explorer." + kind)
+		}
+	}
+	return ""
+}
@@ -1,15 +1,64 @@
 package cmd
 
 import (
+	"context"
+	"fmt"
+
 	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
 )
 
 var proposeCmd = &cobra.Command{
-	Use:   "propose",
+	Use:   "propose [type] --from <template.yaml> | --interactive",
 	Short: "manage proposals",
+	Long: `manage proposals
+
+Any proposal type with a schema can be proposed from a YAML file of plain fields, which is
+converted to content groups and validated against the schema before signing. Print a starter
+file with 'daoctl propose template <type>'.
+
+With --interactive the proposal is built field by field instead: the role and start period of an
+assignment are picked from the graph cache and the calendar, and the proposal is shown with its
+estimated compensation before it is pushed.`,
+	Example: `  daoctl propose template payout > payout.yaml
+  daoctl propose payout --from payout.yaml
+  daoctl propose --interactive
+  daoctl propose assignment --interactive`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		if viper.GetBool("propose-cmd-interactive") {
+			docType := ""
+			if len(args) > 0 {
+				docType = args[0]
+			}
+			return runProposalWizard(ctx, docType)
+		}
+
+		if len(args) == 0 {
+			return cmd.Help()
+		}
+
+		fileName := viper.GetString("propose-cmd-from")
+		if fileName == "" {
+			return fmt.Errorf("specify the proposal with --from <file>, see 'daoctl propose template %v'", args[0])
+		}
+
+		contentGroups, err := templateContentGroups(ctx, args[0], fileName, nil)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("Proposing %v\n", args[0])
+		printContentGroupList(contentGroups, "  ")
+		pushEOSCActions(ctx, getAPI(), newProposeAction(args[0], contentGroups))
+		return nil
+	},
 }
 
 func init() {
 	RootCmd.AddCommand(proposeCmd)
 	proposeCmd.PersistentFlags().StringP("file", "f", "", "filename of document's JSON file")
+	proposeCmd.PersistentFlags().StringP("from", "", "", "filename of a YAML proposal, see 'propose template'")
+	proposeCmd.PersistentFlags().BoolP("interactive", "i", false, "prompt for each field of the proposal")
 }
@@ -2,136 +2,192 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
+	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
 	"github.com/hypha-dao/document-graph/docgraph"
 
-	"io/ioutil"
-
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
-func getDefaultPeriod() docgraph.Document {
+var proposeAssignmentCmd = &cobra.Command{
+	Use:   "assignment -f [filename] | --from [template.yaml] --role <role>",
+	Short: "propose an assignment",
+	Long: `propose an assignment
 
-	ctx := context.Background()
+The flags set the assignee, role, start period, number of periods, time share and deferred pay,
+replacing the values in the file. The role is required, from --role or the file. The start period is looked up in the calendar: 'next' (the
+default), 'current' or 'current+N', a date within the period such as 2021-03-01, or a hash, hash
+prefix, document ID or node label of the period. Periods that have ended and periods beyond the
+end of the calendar are rejected.`,
+	Example: `  daoctl propose assignment --from assignment.yaml --assignee alice --start-period current+2 --periods 12
+  daoctl propose assignment -f assignment.json --role Developer --time-share 50 --deferred 40`,
 
-	root, err := docgraph.LoadDocument(ctx, getAPI(), eos.AN(viper.GetString("DAOContract")), "0f374e7a9d8ab17f172f8c478744cdd4016497e15229616f2ffd04d8002ef64a")
-	if err != nil {
-		panic(err)
-	}
+	Run: func(cmd *cobra.Command, args []string) {
 
-	edges, err := docgraph.GetEdgesFromDocumentWithEdge(ctx, getAPI(), eos.AN(viper.GetString("DAOContract")), root, eos.Name("start"))
-	if err != nil || len(edges) <= 0 {
-		panic("There are no next edges")
-	}
+		ctx := context.Background()
+		if viper.GetBool("propose-cmd-interactive") {
+			errorCheck("proposing assignment", runProposalWizard(ctx, "assignment"))
+			return
+		}
+		contract := toAccount(viper.GetString("DAOContract"), "contract")
 
-	lastDocument, err := docgraph.LoadDocument(ctx, getAPI(), eos.AN(viper.GetString("DAOContract")), edges[0].ToNode.String())
-	if err != nil {
-		panic("Next document not found: " + edges[0].ToNode.String())
-	}
+		overrides, err := assignmentOverrides(ctx, contract)
+		errorCheck("reading assignment flags", err)
 
-	index := 1
-	for index < 6 {
+		contentGroups, err := proposalFileContent(ctx, "assignment", overrides)
+		errorCheck("reading assignment proposal", err)
 
-		edges, err := docgraph.GetEdgesFromDocumentWithEdge(ctx, getAPI(), eos.AN(viper.GetString("DAOContract")), lastDocument, eos.Name("next"))
-		if err != nil || len(edges) <= 0 {
-			panic("There are no next edges")
+		// the role must be chosen, but the assignee defaults to DAOUser when neither the proposal nor
+		// the flags set them
+		if _, err := contentGroups[0].GetContent("role"); err != nil {
+			exitWitMessage("no role for the assignment: set --role or role in the proposal file")
 		}
+		if _, err := contentGroups[0].GetContent("assignee"); err != nil {
+			assignee, err := models.ParseValue(models.NameType, viper.GetString("DAOUser"))
+			errorCheck("setting the assignee", err)
+			contentGroups[0] = setContent(contentGroups[0], "assignee", assignee)
+		}
+
+		reference := viper.GetString("propose-assignment-cmd-start-period")
+		if reference == "" {
+			reference = "next"
+			if startPeriod, err := contentGroups[0].GetContent("start_period"); err == nil {
+				reference = startPeriod.String()
+			}
+		}
+		calendar, err := getCalendar(ctx, getReader(), contract)
+		errorCheck("loading the calendar", err)
+		period, err := resolveStartPeriod(ctx, calendar, reference, time.Now().UTC())
+		errorCheck("finding the start period", err)
+		contentGroups[0] = setContent(contentGroups[0], "start_period", checksumValue(period.Document.Hash))
+
+		fmt.Printf("Proposing assignment starting %v (%v)\n", period.Label, period.StartTime.Format("2006 Jan 02"))
+		pushEOSCActions(ctx, getAPI(), newProposeAction("assignment", contentGroups))
+	},
+}
 
-		lastDocument, err = docgraph.LoadDocument(ctx, getAPI(), eos.AN(viper.GetString("DAOContract")), edges[0].ToNode.String())
+// assignmentOverrides returns the text of the details items set by the flags, with the role
+// resolved to its hash; the start period is resolved against the calendar separately
+func assignmentOverrides(ctx context.Context, contract eos.AccountName) (map[string]string, error) {
+	overrides := make(map[string]string)
+	if assignee := viper.GetString("propose-assignment-cmd-assignee"); assignee != "" {
+		overrides["assignee"] = assignee
+	}
+	if reference := viper.GetString("propose-assignment-cmd-role"); reference != "" {
+		role, err := util.ResolveDocument(ctx, getReader(), contract, reference)
 		if err != nil {
-			panic("Next document not found: " + edges[0].ToNode.String())
+			return nil, err
 		}
+		overrides["role"] = role.Hash.String()
+	}
 
-		index++
+	if viper.IsSet("propose-assignment-cmd-periods") {
+		periods := viper.GetInt("propose-assignment-cmd-periods")
+		if periods < 1 {
+			return nil, fmt.Errorf("invalid number of periods: %v, expected at least 1", periods)
+		}
+		overrides["period_count"] = strconv.Itoa(periods)
+	}
+	if viper.IsSet("propose-assignment-cmd-time-share") {
+		timeShare := viper.GetInt("propose-assignment-cmd-time-share")
+		if timeShare < 1 || timeShare > 100 {
+			return nil, fmt.Errorf("invalid time share: %v, expected a percentage from 1 to 100", timeShare)
+		}
+		overrides["time_share_x100"] = strconv.Itoa(timeShare)
+	}
+	if viper.IsSet("propose-assignment-cmd-deferred") {
+		deferred := viper.GetInt("propose-assignment-cmd-deferred")
+		if deferred < 0 || deferred > 100 {
+			return nil, fmt.Errorf("invalid deferred pay: %v, expected a percentage from 0 to 100", deferred)
+		}
+		overrides["deferred_pay_x100"] = strconv.Itoa(deferred)
 	}
-	return lastDocument
+	return overrides, nil
 }
 
-var proposeAssignmentCmd = &cobra.Command{
-	Use:   "assignment -f [filename] --role <hash>",
-	Short: "propose an assignment",
-
-	Run: func(cmd *cobra.Command, args []string) {
-
-		ctx := context.Background()
-		contract := toAccount(viper.GetString("DAOContract"), "contract")
-
-		var role docgraph.Document
-		var err error
-		if len(viper.GetString("propose-assignment-cmd-role")) > 0 {
-			role, err = docgraph.LoadDocument(ctx, getAPI(), contract, viper.GetString("propose-assignment-cmd-role"))
-		} else {
-			role, err = docgraph.GetLastDocumentOfEdge(ctx, getAPI(), contract, eos.Name("role"))
+// resolveStartPeriod finds the period of the calendar the reference names: "next", "current" or
+// "current+N", a date within the period, or a document reference to the period itself. Periods
+// that have ended are rejected, as is the last period, which only marks the end of the calendar.
+func resolveStartPeriod(ctx context.Context, calendar models.Period, reference string, now time.Time) (models.Period, error) {
+	periods := calendar.Periods()
+	calendarEnd := periods[len(periods)-1].StartTime.Format("2006 Jan 02")
+
+	index := -1
+	switch {
+	case reference == "next" || reference == "current" || strings.HasPrefix(reference, "current+"):
+		current, found := models.PeriodAt(periods, now)
+		if !found {
+			return models.Period{}, fmt.Errorf("the calendar from %v to %v does not contain the current time",
+				periods[0].StartTime.Format("2006 Jan 02"), calendarEnd)
 		}
-		if err != nil {
-			panic(err)
+		offset := 0
+		if reference == "next" {
+			offset = 1
+		} else if reference != "current" {
+			n, err := strconv.Atoi(strings.TrimPrefix(reference, "current+"))
+			if err != nil || n < 0 {
+				return models.Period{}, fmt.Errorf("invalid start period: %v, expected current+N", reference)
+			}
+			offset = n
 		}
-
-		data, err := ioutil.ReadFile(viper.GetString("propose-cmd-file"))
-		if err != nil {
-			fmt.Println("Unable to read file: ", viper.GetString("propose-cmd-file"))
-			return
+		index = current + offset
+
+	default:
+		if date, err := time.Parse("2006-01-02", reference); err == nil {
+			i, found := models.PeriodAt(periods, date)
+			if !found {
+				return models.Period{}, fmt.Errorf("%v is outside the calendar, which runs from %v to %v",
+					reference, periods[0].StartTime.Format("2006 Jan 02"), calendarEnd)
+			}
+			index = i
+			break
 		}
 
-		var proposalDoc docgraph.Document
-		err = json.Unmarshal([]byte(data), &proposalDoc)
+		document, err := util.ResolveDocument(ctx, getReader(), eos.AN(viper.GetString("DAOContract")), reference)
 		if err != nil {
-			panic(err)
+			return models.Period{}, err
+		}
+		for i := range periods {
+			if periods[i].Document.Hash.String() == document.Hash.String() {
+				index = i
+			}
 		}
+		if index < 0 {
+			return models.Period{}, fmt.Errorf("document is not a period of the calendar: %v", document.Hash.String())
+		}
+	}
 
-		// inject the role hash in the first content group of the document
-		proposalDoc.ContentGroups[0] = append(proposalDoc.ContentGroups[0], docgraph.ContentItem{
-			Label: "role",
-			Value: &docgraph.FlexValue{
-				BaseVariant: eos.BaseVariant{
-					TypeID: docgraph.GetVariants().TypeID("checksum256"),
-					Impl:   role.Hash,
-				}},
-		})
-
-		// inject the period hash in the first content group of the document
-		proposalDoc.ContentGroups[0] = append(proposalDoc.ContentGroups[0], docgraph.ContentItem{
-			Label: "start_period",
-			Value: &docgraph.FlexValue{
-				BaseVariant: eos.BaseVariant{
-					TypeID: docgraph.GetVariants().TypeID("checksum256"),
-					Impl:   getDefaultPeriod().Hash,
-				}},
-		})
-
-		// inject the assignee in the first content group of the document
-		proposalDoc.ContentGroups[0] = append(proposalDoc.ContentGroups[0], docgraph.ContentItem{
-			Label: "assignee",
-			Value: &docgraph.FlexValue{
-				BaseVariant: eos.BaseVariant{
-					TypeID: docgraph.GetVariants().TypeID("name"),
-					Impl:   eos.Name(viper.GetString("DAOUser")),
-				}},
-		})
-
-		action := eos.ActN("propose")
-		actions := eos.Action{
-			Account: contract,
-			Name:    action,
-			Authorization: []eos.PermissionLevel{
-				{Actor: eos.AN(viper.GetString("DAOUser")), Permission: eos.PN("active")},
-			},
-			ActionData: eos.NewActionData(proposal{
-				Proposer:      eos.AN(viper.GetString("DAOUser")),
-				ProposalType:  eos.Name("assignment"),
-				ContentGroups: proposalDoc.ContentGroups,
-			})}
-
-		pushEOSCActions(context.Background(), getAPI(), &actions)
-	},
+	if index >= len(periods)-1 {
+		return models.Period{}, fmt.Errorf("start period is beyond the end of the calendar on %v, add periods first", calendarEnd)
+	}
+	period := periods[index]
+	if !period.Next.StartTime.After(now) {
+		return models.Period{}, fmt.Errorf("start period %v ended on %v", period.Label, period.Next.StartTime.Format("2006 Jan 02"))
+	}
+	return period, nil
+}
+
+func checksumValue(checksum eos.Checksum256) *docgraph.FlexValue {
+	return &docgraph.FlexValue{
+		BaseVariant: eos.BaseVariant{
+			TypeID: docgraph.GetVariants().TypeID(string(models.Checksum256Type)),
+			Impl:   checksum,
+		}}
 }
 
 func init() {
 	proposeCmd.AddCommand(proposeAssignmentCmd)
-	proposeAssignmentCmd.PersistentFlags().StringP("role", "", "", "hash of role to apply to")
-
+	proposeAssignmentCmd.Flags().StringP("role", "", "", "role to apply to, as a hash, unique hash prefix, document ID or node label")
+	proposeAssignmentCmd.Flags().StringP("start-period", "", "", "first period: next, current, current+N, a date, or a period reference (default next)")
+	proposeAssignmentCmd.Flags().IntP("periods", "", 0, "number of periods")
+	proposeAssignmentCmd.Flags().StringP("assignee", "", "", "account to assign (default DAOUser)")
+	proposeAssignmentCmd.Flags().IntP("time-share", "", 0, "time commitment as a percentage of full time")
+	proposeAssignmentCmd.Flags().IntP("deferred", "", 0, "percentage of pay deferred")
 }
@@ -20,7 +20,7 @@ var proposeDeploymentApproveCmd = &cobra.Command{
 	Short: "approve an existing multisig deployment proposal",
 	Long:  "approve an existing multisig deployment proposal",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		api := getAPI()
 		ctx := context.Background()
 
 		proposalName, err := grabInput("propose-deployment-approve-cmd-proposal-name", proposalNamePromptLabel)
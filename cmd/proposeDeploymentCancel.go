@@ -26,7 +26,7 @@ var proposeDeploymentCancelCmd = &cobra.Command{
 	Short: "cancel an existing multisig deployment proposal",
 	Long:  "cancel an existing multisig deployment proposal",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		api := getAPI()
 		ctx := context.Background()
 
 		proposalName, err := grabInput("propose-deployment-cancel-cmd-proposal-name", proposalNamePromptLabel)
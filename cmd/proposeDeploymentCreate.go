@@ -13,6 +13,7 @@ import (
 	"github.com/eoscanada/eos-go/system"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/hypha-dao/daoctl/util"
 	"github.com/hypha-dao/document-graph/docgraph"
 	"github.com/manifoldco/promptui"
 	"github.com/spf13/cobra"
@@ -23,7 +24,7 @@ const proposalNamePromptLabel = "Required: name of the proposal (eosio::name for
 const commitPromptLabel = "Required: commit hash of the github repo to use for deployment"
 const notesPromptLabel = "Optional: notes to attach to the deployment proposal document"
 const developerPromptLabel = "Optional: account name of the developer who contributed most to the upgrade"
-const existingDocumentLabel = "Optional: hash of another document within the DAO that describes the deployment, e.g. an approved policy doc"
+const existingDocumentLabel = "Optional: hash, hash prefix, ID or label of another document within the DAO that describes the deployment, e.g. an approved policy doc"
 const accountPromptLabel = "Required: name of account to deploy contract to"
 
 func grabInput(field, promptLabel string) (string, error) {
@@ -45,7 +46,7 @@ var proposeDeploymentCreateCmd = &cobra.Command{
 	Short: "proposes a contract deployment based on a git commit",
 	Long:  "proposes a contract deployment based on a git commit",
 	RunE: func(cmd *cobra.Command, args []string) error {
-		api := eos.New(viper.GetString("EosioEndpoint"))
+		api := getAPI()
 		ctx := context.Background()
 
 		proposalName, err := grabInput("propose-deployment-create-cmd-proposal-name", proposalNamePromptLabel)
@@ -73,6 +74,13 @@ var proposeDeploymentCreateCmd = &cobra.Command{
 		if err != nil {
 			return fmt.Errorf("cannot get input: %v %v", existingDocumentLabel, err)
 		}
+		if document != "" {
+			existingDocument, err := util.ResolveDocument(ctx, getReader(), eos.AN(viper.GetString("DAOContract")), document)
+			if err != nil {
+				return fmt.Errorf("cannot resolve document: %v", err)
+			}
+			document = existingDocument.Hash.String()
+		}
 
 		notes, err := grabInput("propose-deployment-create-cmd-notes", notesPromptLabel)
 		if err != nil {
@@ -205,7 +213,7 @@ var proposeDeploymentCreateCmd = &cobra.Command{
 				Label: "document",
 				Value: &docgraph.FlexValue{
 					BaseVariant: eos.BaseVariant{
-						TypeID: docgraph.GetVariants().TypeID("string"), // TODO: check if valid hash?
+						TypeID: docgraph.GetVariants().TypeID("string"),
 						Impl:   document,
 					},
 				},
@@ -0,0 +1,337 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/manifoldco/promptui"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/viper"
+)
+
+// runProposalWizard prompts for each field of a proposal of the type, asking for the type first
+// when it is empty, then shows the proposal and its estimated compensation and pushes it once
+// confirmed
+func runProposalWizard(ctx context.Context, docType string) error {
+	if docType == "" {
+		var types []string
+		for _, proposalType := range models.ProposalTypes() {
+			types = append(types, string(proposalType))
+		}
+		prompt := promptui.Select{Label: "Select the type of proposal", Items: types}
+		_, result, err := prompt.Run()
+		if err != nil {
+			return fmt.Errorf("cannot capture input: proposal type %v", err)
+		}
+		docType = result
+	}
+
+	schema, err := getProposalSchema(docType)
+	if err != nil {
+		return err
+	}
+
+	gc, err := getGraphCache(ctx)
+	if err != nil {
+		return err
+	}
+	defer gc.Close()
+
+	var role *models.Role
+	values := make(map[string]map[string]string)
+	for _, field := range schema.Fields {
+		if field.Group == "system" {
+			continue
+		}
+
+		var text string
+		switch {
+		case field.Label == "role" && field.Type == models.Checksum256Type:
+			role, err = selectRole(gc)
+			if err == nil {
+				text = role.Hash.String()
+			}
+		case field.Label == "start_period" && field.Type == models.Checksum256Type:
+			text, err = selectStartPeriod(ctx)
+		case field.Type == models.Checksum256Type:
+			text, err = promptField(field, "")
+			if err == nil && text != "" {
+				document, resolveErr := gc.ResolveDocument(text)
+				if resolveErr != nil {
+					return fmt.Errorf("cannot resolve %v: %v", field.Name(), resolveErr)
+				}
+				text = document.Hash.String()
+			}
+		case strings.HasSuffix(field.Label, "_salary_per_phase") && role != nil:
+			text, err = promptField(field, suggestedSalary(gc, role, values["details"], field.Label))
+		default:
+			text, err = promptField(field, fieldDefault(field))
+		}
+		if err != nil {
+			return err
+		}
+
+		if text != "" {
+			if values[field.Group] == nil {
+				values[field.Group] = make(map[string]string)
+			}
+			values[field.Group][field.Label] = text
+		}
+	}
+
+	contentGroups, err := models.ProposalContent(schema, values)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\n%v proposal\n", docType)
+	printContentGroupList(contentGroups, "  ")
+	if estimate := compensationEstimate(schema.Type, values, role); len(estimate) > 0 {
+		fmt.Println("\nEstimated compensation")
+		fmt.Println(columnize.SimpleFormat(estimate))
+	}
+
+	fmt.Println()
+	confirm := promptui.Prompt{Label: "Push this proposal", IsConfirm: true}
+	if _, err := confirm.Run(); err != nil {
+		fmt.Println("Proposal not pushed")
+		return nil
+	}
+
+	pushEOSCActions(ctx, getAPI(), newProposeAction(docType, contentGroups))
+	return nil
+}
+
+// fieldDefault is the value offered for a field: the schema default, or DAOUser for the accounts
+// of a proposal
+func fieldDefault(field models.Field) string {
+	if field.Default != "" {
+		return field.Default
+	}
+	switch field.Label {
+	case "owner", "assignee", "recipient":
+		return viper.GetString("DAOUser")
+	}
+	return ""
+}
+
+// suggestedSalary returns the salary per period of the label that the compensation calculator
+// gives for the role and the time share and deferred pay entered so far, or nothing when it
+// cannot be calculated
+func suggestedSalary(gc *util.GraphCache, role *models.Role, details map[string]string, label string) string {
+	timeShare, err := strconv.ParseInt(details["time_share_x100"], 10, 64)
+	if err != nil {
+		return ""
+	}
+	deferred, err := strconv.ParseInt(details["deferred_pay_x100"], 10, 64)
+	if err != nil {
+		return ""
+	}
+	settings, err := cachedSettings(gc)
+	if err != nil {
+		return ""
+	}
+	compensation, err := models.NewCompensation(*role, settings, float64(timeShare)/100, float64(deferred)/100, 1)
+	if err != nil {
+		return ""
+	}
+
+	switch label {
+	case "husd_salary_per_phase":
+		return compensation.HusdPerPeriod.String()
+	case "hypha_salary_per_phase":
+		return compensation.HyphaPerPeriod.String()
+	case "hvoice_salary_per_phase":
+		return compensation.HvoicePerPeriod.String()
+	}
+	return ""
+}
+
+// promptField prompts for the text of a field, rejecting values that do not parse as the field's
+// type; an optional field may be left empty
+func promptField(field models.Field, defaultValue string) (string, error) {
+	qualifier := "Optional"
+	if field.Required {
+		qualifier = "Required"
+	}
+	label := fmt.Sprintf("%v: %v (%v)", qualifier, fieldDescription(field), field.Label)
+	if field.Example != "" {
+		label = fmt.Sprintf("%v, e.g. %v", label, field.Example)
+	}
+
+	fmt.Println()
+	prompt := promptui.Prompt{
+		Label:   label,
+		Default: defaultValue,
+		Validate: func(input string) error {
+			input = strings.TrimSpace(input)
+			if input == "" {
+				if field.Required {
+					return fmt.Errorf("%v is required", field.Label)
+				}
+				return nil
+			}
+			if field.Type == models.Checksum256Type {
+				return nil
+			}
+			_, err := models.ParseValue(field.Type, input)
+			return err
+		},
+	}
+	result, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("cannot capture input: %v %v", field.Name(), err)
+	}
+	return strings.TrimSpace(result), nil
+}
+
+// selectRole lists the roles in the graph cache by title
+func selectRole(gc *util.GraphCache) (*models.Role, error) {
+	documents, err := gc.GetDocumentsByType(eos.Name("role"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get roles: %v", err)
+	}
+
+	var roles []models.Role
+	for _, document := range documents {
+		role, err := models.NewRole(document)
+		if err != nil {
+			continue
+		}
+		roles = append(roles, role)
+	}
+	if len(roles) == 0 {
+		return nil, fmt.Errorf("no roles found, run 'daoctl cache sync' to fetch them")
+	}
+	sort.Slice(roles, func(i, j int) bool { return roles[i].Title < roles[j].Title })
+
+	items := make([]string, len(roles))
+	for i, role := range roles {
+		items[i] = fmt.Sprintf("%-40v %14v  %v", role.Title, util.FormatAsset(&role.AnnualUSDSalary, 2), role.Hash.String()[:8])
+	}
+
+	fmt.Println()
+	prompt := promptui.Select{
+		Label: "Select the role",
+		Items: items,
+		Size:  10,
+		Searcher: func(input string, index int) bool {
+			return strings.Contains(strings.ToLower(items[index]), strings.ToLower(input))
+		},
+	}
+	i, _, err := prompt.Run()
+	if err != nil {
+		return nil, fmt.Errorf("cannot capture input: role %v", err)
+	}
+	return &roles[i], nil
+}
+
+// selectStartPeriod lists the current and upcoming periods of the calendar and returns the hash
+// of the one selected
+func selectStartPeriod(ctx context.Context) (string, error) {
+	calendar, err := getCalendar(ctx, getReader(), eos.AN(viper.GetString("DAOContract")))
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now().UTC()
+	var periods []models.Period
+	var items []string
+	for _, period := range calendar.Periods() {
+		if period.Next == nil || !period.Next.StartTime.After(now) {
+			continue
+		}
+		current := ""
+		if !period.StartTime.After(now) {
+			current = "(current)"
+		}
+		periods = append(periods, period)
+		items = append(items, fmt.Sprintf("%-12v %v  %v %v", period.Label, period.StartTime.Format("2006 Jan 02"), period.Document.Hash.String()[:8], current))
+	}
+	if len(periods) == 0 {
+		return "", fmt.Errorf("no current or upcoming periods in the calendar")
+	}
+
+	fmt.Println()
+	prompt := promptui.Select{Label: "Select the start period", Items: items, Size: 10}
+	i, _, err := prompt.Run()
+	if err != nil {
+		return "", fmt.Errorf("cannot capture input: start period %v", err)
+	}
+	return periods[i].Document.Hash.String(), nil
+}
+
+// compensationEstimate returns columnize rows with what a proposal pays: an assignment's salary
+// per period and over all its periods, a payout's amounts, a role's cost at full capacity and a
+// badge's pay multipliers
+func compensationEstimate(docType eos.Name, values map[string]map[string]string, role *models.Role) []string {
+	details := values["details"]
+	asset := func(label string) (eos.Asset, bool) {
+		a, err := eos.NewAssetFromString(details[label])
+		return a, err == nil
+	}
+	number := func(label string) (float64, bool) {
+		i, err := strconv.ParseInt(details[label], 10, 64)
+		return float64(i), err == nil
+	}
+
+	var rows []string
+	switch docType {
+	case "assignment":
+		periods, _ := number("period_count")
+		for _, label := range []string{"husd_salary_per_phase", "hypha_salary_per_phase", "hvoice_salary_per_phase"} {
+			perPeriod, ok := asset(label)
+			if !ok {
+				continue
+			}
+			total := util.AssetMult(perPeriod, big.NewFloat(periods))
+			rows = append(rows, fmt.Sprintf("%v per period|%v|over %v periods|%v", perPeriod.Symbol.Symbol,
+				util.FormatAsset(&perPeriod, 2), periods, util.FormatAsset(&total, 2)))
+		}
+		if timeShare, ok := number("time_share_x100"); ok && role != nil {
+			share := util.AssetMult(role.AnnualUSDSalary, big.NewFloat(timeShare/100))
+			rows = append(rows, fmt.Sprintf("Role annual USD salary|%v|at %v%% time share|%v",
+				util.FormatAsset(&role.AnnualUSDSalary, 2), timeShare, util.FormatAsset(&share, 2)))
+		}
+		if deferred, ok := number("deferred_pay_x100"); ok {
+			rows = append(rows, fmt.Sprintf("Deferred pay|%v%%", deferred))
+		}
+	case "payout":
+		for _, label := range []string{"husd_amount", "hypha_amount", "hvoice_amount", "seeds_instant_amount", "seeds_escrow_amount"} {
+			if amount, ok := asset(label); ok && amount.Amount != 0 {
+				rows = append(rows, fmt.Sprintf("%v|%v", label, util.FormatAsset(&amount, 2)))
+			}
+		}
+		if deferred, ok := number("deferred_perc_x100"); ok {
+			rows = append(rows, fmt.Sprintf("Deferred pay|%v%%", deferred))
+		}
+	case "role":
+		salary, ok := asset("annual_usd_salary")
+		if !ok {
+			break
+		}
+		capacity, ok := number("fulltime_capacity_x100")
+		if !ok {
+			capacity = 100
+		}
+		fteCapCost := util.AssetMult(salary, big.NewFloat(capacity/100))
+		rows = append(rows, fmt.Sprintf("Annual USD salary|%v", util.FormatAsset(&salary, 2)))
+		rows = append(rows, fmt.Sprintf("FTE cap cost|%v", util.FormatAsset(&fteCapCost, 2)))
+	case "badge":
+		for _, label := range []string{"husd_coefficient_x10000", "hypha_coefficient_x10000", "hvoice_coefficient_x10000", "seeds_coefficient_x10000"} {
+			if coefficient, ok := number(label); ok {
+				rows = append(rows, fmt.Sprintf("%v pay multiplier|%v", strings.ToUpper(strings.Split(label, "_")[0]),
+					strconv.FormatFloat(coefficient/10000, 'f', -1, 64)))
+			}
+		}
+	}
+	return rows
+}
@@ -0,0 +1,255 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"strconv"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"gopkg.in/yaml.v2"
+)
+
+var proposeTemplateCmd = &cobra.Command{
+	Use:   "template <type>",
+	Short: "print a commented starter YAML file for a proposal",
+	Long: `print a commented starter YAML file for a proposal
+
+Required fields are filled with an example or left empty; optional fields are commented out with
+their default. Fill in the values and submit the proposal with 'daoctl propose <type> --from <file>'.`,
+	Example: `  daoctl propose template role > role.yaml
+  daoctl propose role --from role.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		schema, err := getProposalSchema(args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Print(proposalTemplate(schema))
+		return nil
+	},
+}
+
+func getProposalSchema(docType string) (*models.Schema, error) {
+	schema, found := models.GetSchema(eos.Name(docType))
+	if !found || !schema.Proposable() {
+		return nil, fmt.Errorf("unknown proposal type: %v, expected one of %v", docType, models.ProposalTypes())
+	}
+	return schema, nil
+}
+
+// proposalTemplate renders the fields of the schema outside the system group as YAML, fields of
+// the details group at the top level and other groups as nested maps
+func proposalTemplate(schema *models.Schema) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %v proposal: %v\n", schema.Type, schema.Description)
+	fmt.Fprintf(&b, "# submit with: daoctl propose %v --from <this file>\n", schema.Type)
+
+	for _, group := range schema.Groups() {
+		if group == "system" {
+			continue
+		}
+		indent := ""
+		if group != "details" {
+			fmt.Fprintf(&b, "\n%v:\n", group)
+			indent = "  "
+		}
+
+		for _, field := range schema.Fields {
+			if field.Group != group {
+				continue
+			}
+			qualifier := "optional"
+			if field.Required {
+				qualifier = "required"
+			} else if field.Default != "" {
+				qualifier = "default " + field.Default
+			}
+			fmt.Fprintf(&b, "\n%v# %v (%v, %v)\n", indent, fieldDescription(field), field.Type, qualifier)
+
+			value := field.Example
+			if value == "" {
+				value = field.Default
+			}
+			line := fmt.Sprintf("%v: %v", field.Label, yamlValue(field.Type, value))
+			if !field.Required {
+				line = "# " + line
+			}
+			fmt.Fprintf(&b, "%v%v\n", indent, line)
+		}
+	}
+	return b.String()
+}
+
+// fieldDescription returns the description of the field, saying how a document reference may be
+// given; references are resolved to the document's hash before the proposal is submitted
+func fieldDescription(field models.Field) string {
+	if field.Type == models.Checksum256Type {
+		return field.Description + ", or its hash prefix, document ID or node label"
+	}
+	return field.Description
+}
+
+func yamlValue(fieldType models.FieldType, value string) string {
+	if fieldType == models.Int64Type && value != "" {
+		return value
+	}
+	return strconv.Quote(value)
+}
+
+// readProposalTemplate reads a proposal YAML file into the text of its values by group and label;
+// top level values belong to the details group and nested maps to the group they are named after
+func readProposalTemplate(fileName string) (map[string]map[string]string, error) {
+	data, err := ioutil.ReadFile(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file: %v %v", fileName, err)
+	}
+
+	var raw yaml.MapSlice
+	err = yaml.Unmarshal(data, &raw)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse yaml: %v %v", fileName, err)
+	}
+
+	values := make(map[string]map[string]string)
+	add := func(group string, label, value interface{}) {
+		if value == nil {
+			return
+		}
+		if values[group] == nil {
+			values[group] = make(map[string]string)
+		}
+		values[group][fmt.Sprint(label)] = yamlText(value)
+	}
+	for _, item := range raw {
+		if nested, ok := item.Value.(yaml.MapSlice); ok {
+			for _, nestedItem := range nested {
+				add(fmt.Sprint(item.Key), nestedItem.Key, nestedItem.Value)
+			}
+			continue
+		}
+		add("details", item.Key, item.Value)
+	}
+	return values, nil
+}
+
+func yamlText(value interface{}) string {
+	if f, ok := value.(float64); ok {
+		return strconv.FormatFloat(f, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// templateContentGroups reads the proposal of the type from a YAML file and converts it to content
+// groups, resolving document references in checksum256 fields to hashes. Overrides replace the
+// values of the details group with the same labels.
+func templateContentGroups(ctx context.Context, docType, fileName string, overrides map[string]string) ([]docgraph.ContentGroup, error) {
+	schema, err := getProposalSchema(docType)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := readProposalTemplate(fileName)
+	if err != nil {
+		return nil, err
+	}
+	if len(overrides) > 0 && values["details"] == nil {
+		values["details"] = make(map[string]string)
+	}
+	for label, text := range overrides {
+		values["details"][label] = text
+	}
+
+	for group, items := range values {
+		for label, text := range items {
+			field, found := schema.Field(group, label)
+			if !found || field.Type != models.Checksum256Type || len(text) == 64 {
+				continue
+			}
+			document, err := util.ResolveDocument(ctx, getReader(), eos.AN(viper.GetString("DAOContract")), text)
+			if err != nil {
+				return nil, fmt.Errorf("cannot resolve %v: %v", field.Name(), err)
+			}
+			items[label] = document.Hash.String()
+		}
+	}
+
+	return models.ProposalContent(schema, values)
+}
+
+// newProposeAction returns the propose action for the content groups, proposed by DAOUser
+func newProposeAction(docType string, contentGroups []docgraph.ContentGroup) *eos.Action {
+	return &eos.Action{
+		Account: toAccount(viper.GetString("DAOContract"), "contract"),
+		Name:    eos.ActN("propose"),
+		Authorization: []eos.PermissionLevel{
+			{Actor: eos.AN(viper.GetString("DAOUser")), Permission: eos.PN("active")},
+		},
+		ActionData: eos.NewActionData(proposal{
+			Proposer:      eos.AN(viper.GetString("DAOUser")),
+			ProposalType:  eos.Name(docType),
+			ContentGroups: contentGroups,
+		})}
+}
+
+func init() {
+	proposeCmd.AddCommand(proposeTemplateCmd)
+}
+
+// proposalFileContent returns the content groups of the proposal given with --from, as YAML, or
+// with --file, as a document JSON file. Overrides are the text of details items, such as those
+// given as flags, that replace the file's.
+func proposalFileContent(ctx context.Context, docType string, overrides map[string]string) ([]docgraph.ContentGroup, error) {
+	if fileName := viper.GetString("propose-cmd-from"); fileName != "" {
+		return templateContentGroups(ctx, docType, fileName, overrides)
+	}
+
+	data, err := ioutil.ReadFile(viper.GetString("propose-cmd-file"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot read file: %v %v", viper.GetString("propose-cmd-file"), err)
+	}
+
+	var proposalDoc docgraph.Document
+	err = json.Unmarshal(data, &proposalDoc)
+	if err != nil {
+		return nil, fmt.Errorf("cannot parse document: %v", err)
+	}
+	if len(proposalDoc.ContentGroups) == 0 {
+		return nil, fmt.Errorf("document has no content groups: %v", viper.GetString("propose-cmd-file"))
+	}
+
+	schema, err := getProposalSchema(docType)
+	if err != nil {
+		return nil, err
+	}
+	for label, text := range overrides {
+		field, found := schema.Field("details", label)
+		if !found {
+			return nil, fmt.Errorf("details.%v is not a field of %v", label, docType)
+		}
+		value, err := models.ParseValue(field.Type, text)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", field.Name(), err)
+		}
+		proposalDoc.ContentGroups[0] = setContent(proposalDoc.ContentGroups[0], label, value)
+	}
+	return proposalDoc.ContentGroups, nil
+}
+
+// setContent sets the item with the label in the content group, replacing the item already there
+func setContent(contentGroup docgraph.ContentGroup, label string, value *docgraph.FlexValue) docgraph.ContentGroup {
+	for i := range contentGroup {
+		if contentGroup[i].Label == label {
+			contentGroup[i].Value = value
+			return contentGroup
+		}
+	}
+	return append(contentGroup, docgraph.ContentItem{Label: label, Value: value})
+}
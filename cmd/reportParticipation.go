@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+	"github.com/tidwall/gjson"
+)
+
+var reportParticipationCmd = &cobra.Command{
+	Use:   "participation [--periods <count>]",
+	Short: "report the members' voting participation and voting power",
+	Long: `report the members' voting participation and voting power
+
+The window is the last --periods periods of the calendar, up to and including the current one.
+A member's participation is the share of the proposals created in the window the member voted
+on: document graph proposals, whose votes are read from the graph cache, and Telos Decide
+ballots, found from the castvote actions in Hyperion. A Telos Decide ballot nobody voted on in
+the window is not counted.
+
+Voting power is each member's vote token balance as a share of all members' balances. The Gini
+coefficient measures how unequally it is spread, from 0 when every member holds the same to 1,
+and the Nakamoto coefficient is the fewest members that together hold more than half of it.`,
+	Example: `  daoctl report participation --periods 6`,
+	Args:    cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		count := viper.GetInt("report-participation-cmd-periods")
+		if count < 1 {
+			return fmt.Errorf("invalid number of periods: %v, expected at least 1", count)
+		}
+		now := time.Now().UTC()
+		start, err := windowStart(ctx, gc, count, now)
+		if err != nil {
+			return err
+		}
+
+		votes := make(map[eos.Name]int)
+		proposals, err := graphProposalsSince(gc, start)
+		if err != nil {
+			return err
+		}
+		for _, hash := range proposals {
+			proposalVotes, err := latestVotes(gc, hash)
+			if err != nil {
+				return err
+			}
+			for _, vote := range proposalVotes {
+				votes[vote.Voter]++
+			}
+		}
+
+		ballots := 0
+		if viper.GetBool("report-participation-cmd-telos-decide") {
+			actions, err := getActionsSince("castvote", viper.GetString("TelosDecideContract"), start)
+			if err != nil {
+				return fmt.Errorf("cannot get castvote actions from Hyperion, or use --telos-decide=false: %v", err)
+			}
+			voted := make(map[string]map[eos.Name]bool)
+			for _, action := range actions {
+				ballot := gjson.Get(action.RawData, "ballot_name").String()
+				voter := eos.Name(gjson.Get(action.RawData, "voter").String())
+				if voted[ballot] == nil {
+					voted[ballot] = make(map[eos.Name]bool)
+				}
+				if !voted[ballot][voter] {
+					voted[ballot][voter] = true
+					votes[voter]++
+				}
+			}
+			ballots = len(voted)
+		}
+
+		members := models.Members(ctx, getReader())
+		if len(members) == 0 {
+			return fmt.Errorf("no members found in the %v members table", viper.GetString("DAOContract"))
+		}
+
+		var total eos.Int64
+		for _, member := range members {
+			total += member.VoteTokenBalance.Amount
+		}
+
+		var participations []models.Participation
+		var balances []float64
+		active := 0
+		for _, member := range members {
+			p := models.Participation{
+				Account:          member.Account,
+				VoteTokenBalance: member.VoteTokenBalance,
+				Votes:            votes[member.Account],
+				Proposals:        len(proposals) + ballots,
+			}
+			if total > 0 {
+				p.PowerShare = float64(member.VoteTokenBalance.Amount) / float64(total)
+			}
+			if p.Votes > 0 {
+				active++
+			}
+			participations = append(participations, p)
+			balances = append(balances, float64(member.VoteTokenBalance.Amount))
+		}
+		sort.Slice(participations, func(i, j int) bool {
+			if participations[i].VoteTokenBalance.Amount != participations[j].VoteTokenBalance.Amount {
+				return participations[i].VoteTokenBalance.Amount > participations[j].VoteTokenBalance.Amount
+			}
+			return participations[i].Account < participations[j].Account
+		})
+
+		totalAsset := eos.Asset{Amount: total, Symbol: members[0].VoteTokenBalance.Symbol}
+		output := []string{
+			fmt.Sprintf("Window|%v periods, since %v", count, start.Format("2006 Jan 02")),
+			fmt.Sprintf("Proposals|%v document graph, %v Telos Decide", len(proposals), ballots),
+			fmt.Sprintf("Members|%v, %v voted in the window", len(members), active),
+			fmt.Sprintf("Members' Vote Token|%v", util.FormatAsset(&totalAsset, 2)),
+			fmt.Sprintf("Gini Coefficient|%v", strconv.FormatFloat(models.Gini(balances), 'f', 3, 64)),
+			fmt.Sprintf("Nakamoto Coefficient|%v", models.Nakamoto(balances)),
+		}
+		fmt.Println("\n" + columnize.SimpleFormat(output) + "\n")
+
+		table := views.ParticipationTable(participations)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println(table.String() + "\n")
+		return nil
+	},
+}
+
+// windowStart returns the start of the period count periods back from the one containing the
+// time, or of the first period when the calendar does not reach that far back
+func windowStart(ctx context.Context, gc *util.GraphCache, count int, t time.Time) (time.Time, error) {
+	periods, err := cachedPeriods(ctx, gc)
+	if err != nil {
+		return time.Time{}, err
+	}
+	current, found := models.PeriodAt(periods, t)
+	if !found {
+		return time.Time{}, fmt.Errorf("no period of the calendar contains %v", t.Format("2006 Jan 02"))
+	}
+	first := current - count + 1
+	if first < 0 {
+		first = 0
+	}
+	return periods[first].StartTime, nil
+}
+
+// graphProposalsSince returns the hashes of the document graph proposals created since the time:
+// those the DAO links to as open, passed or failed proposals, and those with votes
+func graphProposalsSince(gc *util.GraphCache, since time.Time) ([]string, error) {
+	hashes := make(map[string]bool)
+	for _, edgeName := range []eos.Name{"proposal", "passedprops", "failedprops"} {
+		edges, err := gc.GetEdgesByName(edgeName)
+		if err != nil {
+			return nil, fmt.Errorf("cannot get %v edges: %v", edgeName, err)
+		}
+		for _, edge := range edges {
+			hashes[edge.ToNode.String()] = true
+		}
+	}
+	voteEdges, err := gc.GetEdgesByName(eos.Name("vote"))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get vote edges: %v", err)
+	}
+	for _, edge := range voteEdges {
+		hashes[edge.FromNode.String()] = true
+	}
+
+	var proposals []string
+	for hash := range hashes {
+		document, found, err := gc.GetDocument(hash)
+		if err != nil {
+			return nil, err
+		}
+		if found && !document.CreatedDate.Time.Before(since) {
+			proposals = append(proposals, hash)
+		}
+	}
+	sort.Strings(proposals)
+	return proposals, nil
+}
+
+func init() {
+	reportCmd.AddCommand(reportParticipationCmd)
+	reportParticipationCmd.Flags().IntP("periods", "", 6, "number of periods, up to and including the current one")
+	reportParticipationCmd.Flags().BoolP("telos-decide", "", true, "include the Telos Decide ballots voted on with castvote")
+}
@@ -2,9 +2,8 @@ package cmd
 
 import (
 	"bytes"
-	"context"
-	"encoding/hex"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
@@ -12,6 +11,7 @@ import (
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 
+	"github.com/hypha-dao/daoctl/hyperion"
 	homedir "github.com/mitchellh/go-homedir"
 	"github.com/spf13/viper"
 )
@@ -19,6 +19,7 @@ import (
 var cfgFile string
 
 var yamlDefault = []byte(`
+Network: mainnet
 EosioEndpoint: https://telos.caleos.io
 AssetsAsFloat: true
 DAOContract: dao.hypha
@@ -57,6 +58,9 @@ Example use:
 
 Hypha - Dapps for a New World - visit online @ hypha.earth`,
 	SilenceUsage: true,
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return saveRecordedFixture()
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
@@ -84,6 +88,11 @@ func init() {
 	RootCmd.PersistentFlags().BoolP("active", "a", true, "show active objects")
 	RootCmd.PersistentFlags().BoolP("failed-proposals", "", false, "include a table with failed proposals")
 	RootCmd.PersistentFlags().StringP("file", "f", "", "filename")
+	RootCmd.PersistentFlags().StringP("network", "", "", "network profile to use, e.g. mainnet, testnet or local (default is the Network setting in daoctl.yaml)")
+	RootCmd.PersistentFlags().StringP("fixture", "", "", "serve chain reads from a recorded fixture file instead of the node")
+	RootCmd.PersistentFlags().StringP("fixture-record", "", "", "record every chain read into this fixture file for offline replay")
+	RootCmd.PersistentFlags().StringP("record", "", "", "record every node and Hyperion HTTP request and response into this cassette directory")
+	RootCmd.PersistentFlags().StringP("replay", "", "", "serve node and Hyperion HTTP requests from this cassette directory instead of the network")
 
 }
 
@@ -120,22 +129,9 @@ func initConfig() {
 	viper.SetEnvKeyReplacer(replacer)
 
 	recurseViperCommands(RootCmd, nil)
-
-	api := getAPI()
-	colorRed := "\033[31m"
-	colorCyan := "\033[36m"
-	colorReset := "\033[0m"
-	info, err := api.GetInfo(context.Background())
-	if err != nil {
-		zlog.Fatal(string(colorRed) + "ERROR: Unable to get Hypha Blockchain Node info. Please check the EosioEndpoint configuration.")
-	}
-
-	if hex.EncodeToString(info.ChainID) == "4667b205c6838ef70ff7988f6e8257e8be0e1284a2f59699054a018f743b1d11" {
-		fmt.Println(string(colorRed) + "\nWARNING: Connecting to the Hypha Production Mainnet")
-	} else if hex.EncodeToString(info.ChainID) == "1eaa0824707c8c16bd25145493bf062aecddfeb56c736f6ba6397f3195f33c9f" {
-		fmt.Println(string(colorCyan) + "\nNETWORK: Connecting to the Hypha Test Network")
-	}
-	fmt.Println(string(colorReset))
+	errorCheck("applying network profile", applyNetworkProfile())
+	setFixtureCacheFile()
+	hyperion.HTTPClient.Transport = getTransport(http.DefaultTransport)
 }
 
 func recurseViperCommands(root *cobra.Command, segments []string) {
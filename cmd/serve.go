@@ -13,9 +13,9 @@ import (
 
 	"github.com/eoscanada/eos-go"
 	"github.com/hypha-dao/dao-contracts/dao-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/hyperion"
 	"github.com/hypha-dao/daoctl/models"
-	"github.com/hypha-dao/document-graph/docgraph"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/spf13/cobra"
@@ -31,7 +31,7 @@ func getVotingEvents(ctx context.Context) {
 	const Format = "2006-01-02T15:04:05"
 }
 
-func getTokenBalance(ctx context.Context, api *eos.API, tokenContract, accountname, symbol string) (eos.Asset, error) {
+func getTokenBalance(ctx context.Context, reader chain.ChainReader, tokenContract, accountname, symbol string) (eos.Asset, error) {
 	type Balance struct {
 		Balance eos.Asset `json:"balance"`
 	}
@@ -43,7 +43,7 @@ func getTokenBalance(ctx context.Context, api *eos.API, tokenContract, accountna
 	request.Table = "accounts"
 	request.Limit = 100
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 	if err != nil {
 		return eos.Asset{}, fmt.Errorf("could not get balance: GetTableRows: token contract: "+tokenContract+" account: "+accountname+" symbol: "+symbol+": %v", err)
 	}
@@ -66,7 +66,7 @@ func getTokenBalance(ctx context.Context, api *eos.API, tokenContract, accountna
 	return eos.Asset{}, fmt.Errorf("could not get balance: no rows match the symbol provided: token contract: " + tokenContract + " account: " + accountname + " symbol: " + symbol)
 }
 
-func getTokenSupply(ctx context.Context, api *eos.API, tokenContract, symbol string) (eos.Asset, error) {
+func getTokenSupply(ctx context.Context, reader chain.ChainReader, tokenContract, symbol string) (eos.Asset, error) {
 	type Supply struct {
 		TokenSupply eos.Asset `json:"supply"`
 	}
@@ -78,7 +78,7 @@ func getTokenSupply(ctx context.Context, api *eos.API, tokenContract, symbol str
 	request.Table = "stat"
 	request.Limit = 1
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 
 	if err != nil {
 		return eos.Asset{}, fmt.Errorf("could not get supply: GetTableRows: token contract: "+tokenContract+"  symbol: "+symbol+": %v", err)
@@ -95,7 +95,7 @@ func getTokenSupply(ctx context.Context, api *eos.API, tokenContract, symbol str
 	return supply[0].TokenSupply, nil
 }
 
-func getSeedsUsdPrice(ctx context.Context, api *eos.API) (eos.Asset, error) {
+func getSeedsUsdPrice(ctx context.Context, reader chain.ChainReader) (eos.Asset, error) {
 	var priceHistory []dao.SeedsPriceHistory
 	var request eos.GetTableRowsRequest
 	request.Code = "tlosto.seeds"
@@ -104,7 +104,7 @@ func getSeedsUsdPrice(ctx context.Context, api *eos.API) (eos.Asset, error) {
 	request.Reverse = true
 	request.Limit = 1
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 	if err != nil {
 		return eos.Asset{}, fmt.Errorf("could not get Seeds/USD price: %v", err)
 	}
@@ -135,7 +135,7 @@ var serveCmd = &cobra.Command{
 
 	Run: func(cmd *cobra.Command, args []string) {
 		ctx := context.Background()
-		api := getAPI()
+		reader := getReader()
 
 		log.Println(yamlStringSettings())
 
@@ -207,7 +207,7 @@ var serveCmd = &cobra.Command{
 		// Periodically update the metrics
 		go func() {
 			for {
-				sup, err := models.GetHvoiceSupply(ctx, api)
+				sup, err := models.GetHvoiceSupply(ctx, reader)
 				if err == nil {
 					hvoiceSupply.Set(assetToFloat(sup))
 				} else {
@@ -215,7 +215,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: telos decide supply: telosdecide: "+viper.GetString("TelosDecideContract")+" symbol: "+viper.GetString("VoteTokenSymbol"), err)
 				}
 
-				hypha, err := getTokenSupply(ctx, api, viper.GetString("RewardToken.Contract"), viper.GetString("RewardToken.Symbol"))
+				hypha, err := getTokenSupply(ctx, reader, viper.GetString("RewardToken.Contract"), viper.GetString("RewardToken.Symbol"))
 				if err == nil {
 					hyphaSupply.Set(assetToFloat(&hypha))
 				} else {
@@ -223,7 +223,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: supply: token contract: "+viper.GetString("RewardToken.Contract")+" symbol: "+viper.GetString("RewardToken.Symbol"), err)
 				}
 
-				husd, err := getTokenSupply(ctx, api, viper.GetString("Treasury.TokenContract"), viper.GetString("Treasury.Symbol"))
+				husd, err := getTokenSupply(ctx, reader, viper.GetString("Treasury.TokenContract"), viper.GetString("Treasury.Symbol"))
 				if err == nil {
 					husdSupply.Set(assetToFloat(&husd))
 				} else {
@@ -231,7 +231,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: supply: token contract: "+viper.GetString("Treasury.TokenContract")+" symbol: "+viper.GetString("Treasury.Symbol"), err)
 				}
 
-				seeds, err := getTokenBalance(ctx, api, viper.GetString("SeedsTokenContract"), viper.GetString("DAOContract"), "SEEDS")
+				seeds, err := getTokenBalance(ctx, reader, viper.GetString("SeedsTokenContract"), viper.GetString("DAOContract"), "SEEDS")
 				if err == nil {
 					seedsBalance.Set(assetToFloat(&seeds))
 				} else {
@@ -239,7 +239,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: balance: "+viper.GetString("DAOContract")+" token contract: "+viper.GetString("SeedsTokenContract")+" symbol: SEEDS", err)
 				}
 
-				escrowedSeeds, err := getTokenBalance(ctx, api, viper.GetString("SeedsTokenContract"), viper.GetString("EscrowContract"), "SEEDS")
+				escrowedSeeds, err := getTokenBalance(ctx, reader, viper.GetString("SeedsTokenContract"), viper.GetString("EscrowContract"), "SEEDS")
 				if err == nil {
 					escrowedSeedsBalance.Set(assetToFloat(&escrowedSeeds))
 				} else {
@@ -247,7 +247,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: balance: "+viper.GetString("EscrowContract")+" token contract: "+viper.GetString("SeedsTokenContract")+" symbol: SEEDS", err)
 				}
 
-				hyphaSeedsAccountSeeds, err := getTokenBalance(ctx, api, viper.GetString("SeedsTokenContract"), viper.GetString("HyphaSeedsAccount"), "SEEDS")
+				hyphaSeedsAccountSeeds, err := getTokenBalance(ctx, reader, viper.GetString("SeedsTokenContract"), viper.GetString("HyphaSeedsAccount"), "SEEDS")
 				if err == nil {
 					hyphaSeedsAccountBalance.Set(assetToFloat(&hyphaSeedsAccountSeeds))
 				} else {
@@ -255,13 +255,13 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: balance: "+viper.GetString("HyphaSeedsAccount")+" token contract: "+viper.GetString("SeedsTokenContract")+" symbol: SEEDS", err)
 				}
 
-				members := models.Members(ctx, api)
+				members := models.Members(ctx, reader)
 				memberCount.Set(float64(len(members)))
 
-				applicants := models.Applicants(ctx, api)
+				applicants := models.Applicants(ctx, reader)
 				applicantCount.Set(float64(len(applicants)))
 
-				// proposals, err := getLegacyObjects(ctx, api, eos.AN(viper.GetString("DAOContract")), eos.Name("proposal"))
+				// proposals, err := getLegacyObjects(ctx, reader, eos.AN(viper.GetString("DAOContract")), eos.Name("proposal"))
 				// if err == nil {
 				// 	openProposals.Set(float64(len(proposals)))
 				// } else {
@@ -269,7 +269,7 @@ var serveCmd = &cobra.Command{
 				// 	log.Println("Retrieval error: an error querying legacy objects from "+viper.GetString("DAOContract")+" scope: proposal", err)
 				// }
 
-				seedsPriceUsdAsset, err := getSeedsUsdPrice(ctx, api)
+				seedsPriceUsdAsset, err := getSeedsUsdPrice(ctx, reader)
 				if err == nil {
 					seedsPriceUsd.Set(assetToFloat(&seedsPriceUsdAsset))
 				} else {
@@ -277,7 +277,7 @@ var serveCmd = &cobra.Command{
 					log.Println("Retrieval error: an error querying the total number of documents from "+viper.GetString("DAOContract"), err)
 				}
 
-				docs, err := docgraph.GetAllDocuments(ctx, api, eos.AN(viper.GetString("DAOContract")))
+				docs, err := reader.GetAllDocuments(ctx, eos.AN(viper.GetString("DAOContract")))
 				if err == nil {
 					documentCount.Set(float64(len(docs)))
 				} else {
@@ -0,0 +1,14 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "simulate the outcome of governance actions",
+}
+
+func init() {
+	RootCmd.AddCommand(simulateCmd)
+}
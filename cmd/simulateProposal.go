@@ -0,0 +1,163 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/alexeyco/simpletable"
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/daoctl/views"
+	"github.com/ryanuber/columnize"
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+)
+
+var simulateProposalCmd = &cobra.Command{
+	Use:   "proposal [hash | shorty | id | label]",
+	Short: "simulate how the remaining votes could decide a proposal",
+	Long: `simulate how the remaining votes could decide a proposal
+
+The ballot's current tally is compared with the vote token balances of the members who have not
+voted, using the quorum and alignment of the DAO settings: whether the proposal can still pass if
+they all vote pass, whether it can still fail if they all vote fail or do not vote, and the least
+vote power that would change its current outcome. Members whose vote alone would change it, and
+the fewest members that together could, are marked as swing voters. Members whose vote token
+balance is not in the symbol and precision of the ballot's supply are left out of the simulation
+and listed as skipped.`,
+	Example: `  daoctl simulate proposal e8d9efca`,
+	Args:    cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		proposal, err := gc.ResolveDocument(args[0])
+		if err != nil {
+			return err
+		}
+		ballot, err := loadProposalBallot(ctx, gc, proposal)
+		if err != nil {
+			return err
+		}
+		if ballot.Status != proposalOpen {
+			return fmt.Errorf("cannot simulate proposal: %v is %v", proposal.Hash.String(), ballot.Status)
+		}
+
+		members := models.Members(ctx, getReader())
+		if len(members) == 0 {
+			return fmt.Errorf("no members found in the %v members table", viper.GetString("DAOContract"))
+		}
+		voters, remaining, skipped := remainingVoters(ballot, members)
+
+		passing := ballot.Passing()
+		canPass := ballot.WithVotes(models.VotePass, remaining.Amount).Passing()
+		canFail := !passing || !ballot.WithVotes(models.VoteFail, remaining.Amount).Passing()
+
+		option, verb := models.VoteFail, "stop it passing"
+		needed, possible := ballot.FailVotesNeeded()
+		if !passing {
+			option, verb = models.VotePass, "pass it"
+			needed, possible = ballot.PassVotesNeeded()
+		}
+		neededAsset := eos.Asset{Amount: needed, Symbol: ballot.Supply.Symbol}
+		neededText := fmt.Sprintf("%v %v votes", util.FormatAsset(&neededAsset, 2), option)
+		if !possible {
+			neededText = "no vote can"
+		}
+		markSwingVoters(voters, needed, possible)
+
+		cast := ballot.Cast()
+		quorumVotes := ballot.QuorumVotes()
+		output := []string{
+			fmt.Sprintf("Proposal|%v", proposal.Hash.String()),
+			fmt.Sprintf("Title|%v", ballot.Title),
+			fmt.Sprintf("Time Remaining|%v", formatRemaining(ballot.Remaining(time.Now().UTC()))),
+			fmt.Sprintf("Tally|pass %v, fail %v, abstain %v", util.FormatAsset(&ballot.Tally.Pass, 2),
+				util.FormatAsset(&ballot.Tally.Fail, 2), util.FormatAsset(&ballot.Tally.Abstain, 2)),
+			fmt.Sprintf("Quorum|%v cast of %v needed", util.FormatAsset(&cast, 2), util.FormatAsset(&quorumVotes, 2)),
+			fmt.Sprintf("Alignment|pass %v%% of %v%% needed", percentText(ballot.PassShare()), percentText(ballot.Alignment)),
+			fmt.Sprintf("Passing Now|%v", passing),
+			fmt.Sprintf("Remaining Vote Power|%v from %v members who have not voted", util.FormatAsset(&remaining, 2), len(voters)),
+			fmt.Sprintf("Can Still Pass|%v", canPass),
+			fmt.Sprintf("Can Still Fail|%v", canFail),
+			fmt.Sprintf("Needed to %v|%v", verb, neededText),
+		}
+		if len(skipped) > 0 {
+			var balances []string
+			for _, member := range skipped {
+				balances = append(balances, fmt.Sprintf("%v (%v)", member.Account, member.VoteTokenBalance.String()))
+			}
+			output = append(output, fmt.Sprintf("Skipped Members|%v not in %v: %v", len(skipped),
+				ballot.Supply.Symbol.String(), strings.Join(balances, ", ")))
+		}
+		fmt.Println("\n" + columnize.SimpleFormat(output) + "\n")
+
+		table := views.SwingVoterTable(voters)
+		table.SetStyle(simpletable.StyleCompactLite)
+		fmt.Println("Members who have not voted\n\n" + table.String() + "\n")
+		return nil
+	},
+}
+
+// remainingVoters returns the members with vote power who have not voted on the ballot, ordered
+// by vote power, and their total vote power, and the members who have not voted whose balance is
+// in a different symbol or precision than the ballot's supply
+func remainingVoters(ballot models.ProposalBallot, members []models.Member) ([]models.SwingVoter, eos.Asset, []models.Member) {
+	voted := make(map[eos.Name]bool)
+	for _, vote := range ballot.Votes {
+		voted[vote.Voter] = true
+	}
+
+	remaining := eos.Asset{Symbol: ballot.Supply.Symbol}
+	var voters []models.SwingVoter
+	var skipped []models.Member
+	for _, member := range members {
+		if voted[member.Account] || member.VoteTokenBalance.Amount <= 0 {
+			continue
+		}
+		if member.VoteTokenBalance.Symbol != ballot.Supply.Symbol {
+			skipped = append(skipped, member)
+			continue
+		}
+		voters = append(voters, models.SwingVoter{Account: member.Account, VotePower: member.VoteTokenBalance})
+		remaining.Amount += member.VoteTokenBalance.Amount
+	}
+	sort.Slice(voters, func(i, j int) bool {
+		if voters[i].VotePower.Amount != voters[j].VotePower.Amount {
+			return voters[i].VotePower.Amount > voters[j].VotePower.Amount
+		}
+		return voters[i].Account < voters[j].Account
+	})
+	return voters, remaining, skipped
+}
+
+// markSwingVoters marks the voters with the vote power needed to change the outcome alone, and
+// the fewest voters with it together, who are those with the most vote power
+func markSwingVoters(voters []models.SwingVoter, needed eos.Int64, possible bool) {
+	if !possible || needed == 0 {
+		return
+	}
+
+	var sum eos.Int64
+	coalition := 0
+	for coalition < len(voters) && sum < needed {
+		sum += voters[coalition].VotePower.Amount
+		coalition++
+	}
+	for i := range voters {
+		voters[i].Alone = voters[i].VotePower.Amount >= needed
+		voters[i].Coalition = sum >= needed && i < coalition
+	}
+}
+
+func init() {
+	simulateCmd.AddCommand(simulateProposalCmd)
+}
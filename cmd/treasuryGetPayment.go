@@ -23,7 +23,7 @@ var treasuryGetPaymentCmd = &cobra.Command{
 			return
 		}
 
-		payment, err := models.LoadPaymentByID(ctx, getAPI(), paymentID)
+		payment, err := models.LoadPaymentByID(ctx, getReader(), paymentID)
 		if err != nil {
 			fmt.Println("Payment ID not found")
 			return
@@ -8,7 +8,7 @@ import (
 	"os"
 
 	"github.com/alexeyco/simpletable"
-	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/models"
 	"github.com/hypha-dao/daoctl/views"
 	"github.com/spf13/cobra"
@@ -23,7 +23,7 @@ var treasuryGetPaymentsCmd = &cobra.Command{
 		ctx := context.Background()
 
 		if viper.GetBool("global-csv") {
-			payments := models.Payments(ctx, getAPI())
+			payments := models.Payments(ctx, getReader())
 			paymentsTable := views.PaymentTable(payments)
 			csvData := models.TableToData(paymentsTable)
 
@@ -41,14 +41,14 @@ var treasuryGetPaymentsCmd = &cobra.Command{
 				log.Fatalln("error writing csv:", err)
 			}
 		} else {
-			printPaymentsTable(ctx, getAPI(), "HUSD Payments")
+			printPaymentsTable(ctx, getReader(), "HUSD Payments")
 		}
 	},
 }
 
-func printPaymentsTable(ctx context.Context, api *eos.API, title string) {
+func printPaymentsTable(ctx context.Context, reader chain.ChainReader, title string) {
 	fmt.Println("\n", title)
-	payments := models.Payments(ctx, api)
+	payments := models.Payments(ctx, reader)
 	paymentsTable := views.PaymentTable(payments)
 	paymentsTable.SetStyle(simpletable.StyleCompactLite)
 	fmt.Println("\n" + paymentsTable.String() + "\n\n")
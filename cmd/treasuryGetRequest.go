@@ -23,7 +23,7 @@ var treasuryGetRequestCmd = &cobra.Command{
 			return
 		}
 
-		request, err := models.LoadRequestByID(ctx, getAPI(), requestID)
+		request, err := models.LoadRequestByID(ctx, getReader(), requestID)
 		if err != nil {
 			fmt.Println("Request ID not found")
 			return
@@ -8,7 +8,7 @@ import (
 	"os"
 
 	"github.com/alexeyco/simpletable"
-	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/models"
 	"github.com/hypha-dao/daoctl/views"
 
@@ -24,7 +24,7 @@ var treasuryGetRequestsCmd = &cobra.Command{
 		ctx := context.Background()
 
 		if viper.GetBool("global-csv") {
-			requests := models.Requests(ctx, getAPI(), viper.GetBool("treasury-get-requests-cmd-all"))
+			requests := models.Requests(ctx, getReader(), viper.GetBool("treasury-get-requests-cmd-all"))
 			requestsTable := views.RequestTable(requests)
 			csvData := models.TableToData(requestsTable)
 
@@ -42,14 +42,14 @@ var treasuryGetRequestsCmd = &cobra.Command{
 				log.Fatalln("error writing csv:", err)
 			}
 		} else {
-			printRequestsTable(ctx, getAPI(), "HUSD Redemption Requests")
+			printRequestsTable(ctx, getReader(), "HUSD Redemption Requests")
 		}
 	},
 }
 
-func printRequestsTable(ctx context.Context, api *eos.API, title string) {
+func printRequestsTable(ctx context.Context, reader chain.ChainReader, title string) {
 	fmt.Println("\n", title)
-	requests := models.Requests(ctx, api, viper.GetBool("treasury-get-requests-cmd-all"))
+	requests := models.Requests(ctx, reader, viper.GetBool("treasury-get-requests-cmd-all"))
 	requestsTable := views.RequestTable(requests)
 	requestsTable.SetStyle(simpletable.StyleCompactLite)
 	fmt.Println("\n" + requestsTable.String() + "\n\n")
@@ -1,51 +1,184 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/ryanuber/columnize"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var voteCmd = &cobra.Command{
-	Use:   "vote ballot_id pass|fail",
-	Short: "vote pass or fail on a ballot",
-	Long:  "vote on a specific ballot.  Example:  daoctl vote 34 pass",
-	Args:  cobra.RangeArgs(2, 2),
-	Run: func(cmd *cobra.Command, args []string) {
-		//fmt.Println("vote pass <ballot_id> is not yet implemented")
+	Use:   "vote [hash | shorty | id | label] pass|fail|abstain",
+	Short: "vote pass, fail or abstain on a proposal",
+	Long: `vote pass, fail or abstain on a proposal with the DAO contract's vote action
 
-		ballotName := eos.Name(viper.GetString("BallotPrefix") + args[0]) // TODO: this will break; need to make the prefix dynamic
-		option := eos.Name(args[1])                                       // only supporting a single option value, will enhance to include multi-value later
+The proposal is resolved against the graph cache and must be open for voting. With --from-file
+the votes on many proposals are cast in one transaction, read from a file with a proposal
+reference and a vote on each line; blank lines and lines starting with # are skipped.`,
+	Example: `  daoctl vote e8d9efca pass
+  daoctl vote --from-file votes.txt --dry-run`,
+	Args: func(cmd *cobra.Command, args []string) error {
+		if viper.GetString("vote-cmd-from-file") != "" {
+			return cobra.NoArgs(cmd, args)
+		}
+		return cobra.ExactArgs(2)(cmd, args)
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx := context.Background()
+
+		lines := [][]string{args}
+		if fileName := viper.GetString("vote-cmd-from-file"); fileName != "" {
+			var err error
+			lines, err = readVoteFile(fileName)
+			if err != nil {
+				return err
+			}
+		}
+
+		gc, err := getGraphCache(ctx)
+		if err != nil {
+			return err
+		}
+		defer gc.Close()
+
+		var actions []*eos.Action
+		var output []string
+		voted := make(map[string]bool)
+		for _, line := range lines {
+			proposal, vote, err := resolveVote(gc, line[0], line[1])
+			if err != nil {
+				return err
+			}
+			hash := proposal.Hash.String()
+			if voted[hash] {
+				return fmt.Errorf("cannot vote twice on proposal in one transaction: %v", hash)
+			}
+			voted[hash] = true
+
+			docType, _ := proposal.GetType()
+			output = append(output, fmt.Sprintf("%v|%v|%v|%v", hash[:8], docType, proposalTitle(proposal), vote))
+			actions = append(actions, newVoteAction(proposal.Hash, vote))
+		}
+
+		fmt.Println("\n" + columnize.SimpleFormat(output) + "\n")
 
-		pushEOSCActions(context.Background(), getAPI(), newVoteAction(ballotName, option))
+		if viper.GetBool("vote-cmd-dry-run") {
+			for _, action := range actions {
+				// show the action data as fields rather than packed hex
+				act, _ := json.MarshalIndent(struct {
+					Account       eos.AccountName       `json:"account"`
+					Name          eos.ActionName        `json:"name"`
+					Authorization []eos.PermissionLevel `json:"authorization"`
+					Data          interface{}           `json:"data"`
+				}{action.Account, action.Name, action.Authorization, action.ActionData.Data}, "", " ")
+				fmt.Println(string(act))
+			}
+			fmt.Printf("\nDry run: %v vote actions not pushed\n", len(actions))
+			return nil
+		}
+
+		pushEOSCActions(ctx, getAPI(), actions...)
+		return nil
 	},
 }
 
-// Vote represents a set of options being cast as a vote to Telos Decide
-type Vote struct {
-	Voter      eos.Name   `json:"voter"`
-	BallotName eos.Name   `json:"ballot_name"`
-	Options    []eos.Name `json:"options"`
+// resolveVote resolves the proposal a vote is cast on, which must be open for voting, and checks
+// the vote is one of the options
+func resolveVote(gc *util.GraphCache, reference, vote string) (docgraph.Document, string, error) {
+	vote = strings.ToLower(vote)
+	switch vote {
+	case models.VotePass, models.VoteFail, models.VoteAbstain:
+	default:
+		return docgraph.Document{}, "", fmt.Errorf("invalid vote: %v on %v, expected pass, fail or abstain", vote, reference)
+	}
+
+	proposal, err := gc.ResolveDocument(reference)
+	if err != nil {
+		return docgraph.Document{}, "", err
+	}
+	settings, err := cachedSettings(gc)
+	if err != nil {
+		return docgraph.Document{}, "", err
+	}
+
+	docType, err := proposal.GetType()
+	if err != nil {
+		return docgraph.Document{}, "", fmt.Errorf("cannot get type of proposal: %v %v", proposal.Hash.String(), err)
+	}
+	status, err := proposalStatus(gc, proposal, docType, ballotEnd(proposal, settings))
+	if err != nil {
+		return docgraph.Document{}, "", err
+	}
+	if status != proposalOpen {
+		return docgraph.Document{}, "", fmt.Errorf("cannot vote on proposal: %v is %v", proposal.Hash.String(), status)
+	}
+	return proposal, vote, nil
 }
 
-func newVoteAction(ballotName, option eos.Name) *eos.Action {
+// readVoteFile reads the proposal reference and vote on each line of the file
+func readVoteFile(fileName string) ([][]string, error) {
+	file, err := os.Open(fileName)
+	if err != nil {
+		return nil, fmt.Errorf("cannot read votes file: %v %v", fileName, err)
+	}
+	defer file.Close()
+
+	var lines [][]string
+	scanner := bufio.NewScanner(file)
+	for number := 1; scanner.Scan(); number++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		// the reference may be a node label containing spaces, so the vote is the last field
+		split := strings.LastIndexAny(line, " \t")
+		if split < 0 {
+			return nil, fmt.Errorf("invalid line %v of votes file: %q, expected a proposal and a vote", number, line)
+		}
+		lines = append(lines, []string{strings.TrimSpace(line[:split]), line[split+1:]})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("cannot read votes file: %v %v", fileName, err)
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no votes in file: %v", fileName)
+	}
+	return lines, nil
+}
+
+type proposalVote struct {
+	Voter        eos.Name        `json:"voter"`
+	ProposalHash eos.Checksum256 `json:"proposal_hash"`
+	Vote         string          `json:"vote"`
+}
 
+func newVoteAction(proposalHash eos.Checksum256, vote string) *eos.Action {
 	return &eos.Action{
-		Account: eos.AN(viper.GetString("TelosDecideContract")),
-		Name:    eos.ActN("castvote"),
+		Account: eos.AN(viper.GetString("DAOContract")),
+		Name:    eos.ActN("vote"),
 		Authorization: []eos.PermissionLevel{
 			{Actor: eos.AN(viper.GetString("DAOUser")), Permission: eos.PN("active")},
 		},
-		ActionData: eos.NewActionData(&Vote{
-			Voter:      eos.Name(viper.GetString("DAOUser")),
-			BallotName: ballotName,
-			Options:    []eos.Name{option},
+		ActionData: eos.NewActionData(proposalVote{
+			Voter:        eos.Name(viper.GetString("DAOUser")),
+			ProposalHash: proposalHash,
+			Vote:         vote,
 		}),
 	}
 }
 
 func init() {
 	RootCmd.AddCommand(voteCmd)
+	voteCmd.Flags().StringP("from-file", "", "", "file with a proposal reference and a vote on each line")
+	voteCmd.Flags().BoolP("dry-run", "", false, "print the vote actions without pushing them")
 }
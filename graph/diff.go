@@ -0,0 +1,82 @@
+package graph
+
+import (
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// FieldChange is a content item added, removed or changed between two versions of a document.
+// Before is nil for an added item and After is nil for a removed one.
+type FieldChange struct {
+	Group  string
+	Label  string
+	Before *docgraph.ContentItem
+	After  *docgraph.ContentItem
+}
+
+// Field returns the change's field name as used in queries, group.label
+func (c *FieldChange) Field() string {
+	if c.Group == "" {
+		return c.Label
+	}
+	return c.Group + "." + c.Label
+}
+
+// DiffContent compares content groups item by item, matching groups by their content_group_label
+// and items by label, and returns the changes in the order of the groups and items
+func DiffContent(before, after []docgraph.ContentGroup) []FieldChange {
+	previous := contentItems(before)
+	current := contentItems(after)
+
+	var changes []FieldChange
+	for _, key := range current.keys {
+		item := current.items[key]
+		old, found := previous.items[key]
+		if !found {
+			changes = append(changes, FieldChange{Group: key.group, Label: key.label, After: item})
+		} else if itemValue(old) != itemValue(item) {
+			changes = append(changes, FieldChange{Group: key.group, Label: key.label, Before: old, After: item})
+		}
+	}
+	for _, key := range previous.keys {
+		if _, found := current.items[key]; !found {
+			changes = append(changes, FieldChange{Group: key.group, Label: key.label, Before: previous.items[key]})
+		}
+	}
+	return changes
+}
+
+type itemKey struct {
+	group string
+	label string
+}
+
+type itemIndex struct {
+	keys  []itemKey
+	items map[itemKey]*docgraph.ContentItem
+}
+
+func contentItems(contentGroups []docgraph.ContentGroup) itemIndex {
+	index := itemIndex{items: make(map[itemKey]*docgraph.ContentItem)}
+	for _, contentGroup := range contentGroups {
+		group := contentGroupLabel(contentGroup)
+		for i := range contentGroup {
+			item := &contentGroup[i]
+			if item.Label == "content_group_label" {
+				continue
+			}
+			key := itemKey{group: group, label: item.Label}
+			if _, found := index.items[key]; !found {
+				index.keys = append(index.keys, key)
+			}
+			index.items[key] = item
+		}
+	}
+	return index
+}
+
+func itemValue(item *docgraph.ContentItem) string {
+	if item.Value == nil {
+		return ""
+	}
+	return item.Value.String()
+}
@@ -0,0 +1,369 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// ExportFormats are the formats accepted by Export
+var ExportFormats = []string{"dot", "graphml", "cypher", "json-graph"}
+
+// Subgraph is a set of documents and the edges between them
+type Subgraph struct {
+	Documents []docgraph.Document
+	Edges     []docgraph.Edge
+}
+
+// SubgraphFilter restricts the documents to those of the types and the edges to those with the
+// names; an empty list allows all
+type SubgraphFilter struct {
+	Types     []string
+	EdgeNames []string
+}
+
+func (f SubgraphFilter) allowsDocument(document *docgraph.Document) bool {
+	if len(f.Types) == 0 {
+		return true
+	}
+	docType, _ := FieldValue(document, "type")
+	return contains(f.Types, FormatValue(docType))
+}
+
+func (f SubgraphFilter) allowsEdge(edge *docgraph.Edge) bool {
+	return len(f.EdgeNames) == 0 || contains(f.EdgeNames, string(edge.EdgeName))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// WholeGraph returns the documents allowed by the filter and the allowed edges between them
+func WholeGraph(documents []docgraph.Document, edges []docgraph.Edge, filter SubgraphFilter) Subgraph {
+	var subgraph Subgraph
+	included := make(map[string]bool)
+	for i := range documents {
+		if filter.allowsDocument(&documents[i]) {
+			subgraph.Documents = append(subgraph.Documents, documents[i])
+			included[documents[i].Hash.String()] = true
+		}
+	}
+	subgraph.Edges = edgesBetween(edges, included, filter)
+	return subgraph
+}
+
+// Neighbourhood returns the documents within the number of hops of the root, following allowed
+// edges in either direction through allowed documents, and the allowed edges between them. The
+// root is always included.
+func Neighbourhood(source Source, root docgraph.Document, hops int, filter SubgraphFilter) (Subgraph, error) {
+	documents := map[string]docgraph.Document{root.Hash.String(): root}
+	var edges []docgraph.Edge
+	seenEdges := make(map[uint64]bool)
+	frontier := []string{root.Hash.String()}
+
+	for hop := 0; hop < hops && len(frontier) > 0; hop++ {
+		var next []string
+		for _, hash := range frontier {
+			from, err := source.GetEdgesFrom(hash, "")
+			if err != nil {
+				return Subgraph{}, fmt.Errorf("cannot read edges of document: %v %v", hash, err)
+			}
+			to, err := source.GetEdgesTo(hash, "")
+			if err != nil {
+				return Subgraph{}, fmt.Errorf("cannot read edges of document: %v %v", hash, err)
+			}
+
+			for _, edge := range append(from, to...) {
+				if seenEdges[edge.ID] || !filter.allowsEdge(&edge) {
+					continue
+				}
+				seenEdges[edge.ID] = true
+				edges = append(edges, edge)
+
+				neighbour := edge.ToNode.String()
+				if neighbour == hash {
+					neighbour = edge.FromNode.String()
+				}
+				if _, found := documents[neighbour]; found {
+					continue
+				}
+
+				document, found, err := source.GetDocument(neighbour)
+				if err != nil {
+					return Subgraph{}, err
+				}
+				if found && filter.allowsDocument(&document) {
+					documents[neighbour] = document
+					next = append(next, neighbour)
+				}
+			}
+		}
+		frontier = next
+	}
+
+	var subgraph Subgraph
+	included := make(map[string]bool)
+	for hash, document := range documents {
+		subgraph.Documents = append(subgraph.Documents, document)
+		included[hash] = true
+	}
+	sort.Slice(subgraph.Documents, func(i, j int) bool {
+		return subgraph.Documents[i].ID < subgraph.Documents[j].ID
+	})
+	subgraph.Edges = edgesBetween(edges, included, filter)
+	return subgraph, nil
+}
+
+func edgesBetween(edges []docgraph.Edge, included map[string]bool, filter SubgraphFilter) []docgraph.Edge {
+	var between []docgraph.Edge
+	for i := range edges {
+		if filter.allowsEdge(&edges[i]) && included[edges[i].FromNode.String()] && included[edges[i].ToNode.String()] {
+			between = append(between, edges[i])
+		}
+	}
+	sort.Slice(between, func(i, j int) bool {
+		return between[i].ID < between[j].ID
+	})
+	return between
+}
+
+// CheckExportFormat returns an error unless the format is one of the ExportFormats
+func CheckExportFormat(format string) error {
+	if !contains(ExportFormats, format) {
+		return fmt.Errorf("unknown export format: %v, expected one of %v", format, strings.Join(ExportFormats, ", "))
+	}
+	return nil
+}
+
+// Export writes the subgraph in one of the ExportFormats
+func Export(w io.Writer, subgraph Subgraph, format string) error {
+	if err := CheckExportFormat(format); err != nil {
+		return err
+	}
+
+	switch format {
+	case "dot":
+		return exportDOT(w, subgraph)
+	case "graphml":
+		return exportGraphML(w, subgraph)
+	case "cypher":
+		return exportCypher(w, subgraph)
+	case "json-graph":
+		return exportJSONGraph(w, subgraph)
+	}
+	return nil
+}
+
+func nodeLabel(document *docgraph.Document) string {
+	label, _ := FieldValue(document, "label")
+	return FormatValue(label)
+}
+
+func nodeType(document *docgraph.Document) string {
+	docType, _ := FieldValue(document, "type")
+	return FormatValue(docType)
+}
+
+func timestamp(t eos.BlockTimestamp) string {
+	return t.Time.UTC().Format("2006-01-02T15:04:05")
+}
+
+func exportDOT(w io.Writer, subgraph Subgraph) error {
+	quote := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+	fmt.Fprintln(w, "digraph dao {")
+	fmt.Fprintln(w, "  node [shape=box];")
+	for i := range subgraph.Documents {
+		document := &subgraph.Documents[i]
+		fmt.Fprintf(w, "  \"%v\" [label=\"%v\\n(%v)\", type=\"%v\", id=%v];\n", document.Hash.String(),
+			quote.Replace(nodeLabel(document)), quote.Replace(nodeType(document)), quote.Replace(nodeType(document)), document.ID)
+	}
+	for _, edge := range subgraph.Edges {
+		fmt.Fprintf(w, "  \"%v\" -> \"%v\" [label=\"%v\"];\n", edge.FromNode.String(), edge.ToNode.String(), quote.Replace(string(edge.EdgeName)))
+	}
+	_, err := fmt.Fprintln(w, "}")
+	return err
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLNode struct {
+	ID   string        `xml:"id,attr"`
+	Data []graphMLData `xml:"data"`
+}
+
+type graphMLEdge struct {
+	ID     string        `xml:"id,attr"`
+	Source string        `xml:"source,attr"`
+	Target string        `xml:"target,attr"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		ID          string        `xml:"id,attr"`
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLNode `xml:"node"`
+		Edges       []graphMLEdge `xml:"edge"`
+	} `xml:"graph"`
+}
+
+func exportGraphML(w io.Writer, subgraph Subgraph) error {
+	doc := graphML{
+		XMLNS: "http://graphml.graphdrawing.org/xmlns",
+		Keys: []graphMLKey{
+			{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+			{ID: "type", For: "node", AttrName: "type", AttrType: "string"},
+			{ID: "docid", For: "node", AttrName: "id", AttrType: "long"},
+			{ID: "creator", For: "node", AttrName: "creator", AttrType: "string"},
+			{ID: "created", For: "node", AttrName: "created", AttrType: "string"},
+			{ID: "name", For: "edge", AttrName: "label", AttrType: "string"},
+			{ID: "edgecreated", For: "edge", AttrName: "created", AttrType: "string"},
+		},
+	}
+	doc.Graph.ID = "dao"
+	doc.Graph.EdgeDefault = "directed"
+
+	for i := range subgraph.Documents {
+		document := &subgraph.Documents[i]
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLNode{
+			ID: document.Hash.String(),
+			Data: []graphMLData{
+				{Key: "label", Value: nodeLabel(document)},
+				{Key: "type", Value: nodeType(document)},
+				{Key: "docid", Value: fmt.Sprint(document.ID)},
+				{Key: "creator", Value: string(document.Creator)},
+				{Key: "created", Value: timestamp(document.CreatedDate)},
+			},
+		})
+	}
+	for _, edge := range subgraph.Edges {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLEdge{
+			ID:     fmt.Sprintf("e%v", edge.ID),
+			Source: edge.FromNode.String(),
+			Target: edge.ToNode.String(),
+			Data: []graphMLData{
+				{Key: "name", Value: string(edge.EdgeName)},
+				{Key: "edgecreated", Value: timestamp(edge.CreatedDate)},
+			},
+		})
+	}
+
+	_, err := io.WriteString(w, xml.Header)
+	if err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	err = encoder.Encode(doc)
+	if err != nil {
+		return fmt.Errorf("cannot encode graphml: %v", err)
+	}
+	_, err = fmt.Fprintln(w)
+	return err
+}
+
+// exportCypher writes MERGE statements so that loading an export twice into Neo4j does not
+// duplicate documents or edges
+func exportCypher(w io.Writer, subgraph Subgraph) error {
+	quote := strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`)
+	identifier := strings.NewReplacer("`", "``")
+
+	for i := range subgraph.Documents {
+		document := &subgraph.Documents[i]
+		labels := ""
+		if docType := nodeType(document); docType != "" {
+			labels = " n:`" + identifier.Replace(docType) + "`,"
+		}
+		fmt.Fprintf(w, "MERGE (n:Document {hash: '%v'}) SET%v n.id = %v, n.label = '%v', n.type = '%v', n.creator = '%v', n.created = datetime('%v');\n",
+			document.Hash.String(), labels, document.ID, quote.Replace(nodeLabel(document)), quote.Replace(nodeType(document)),
+			quote.Replace(string(document.Creator)), timestamp(document.CreatedDate))
+	}
+	for _, edge := range subgraph.Edges {
+		fmt.Fprintf(w, "MATCH (a:Document {hash: '%v'}), (b:Document {hash: '%v'}) MERGE (a)-[r:`%v` {id: %v}]->(b) SET r.created = datetime('%v');\n",
+			edge.FromNode.String(), edge.ToNode.String(), identifier.Replace(string(edge.EdgeName)), edge.ID, timestamp(edge.CreatedDate))
+	}
+	return nil
+}
+
+type jsonGraphNode struct {
+	Label    string                 `json:"label"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+type jsonGraphEdge struct {
+	Source   string                 `json:"source"`
+	Target   string                 `json:"target"`
+	Relation string                 `json:"relation"`
+	Directed bool                   `json:"directed"`
+	Metadata map[string]interface{} `json:"metadata"`
+}
+
+// exportJSONGraph writes the JSON Graph Format, see https://jsongraphformat.info
+func exportJSONGraph(w io.Writer, subgraph Subgraph) error {
+	nodes := make(map[string]jsonGraphNode)
+	for i := range subgraph.Documents {
+		document := &subgraph.Documents[i]
+		nodes[document.Hash.String()] = jsonGraphNode{
+			Label: nodeLabel(document),
+			Metadata: map[string]interface{}{
+				"id":      document.ID,
+				"type":    nodeType(document),
+				"creator": string(document.Creator),
+				"created": timestamp(document.CreatedDate),
+			},
+		}
+	}
+
+	edges := make([]jsonGraphEdge, 0, len(subgraph.Edges))
+	for _, edge := range subgraph.Edges {
+		edges = append(edges, jsonGraphEdge{
+			Source:   edge.FromNode.String(),
+			Target:   edge.ToNode.String(),
+			Relation: string(edge.EdgeName),
+			Directed: true,
+			Metadata: map[string]interface{}{
+				"id":      edge.ID,
+				"created": timestamp(edge.CreatedDate),
+			},
+		})
+	}
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"graph": map[string]interface{}{
+			"id":       "dao",
+			"directed": true,
+			"nodes":    nodes,
+			"edges":    edges,
+		},
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("cannot marshal json graph: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
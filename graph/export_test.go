@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"strings"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// exportLabel needs escaping in every format
+const exportLabel = "say \"hi\" <b> & it's\nline 2"
+
+func exportSubgraph() Subgraph {
+	created := eos.BlockTimestamp{Time: time.Date(2021, 3, 1, 12, 0, 0, 0, time.UTC)}
+	alice := testDocument(2, "dao.hypha", "member", "alice")
+	role := testDocument(4, "alice", "role", exportLabel)
+	alice.CreatedDate, role.CreatedDate = created, created
+	owns := testEdge(13, alice, role, "owns")
+	owns.CreatedDate = created
+	return Subgraph{Documents: []docgraph.Document{alice, role}, Edges: []docgraph.Edge{owns}}
+}
+
+func TestExport(t *testing.T) {
+	subgraph := exportSubgraph()
+	alice, role := subgraph.Documents[0].Hash.String(), subgraph.Documents[1].Hash.String()
+
+	tests := []struct {
+		format string
+		want   []string
+		// parse checks that the output reads back, with the label unescaped
+		parse func(t *testing.T, output []byte)
+	}{
+		{
+			format: "dot",
+			want: []string{
+				"digraph dao {",
+				`  "` + role + `" [label="say \"hi\" <b> & it's\nline 2\n(role)", type="role", id=4];`,
+				`  "` + alice + `" -> "` + role + `" [label="owns"];`,
+			},
+		},
+		{
+			format: "graphml",
+			want: []string{
+				`<node id="` + role + `">`,
+				`<data key="label">say &#34;hi&#34; &lt;b&gt; &amp; it&#39;s&#xA;line 2</data>`,
+				`<edge id="e13" source="` + alice + `" target="` + role + `">`,
+				`<data key="created">2021-03-01T12:00:00</data>`,
+			},
+			parse: func(t *testing.T, output []byte) {
+				var doc graphML
+				if err := xml.Unmarshal(output, &doc); err != nil {
+					t.Fatal(err)
+				}
+				if len(doc.Graph.Nodes) != 2 || doc.Graph.Nodes[1].Data[0].Value != exportLabel {
+					t.Errorf("got nodes %+v", doc.Graph.Nodes)
+				}
+			},
+		},
+		{
+			format: "cypher",
+			want: []string{
+				"MERGE (n:Document {hash: '" + role + "'}) SET n:`role`, n.id = 4, " +
+					`n.label = 'say "hi" <b> & it\'s\nline 2', n.type = 'role', n.creator = 'alice', n.created = datetime('2021-03-01T12:00:00');`,
+				"MATCH (a:Document {hash: '" + alice + "'}), (b:Document {hash: '" + role + "'}) " +
+					"MERGE (a)-[r:`owns` {id: 13}]->(b) SET r.created = datetime('2021-03-01T12:00:00');",
+			},
+			parse: func(t *testing.T, output []byte) {
+				if lines := strings.Split(strings.TrimSpace(string(output)), "\n"); len(lines) != 3 {
+					t.Errorf("got %v statements, want one per line", len(lines))
+				}
+			},
+		},
+		{
+			format: "json-graph",
+			want: []string{
+				`"label": "say \"hi\" \u003cb\u003e \u0026 it's\nline 2"`,
+				`"relation": "owns"`,
+			},
+			parse: func(t *testing.T, output []byte) {
+				var doc struct {
+					Graph struct {
+						Nodes map[string]jsonGraphNode `json:"nodes"`
+						Edges []jsonGraphEdge          `json:"edges"`
+					} `json:"graph"`
+				}
+				if err := json.Unmarshal(output, &doc); err != nil {
+					t.Fatal(err)
+				}
+				if doc.Graph.Nodes[role].Label != exportLabel || len(doc.Graph.Edges) != 1 || doc.Graph.Edges[0].Target != role {
+					t.Errorf("got nodes %+v and edges %+v", doc.Graph.Nodes, doc.Graph.Edges)
+				}
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.format, func(t *testing.T) {
+			var output bytes.Buffer
+			if err := Export(&output, subgraph, test.format); err != nil {
+				t.Fatal(err)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(output.String(), want) {
+					t.Errorf("output does not contain\n%v\ngot\n%v", want, output.String())
+				}
+			}
+			if test.parse != nil {
+				test.parse(t, output.Bytes())
+			}
+		})
+	}
+}
+
+func TestExportUnknownFormat(t *testing.T) {
+	var output bytes.Buffer
+	err := Export(&output, exportSubgraph(), "svg")
+	if err == nil || err.Error() != "unknown export format: svg, expected one of dot, graphml, cypher, json-graph" {
+		t.Errorf("got error %v", err)
+	}
+	if output.Len() != 0 {
+		t.Errorf("wrote %q for an unknown format", output.String())
+	}
+}
@@ -0,0 +1,178 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// FieldValue returns the value of a field of the document: one of the document fields id, hash,
+// creator, type, label or created, a content item addressed as group.label, or a bare content
+// label searched across all content groups. The boolean is false when the document lacks the field.
+func FieldValue(document *docgraph.Document, field string) (interface{}, bool) {
+	switch field {
+	case "id":
+		return int64(document.ID), true
+	case "hash":
+		return document.Hash.String(), true
+	case "creator":
+		return string(document.Creator), true
+	case "type":
+		docType, err := document.GetType()
+		if err != nil || docType == "" {
+			return nil, false
+		}
+		return string(docType), true
+	case "label":
+		label := document.GetNodeLabel()
+		return label, label != ""
+	case "created":
+		return document.CreatedDate.Time, true
+	}
+
+	groupLabel, label := "", field
+	if i := strings.Index(field, "."); i >= 0 {
+		groupLabel, label = field[:i], field[i+1:]
+	}
+
+	value := ContentValue(document, groupLabel, label)
+	if value == nil {
+		return nil, false
+	}
+	return value.Impl, true
+}
+
+// ContentValue returns the content item with the label within the content group with the
+// group label, or within any content group when groupLabel is empty
+func ContentValue(document *docgraph.Document, groupLabel, label string) *docgraph.FlexValue {
+	for _, contentGroup := range document.ContentGroups {
+		if groupLabel != "" && contentGroupLabel(contentGroup) != groupLabel {
+			continue
+		}
+		for _, item := range contentGroup {
+			if item.Label == label {
+				return item.Value
+			}
+		}
+	}
+	return nil
+}
+
+func contentGroupLabel(contentGroup docgraph.ContentGroup) string {
+	for _, item := range contentGroup {
+		if item.Label == "content_group_label" && item.Value != nil {
+			return item.Value.String()
+		}
+	}
+	return ""
+}
+
+// FormatValue renders a field value for tables and CSV
+func FormatValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Time:
+		return v.Format("2006 Jan 02 15:04:05")
+	case eos.TimePoint:
+		return timePointTime(v).Format("2006 Jan 02 15:04:05")
+	case eos.Asset:
+		return v.String()
+	case eos.Name:
+		return string(v)
+	case eos.Checksum256:
+		return v.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+func timePointTime(tp eos.TimePoint) time.Time {
+	return time.Unix(0, int64(tp)*int64(time.Microsecond)).UTC()
+}
+
+// compare compares a field value with the literal from a query, returning -1, 0 or 1, and
+// false when the two cannot be compared
+func compare(value interface{}, literal string) (int, bool) {
+	switch v := value.(type) {
+	case int64:
+		n, err := strconv.ParseFloat(literal, 64)
+		if err != nil {
+			return 0, false
+		}
+		return compareFloats(float64(v), n), true
+	case eos.Asset:
+		n, ok := parseAmount(literal)
+		if !ok {
+			return 0, false
+		}
+		return compareFloats(float64(v.Amount)/math.Pow10(int(v.Precision)), n), true
+	case time.Time:
+		t, ok := ParseTime(literal)
+		if !ok {
+			return 0, false
+		}
+		return compareTimes(v, t), true
+	case eos.TimePoint:
+		t, ok := ParseTime(literal)
+		if !ok {
+			return 0, false
+		}
+		return compareTimes(timePointTime(v), t), true
+	default:
+		return strings.Compare(FormatValue(value), literal), true
+	}
+}
+
+func compareFloats(a, b float64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func compareTimes(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+	return 0
+}
+
+// parseAmount accepts a plain number or an asset string such as "1000.00 HUSD"
+func parseAmount(literal string) (float64, bool) {
+	amount := strings.Fields(literal)
+	if len(amount) == 0 {
+		return 0, false
+	}
+	n, err := strconv.ParseFloat(amount[0], 64)
+	return n, err == nil
+}
+
+var timeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02 15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02",
+}
+
+// ParseTime parses a date or date and time in the layouts accepted by query comparisons
+func ParseTime(literal string) (time.Time, bool) {
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, literal); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"sort"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Memory is a Source over documents and edges held in memory, such as a graph rebuilt as of a
+// past block
+type Memory struct {
+	documents map[string]docgraph.Document
+	edges     []docgraph.Edge
+	from      map[string][]docgraph.Edge
+	to        map[string][]docgraph.Edge
+}
+
+// NewMemory indexes the documents and edges
+func NewMemory(documents []docgraph.Document, edges []docgraph.Edge) *Memory {
+	m := &Memory{
+		documents: make(map[string]docgraph.Document),
+		from:      make(map[string][]docgraph.Edge),
+		to:        make(map[string][]docgraph.Edge),
+	}
+	for _, document := range documents {
+		m.documents[document.Hash.String()] = document
+	}
+
+	m.edges = make([]docgraph.Edge, len(edges))
+	copy(m.edges, edges)
+	sort.Slice(m.edges, func(i, j int) bool { return m.edges[i].ID < m.edges[j].ID })
+	for _, edge := range m.edges {
+		m.from[edge.FromNode.String()] = append(m.from[edge.FromNode.String()], edge)
+		m.to[edge.ToNode.String()] = append(m.to[edge.ToNode.String()], edge)
+	}
+	return m
+}
+
+// GetAllDocuments returns the documents ordered by ID
+func (m *Memory) GetAllDocuments() ([]docgraph.Document, error) {
+	var documents []docgraph.Document
+	for _, document := range m.documents {
+		documents = append(documents, document)
+	}
+	sort.Slice(documents, func(i, j int) bool { return documents[i].ID < documents[j].ID })
+	return documents, nil
+}
+
+// GetAllEdges returns the edges ordered by ID
+func (m *Memory) GetAllEdges() ([]docgraph.Edge, error) {
+	return m.edges, nil
+}
+
+// GetDocument returns the document with the hash, and whether it was found
+func (m *Memory) GetDocument(hash string) (docgraph.Document, bool, error) {
+	document, found := m.documents[hash]
+	return document, found, nil
+}
+
+// GetDocumentsByType returns the documents of the type ordered by ID
+func (m *Memory) GetDocumentsByType(docType eos.Name) ([]docgraph.Document, error) {
+	all, _ := m.GetAllDocuments()
+	var documents []docgraph.Document
+	for _, document := range all {
+		if t, err := document.GetType(); err == nil && t == docType {
+			documents = append(documents, document)
+		}
+	}
+	return documents, nil
+}
+
+// GetEdgesFrom returns the edges from the document, only those with the name unless it is empty
+func (m *Memory) GetEdgesFrom(hash string, edgeName eos.Name) ([]docgraph.Edge, error) {
+	return withName(m.from[hash], edgeName), nil
+}
+
+// GetEdgesTo returns the edges to the document, only those with the name unless it is empty
+func (m *Memory) GetEdgesTo(hash string, edgeName eos.Name) ([]docgraph.Edge, error) {
+	return withName(m.to[hash], edgeName), nil
+}
+
+func withName(edges []docgraph.Edge, edgeName eos.Name) []docgraph.Edge {
+	if edgeName == "" {
+		return edges
+	}
+	var named []docgraph.Edge
+	for _, edge := range edges {
+		if edge.EdgeName == edgeName {
+			named = append(named, edge)
+		}
+	}
+	return named
+}
@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+type tokenKind int
+
+const (
+	wordToken tokenKind = iota
+	stringToken
+	operatorToken
+	arrowToken
+	openToken
+	closeToken
+	endToken
+)
+
+type token struct {
+	kind     tokenKind
+	text     string
+	position int
+}
+
+func (t token) String() string {
+	if t.kind == endToken {
+		return "end of query"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// Parse parses a graph query such as
+//
+//	type=assignment and details.assignee=alice and created>2021-01-01 -> assigned
+//
+// A query is an optional filter followed by edge steps. A step is -> or <- and an edge name,
+// or * for any edge, optionally followed by a filter on the documents it reaches. Filters combine
+// comparisons with and, or, not and parentheses. The comparison operators are = != > >= < <= and
+// ~ for a case insensitive substring match.
+func Parse(query string) (*Query, error) {
+	tokens, err := tokenize(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &parser{tokens: tokens}
+	result := &Query{}
+	if p.peek().kind != arrowToken && p.peek().kind != endToken {
+		if result.Filter, err = p.parseOr(); err != nil {
+			return nil, err
+		}
+	}
+
+	for p.peek().kind == arrowToken {
+		arrow := p.next()
+		step := Step{Direction: Outgoing}
+		if arrow.text == "<-" {
+			step.Direction = Incoming
+		}
+
+		name := p.next()
+		if name.kind != wordToken {
+			return nil, fmt.Errorf("expected an edge name after %v at position %v, found %v", arrow.text, name.position, name)
+		}
+		if name.text != "*" {
+			step.EdgeName = eos.Name(name.text)
+		}
+
+		if p.peek().kind != arrowToken && p.peek().kind != endToken {
+			if step.Filter, err = p.parseOr(); err != nil {
+				return nil, err
+			}
+		}
+		result.Steps = append(result.Steps, step)
+	}
+
+	if end := p.peek(); end.kind != endToken {
+		return nil, fmt.Errorf("unexpected %v at position %v", end, end.position)
+	}
+	return result, nil
+}
+
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != endToken {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) isKeyword(keyword string) bool {
+	t := p.peek()
+	return t.kind == wordToken && strings.EqualFold(t.text, keyword)
+}
+
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("or") {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &orExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.isKeyword("and") {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &andExpr{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.isKeyword("not") {
+		p.next()
+		expr, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &notExpr{expr: expr}, nil
+	}
+
+	if p.peek().kind == openToken {
+		open := p.next()
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != closeToken {
+			return nil, fmt.Errorf("missing ) for ( at position %v, found %v", open.position, closing)
+		}
+		return expr, nil
+	}
+
+	field := p.next()
+	if field.kind != wordToken {
+		return nil, fmt.Errorf("expected a field name at position %v, found %v", field.position, field)
+	}
+	operator := p.next()
+	if operator.kind != operatorToken {
+		return nil, fmt.Errorf("expected a comparison operator after %v at position %v, found %v", field.text, operator.position, operator)
+	}
+	value := p.next()
+	if value.kind != wordToken && value.kind != stringToken {
+		return nil, fmt.Errorf("expected a value after %v%v at position %v, found %v", field.text, operator.text, value.position, value)
+	}
+	return &comparison{field: field.text, operator: operator.text, literal: value.text}, nil
+}
+
+func tokenize(query string) ([]token, error) {
+	var tokens []token
+	runes := []rune(query)
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{kind: openToken, text: "(", position: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{kind: closeToken, text: ")", position: i})
+			i++
+		case r == '-' && i+1 < len(runes) && runes[i+1] == '>',
+			r == '<' && i+1 < len(runes) && runes[i+1] == '-' && !awaitsOperator(tokens):
+			tokens = append(tokens, token{kind: arrowToken, text: string(runes[i : i+2]), position: i})
+			i += 2
+		case r == '!' || r == '=' || r == '<' || r == '>' || r == '~':
+			length := 1
+			if r != '~' && i+1 < len(runes) && runes[i+1] == '=' {
+				length = 2
+			}
+			operator := string(runes[i : i+length])
+			if operator == "!" {
+				return nil, fmt.Errorf("unknown operator ! at position %v, did you mean !=", i)
+			}
+			tokens = append(tokens, token{kind: operatorToken, text: operator, position: i})
+			i += length
+		case r == '"':
+			start := i
+			var value strings.Builder
+			for i++; i < len(runes) && runes[i] != '"'; i++ {
+				if runes[i] == '\\' && i+1 < len(runes) {
+					i++
+				}
+				value.WriteRune(runes[i])
+			}
+			if i >= len(runes) {
+				return nil, fmt.Errorf("unterminated string starting at position %v", start)
+			}
+			tokens = append(tokens, token{kind: stringToken, text: value.String(), position: start})
+			i++
+		default:
+			start := i
+			for i < len(runes) && isWordRune(runes, i) {
+				i++
+			}
+			if i == start {
+				return nil, fmt.Errorf("unexpected character %q at position %v", r, i)
+			}
+			tokens = append(tokens, token{kind: wordToken, text: string(runes[start:i]), position: start})
+		}
+	}
+	return append(tokens, token{kind: endToken, position: len(runes)}), nil
+}
+
+// isWordRune reports whether the rune at i continues a bare word; a dash is part of a word,
+// as in dates, unless it starts an arrow
+func isWordRune(runes []rune, i int) bool {
+	r := runes[i]
+	if r == '-' {
+		return i+1 >= len(runes) || runes[i+1] != '>'
+	}
+	return unicode.IsLetter(r) || unicode.IsDigit(r) || strings.ContainsRune("._:/*+@", r)
+}
+
+// awaitsOperator reports whether the tokens end in a field name that still needs its comparison
+// operator, so that in x<-5 the < compares with -5 instead of starting an incoming step
+func awaitsOperator(tokens []token) bool {
+	n := len(tokens)
+	if n == 0 || tokens[n-1].kind != wordToken {
+		return false
+	}
+	return n == 1 || (tokens[n-2].kind != operatorToken && tokens[n-2].kind != arrowToken)
+}
@@ -0,0 +1,113 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	type step struct {
+		direction Direction
+		edgeName  string
+		filter    string
+	}
+	tests := []struct {
+		query  string
+		filter string
+		steps  []step
+	}{
+		{"", "", nil},
+		{"type=role", `type="role"`, nil},
+		{
+			"type=assignment and details.assignee=alice and created>2021-01-01 -> assigned",
+			`((type="assignment" and details.assignee="alice") and created>"2021-01-01")`,
+			[]step{{Outgoing, "assigned", ""}},
+		},
+		{"type=role or type=badge and label~design", `(type="role" or (type="badge" and label~"design"))`, nil},
+		{"(type=role or type=badge) and label~design", `((type="role" or type="badge") and label~"design")`, nil},
+		{"not (type=role or type=badge)", `not (type="role" or type="badge")`, nil},
+		{"NOT type=role AND label!=x Or id<=3", `((not type="role" and label!="x") or id<="3")`, nil},
+		{"details.time_share_x100>=50", `details.time_share_x100>="50"`, nil},
+		{`label="Designer: erin"`, `label="Designer: erin"`, nil},
+		{`title="say \"hi\""`, `title="say \"hi\""`, nil},
+		{"created<2021-03-01T12:00", `created<"2021-03-01T12:00"`, nil},
+		{"balance<-5", `balance<"-5"`, nil},
+		{"type=role and balance<-5", `(type="role" and balance<"-5")`, nil},
+		{"balance<=-5", `balance<="-5"`, nil},
+		{"type=role<-role", `type="role"`, []step{{Incoming, "role", ""}}},
+		{"<-role<-assigned", "", []step{{Incoming, "role", ""}, {Incoming, "assigned", ""}}},
+		{
+			"<- assigned type=member -> * hash~ab",
+			"",
+			[]step{{Incoming, "assigned", `type="member"`}, {Outgoing, "", `hash~"ab"`}},
+		},
+		{
+			"type=member and label=bob -> assigned -> role",
+			`(type="member" and label="bob")`,
+			[]step{{Outgoing, "assigned", ""}, {Outgoing, "role", ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			query, err := Parse(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			filter := ""
+			if query.Filter != nil {
+				filter = query.Filter.String()
+			}
+			if filter != test.filter {
+				t.Errorf("filter: got %v, want %v", filter, test.filter)
+			}
+
+			if len(query.Steps) != len(test.steps) {
+				t.Fatalf("got %v steps, want %v", len(query.Steps), len(test.steps))
+			}
+			for i, want := range test.steps {
+				got := query.Steps[i]
+				stepFilter := ""
+				if got.Filter != nil {
+					stepFilter = got.Filter.String()
+				}
+				if got.Direction != want.direction || string(got.EdgeName) != want.edgeName || stepFilter != want.filter {
+					t.Errorf("step %v: got %v %q %v, want %v %q %v", i, got.Direction, got.EdgeName, stepFilter,
+						want.direction, want.edgeName, want.filter)
+				}
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		query string
+		err   string
+	}{
+		{"type=", "expected a value after type= at position 5, found end of query"},
+		{"type assignment", `expected a comparison operator after type at position 5, found "assignment"`},
+		{"= role", `expected a field name at position 0, found "="`},
+		{"(type=role", "missing ) for ( at position 0, found end of query"},
+		{"type=role and (label=x or", "expected a field name at position 25, found end of query"},
+		{"type!role", "unknown operator ! at position 4, did you mean !="},
+		{`label="Designer`, "unterminated string starting at position 6"},
+		{"type=role )", `unexpected ")" at position 10`},
+		{"type=role & label=x", "unexpected character '&' at position 10"},
+		{"->", "expected an edge name after -> at position 2, found end of query"},
+		{"type=role <- (", `expected an edge name after <- at position 13, found "("`},
+		{"type=role -> role label", `expected a comparison operator after label at position 23, found end of query`},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			_, err := Parse(test.query)
+			if err == nil {
+				t.Fatalf("expected error %q", test.err)
+			}
+			if err.Error() != test.err {
+				t.Errorf("got error %q, want %q", err.Error(), test.err)
+			}
+		})
+	}
+}
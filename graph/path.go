@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"context"
+
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Hop is one edge of a path, walked forward from its from node to its to node, or backward
+type Hop struct {
+	Edge    docgraph.Edge
+	Forward bool
+}
+
+// From is the hash of the document the hop leaves
+func (h Hop) From() string {
+	if h.Forward {
+		return h.Edge.FromNode.String()
+	}
+	return h.Edge.ToNode.String()
+}
+
+// To is the hash of the document the hop arrives at
+func (h Hop) To() string {
+	if h.Forward {
+		return h.Edge.ToNode.String()
+	}
+	return h.Edge.FromNode.String()
+}
+
+// Path is a sequence of hops where each hop leaves the document the previous one arrived at
+type Path []Hop
+
+// Adjacency indexes a set of edges by the documents at either end
+type Adjacency struct {
+	from map[string][]docgraph.Edge
+	to   map[string][]docgraph.Edge
+}
+
+// NewAdjacency indexes the edges, typically all edges of the DAO
+func NewAdjacency(edges []docgraph.Edge) *Adjacency {
+	a := &Adjacency{
+		from: make(map[string][]docgraph.Edge),
+		to:   make(map[string][]docgraph.Edge),
+	}
+	for _, edge := range edges {
+		a.from[edge.FromNode.String()] = append(a.from[edge.FromNode.String()], edge)
+		a.to[edge.ToNode.String()] = append(a.to[edge.ToNode.String()], edge)
+	}
+	return a
+}
+
+// Hops returns the hops leaving the document, only along outgoing edges when directed is true
+func (a *Adjacency) Hops(hash string, directed bool) []Hop {
+	var hops []Hop
+	for _, edge := range a.from[hash] {
+		hops = append(hops, Hop{Edge: edge, Forward: true})
+	}
+	if !directed {
+		for _, edge := range a.to[hash] {
+			hops = append(hops, Hop{Edge: edge, Forward: false})
+		}
+	}
+	return hops
+}
+
+// ShortestPath finds a path with the fewest hops from one document to another using a breadth
+// first search. The boolean is false when the documents are not connected.
+func (a *Adjacency) ShortestPath(from, to string, directed bool) (Path, bool) {
+	if from == to {
+		return Path{}, true
+	}
+
+	reachedBy := map[string]Hop{}
+	visited := map[string]bool{from: true}
+	queue := []string{from}
+
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+
+		for _, hop := range a.Hops(hash, directed) {
+			next := hop.To()
+			if visited[next] {
+				continue
+			}
+			visited[next] = true
+			reachedBy[next] = hop
+
+			if next == to {
+				var path Path
+				for node := to; node != from; node = reachedBy[node].From() {
+					path = append(Path{reachedBy[node]}, path...)
+				}
+				return path, true
+			}
+			queue = append(queue, next)
+		}
+	}
+	return nil, false
+}
+
+// AllPaths finds the simple paths, which visit no document twice, from one document to another
+// with at most maxDepth hops, stopping after limit paths. Paths are ordered by length. Hops to
+// documents that cannot reach the target in the hops left are pruned. When the context is done
+// the paths found so far are returned with its error.
+func (a *Adjacency) AllPaths(ctx context.Context, from, to string, directed bool, maxDepth, limit int) ([]Path, error) {
+	distances := a.distancesTo(to, directed, maxDepth)
+	if _, reachable := distances[from]; !reachable || from == to {
+		return nil, nil
+	}
+
+	w := walker{
+		adjacency: a,
+		to:        to,
+		directed:  directed,
+		limit:     limit,
+		distances: distances,
+		visited:   map[string]bool{from: true},
+		ctx:       ctx,
+	}
+	// iterative deepening returns shorter paths first and lets the limit cut off the longest ones
+	for depth := distances[from]; depth <= maxDepth && len(w.paths) < limit; depth++ {
+		w.depth = depth
+		if err := w.walk(from, nil); err != nil {
+			return w.paths, err
+		}
+	}
+	return w.paths, nil
+}
+
+// distancesTo returns the fewest hops from each document that can reach the target within
+// maxDepth hops, found by a breadth first search backward from the target
+func (a *Adjacency) distancesTo(to string, directed bool, maxDepth int) map[string]int {
+	distances := map[string]int{to: 0}
+	queue := []string{to}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if distances[hash] >= maxDepth {
+			continue
+		}
+
+		var previous []string
+		for _, edge := range a.to[hash] {
+			previous = append(previous, edge.FromNode.String())
+		}
+		if !directed {
+			for _, edge := range a.from[hash] {
+				previous = append(previous, edge.ToNode.String())
+			}
+		}
+		for _, prev := range previous {
+			if _, seen := distances[prev]; !seen {
+				distances[prev] = distances[hash] + 1
+				queue = append(queue, prev)
+			}
+		}
+	}
+	return distances
+}
+
+type walker struct {
+	adjacency *Adjacency
+	to        string
+	directed  bool
+	depth     int
+	limit     int
+	distances map[string]int
+	visited   map[string]bool
+	paths     []Path
+	ctx       context.Context
+}
+
+// walk extends the path from the document with every hop that can still reach the target within
+// the depth, keeping the paths that arrive at it in exactly depth hops
+func (w *walker) walk(hash string, path Path) error {
+	if err := w.ctx.Err(); err != nil {
+		return err
+	}
+
+	for _, hop := range w.adjacency.Hops(hash, w.directed) {
+		if len(w.paths) >= w.limit {
+			return nil
+		}
+		next := hop.To()
+		distance, reachable := w.distances[next]
+		if w.visited[next] || !reachable || len(path)+1+distance > w.depth {
+			continue
+		}
+
+		if next == w.to {
+			if len(path)+1 == w.depth {
+				found := make(Path, len(path), len(path)+1)
+				copy(found, path)
+				w.paths = append(w.paths, append(found, hop))
+			}
+			continue
+		}
+
+		w.visited[next] = true
+		err := w.walk(next, append(path, hop))
+		w.visited[next] = false
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// pathGraph is a small graph with two routes from a to e, a back edge and a detour:
+//
+//	a -> b -> c -> e,  a -> d -> e,  c -> a,  b -> f -> c
+func pathGraph() (*Adjacency, map[string]string) {
+	names := []string{"a", "b", "c", "d", "e", "f"}
+	documents := make(map[string]docgraph.Document)
+	hashes := make(map[string]string)
+	for i, name := range names {
+		documents[name] = testDocument(uint64(i+1), "dao.hypha", "node", name)
+		hashes[name] = documents[name].Hash.String()
+	}
+
+	var edges []docgraph.Edge
+	for i, pair := range [][2]string{{"a", "b"}, {"b", "c"}, {"c", "e"}, {"a", "d"}, {"d", "e"}, {"c", "a"}, {"b", "f"}, {"f", "c"}} {
+		edges = append(edges, testEdge(uint64(100+i), documents[pair[0]], documents[pair[1]], "next"))
+	}
+	return NewAdjacency(edges), hashes
+}
+
+// pathNames renders a path as the names of the documents it visits
+func pathNames(path Path, from string, names map[string]string) string {
+	rendered := names[from]
+	for _, hop := range path {
+		rendered += names[hop.To()]
+	}
+	return rendered
+}
+
+func TestShortestPath(t *testing.T) {
+	adjacency, hashes := pathGraph()
+	names := make(map[string]string)
+	for name, hash := range hashes {
+		names[hash] = name
+	}
+
+	tests := []struct {
+		from, to string
+		directed bool
+		path     string
+		found    bool
+	}{
+		{"a", "e", true, "ade", true},
+		{"a", "a", true, "a", true},
+		{"b", "a", true, "bca", true},
+		{"e", "a", true, "", false},
+		{"e", "a", false, "eca", true},
+		{"f", "d", true, "fcad", true},
+		{"f", "d", false, "fced", true},
+	}
+	for _, test := range tests {
+		t.Run(test.from+test.to, func(t *testing.T) {
+			path, found := adjacency.ShortestPath(hashes[test.from], hashes[test.to], test.directed)
+			if found != test.found {
+				t.Fatalf("found: got %v, want %v", found, test.found)
+			}
+			if found && pathNames(path, hashes[test.from], names) != test.path {
+				t.Errorf("got path %v, want %v", pathNames(path, hashes[test.from], names), test.path)
+			}
+		})
+	}
+}
+
+func TestAllPaths(t *testing.T) {
+	adjacency, hashes := pathGraph()
+	names := make(map[string]string)
+	for name, hash := range hashes {
+		names[hash] = name
+	}
+
+	tests := []struct {
+		name     string
+		from, to string
+		directed bool
+		maxDepth int
+		limit    int
+		paths    []string
+	}{
+		{"directed, shortest first", "a", "e", true, 4, 10, []string{"ade", "abce", "abfce"}},
+		{"depth cuts long paths", "a", "e", true, 3, 10, []string{"ade", "abce"}},
+		{"too shallow", "a", "e", true, 1, 10, []string{}},
+		{"limit", "a", "e", true, 4, 2, []string{"ade", "abce"}},
+		{"unreachable when directed", "e", "a", true, 4, 10, []string{}},
+		{"undirected", "e", "a", false, 3, 10, []string{"eca", "eda", "ecba"}},
+		{"same document", "a", "a", true, 4, 10, []string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			paths, err := adjacency.AllPaths(context.Background(), hashes[test.from], hashes[test.to], test.directed, test.maxDepth, test.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			rendered := []string{}
+			for _, path := range paths {
+				rendered = append(rendered, pathNames(path, hashes[test.from], names))
+			}
+			if !reflect.DeepEqual(rendered, test.paths) {
+				t.Errorf("got %v, want %v", rendered, test.paths)
+			}
+		})
+	}
+}
+
+func TestAllPathsStopsWhenContextIsDone(t *testing.T) {
+	adjacency, hashes := pathGraph()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Nanosecond)
+	defer cancel()
+	<-ctx.Done()
+
+	paths, err := adjacency.AllPaths(ctx, hashes["a"], hashes["e"], true, 4, 10)
+	if err != context.DeadlineExceeded {
+		t.Errorf("got error %v, want %v", err, context.DeadlineExceeded)
+	}
+	if len(paths) != 0 {
+		t.Errorf("got %v paths after the deadline", len(paths))
+	}
+}
+
+func TestDistancesTo(t *testing.T) {
+	adjacency, hashes := pathGraph()
+	distances := adjacency.distancesTo(hashes["e"], true, 2)
+
+	want := map[string]int{"e": 0, "c": 1, "d": 1, "a": 2, "b": 2, "f": 2}
+	for name, distance := range want {
+		if got, found := distances[hashes[name]]; !found || got != distance {
+			t.Errorf("distance from %v: got %v, %v, want %v", name, got, found, distance)
+		}
+	}
+	if len(distances) != len(want) {
+		t.Errorf("got %v distances, want %v", len(distances), len(want))
+	}
+}
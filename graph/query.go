@@ -0,0 +1,203 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Source is the document graph that queries are evaluated against; util.GraphCache satisfies it
+type Source interface {
+	GetAllDocuments() ([]docgraph.Document, error)
+	GetDocument(hash string) (docgraph.Document, bool, error)
+	GetDocumentsByType(docType eos.Name) ([]docgraph.Document, error)
+	GetEdgesFrom(hash string, edgeName eos.Name) ([]docgraph.Edge, error)
+	GetEdgesTo(hash string, edgeName eos.Name) ([]docgraph.Edge, error)
+}
+
+// Direction of an edge traversal step
+type Direction int
+
+const (
+	// Outgoing follows edges from the current documents to the documents they point to
+	Outgoing Direction = iota
+	// Incoming follows edges that point to the current documents back to where they come from
+	Incoming
+)
+
+// Step follows edges from the current set of documents, optionally only edges with EdgeName,
+// and keeps the documents reached that match Filter
+type Step struct {
+	Direction Direction
+	EdgeName  eos.Name
+	Filter    Expr
+}
+
+// Query is a parsed graph query: a filter over all documents followed by traversal steps
+type Query struct {
+	Filter Expr
+	Steps  []Step
+}
+
+// Expr is a boolean expression over a document
+type Expr interface {
+	Match(document *docgraph.Document) bool
+	String() string
+}
+
+type andExpr struct{ left, right Expr }
+
+func (e *andExpr) Match(d *docgraph.Document) bool { return e.left.Match(d) && e.right.Match(d) }
+func (e *andExpr) String() string                  { return "(" + e.left.String() + " and " + e.right.String() + ")" }
+
+type orExpr struct{ left, right Expr }
+
+func (e *orExpr) Match(d *docgraph.Document) bool { return e.left.Match(d) || e.right.Match(d) }
+func (e *orExpr) String() string                  { return "(" + e.left.String() + " or " + e.right.String() + ")" }
+
+type notExpr struct{ expr Expr }
+
+func (e *notExpr) Match(d *docgraph.Document) bool { return !e.expr.Match(d) }
+func (e *notExpr) String() string                  { return "not " + e.expr.String() }
+
+// comparison compares a document field with a literal. A document that lacks the field, or
+// whose field cannot be compared with the literal, never matches.
+type comparison struct {
+	field    string
+	operator string
+	literal  string
+}
+
+func (c *comparison) Match(d *docgraph.Document) bool {
+	value, found := FieldValue(d, c.field)
+	if !found {
+		return false
+	}
+
+	if c.operator == "~" {
+		return strings.Contains(strings.ToLower(FormatValue(value)), strings.ToLower(c.literal))
+	}
+
+	result, ok := compare(value, c.literal)
+	if !ok {
+		return false
+	}
+	switch c.operator {
+	case "=":
+		return result == 0
+	case "!=":
+		return result != 0
+	case ">":
+		return result > 0
+	case ">=":
+		return result >= 0
+	case "<":
+		return result < 0
+	case "<=":
+		return result <= 0
+	}
+	return false
+}
+
+func (c *comparison) String() string {
+	return c.field + c.operator + fmt.Sprintf("%q", c.literal)
+}
+
+// Run evaluates the query against the source and returns the matching documents ordered by ID
+func (q *Query) Run(source Source) ([]docgraph.Document, error) {
+	var documents []docgraph.Document
+	var err error
+	if docType, ok := indexedType(q.Filter); ok {
+		documents, err = source.GetDocumentsByType(eos.Name(docType))
+	} else {
+		documents, err = source.GetAllDocuments()
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot read documents: %v", err)
+	}
+	documents = filter(documents, q.Filter)
+
+	for _, step := range q.Steps {
+		documents, err = traverse(source, documents, step)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return documents, nil
+}
+
+// indexedType returns the type that every match of the filter must have, if the filter
+// requires one, so that only documents of that type need to be scanned
+func indexedType(expr Expr) (string, bool) {
+	switch e := expr.(type) {
+	case *comparison:
+		if e.field == "type" && e.operator == "=" {
+			return e.literal, true
+		}
+	case *andExpr:
+		if docType, ok := indexedType(e.left); ok {
+			return docType, true
+		}
+		return indexedType(e.right)
+	}
+	return "", false
+}
+
+func filter(documents []docgraph.Document, expr Expr) []docgraph.Document {
+	if expr == nil {
+		return documents
+	}
+	var matches []docgraph.Document
+	for i := range documents {
+		if expr.Match(&documents[i]) {
+			matches = append(matches, documents[i])
+		}
+	}
+	return matches
+}
+
+func traverse(source Source, documents []docgraph.Document, step Step) ([]docgraph.Document, error) {
+	reached := make(map[string]docgraph.Document)
+	for _, document := range documents {
+		var edges []docgraph.Edge
+		var err error
+		if step.Direction == Outgoing {
+			edges, err = source.GetEdgesFrom(document.Hash.String(), step.EdgeName)
+		} else {
+			edges, err = source.GetEdgesTo(document.Hash.String(), step.EdgeName)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("cannot read edges of document: %v %v", document.Hash.String(), err)
+		}
+
+		for _, edge := range edges {
+			hash := edge.ToNode.String()
+			if step.Direction == Incoming {
+				hash = edge.FromNode.String()
+			}
+			if _, seen := reached[hash]; seen {
+				continue
+			}
+
+			neighbour, found, err := source.GetDocument(hash)
+			if err != nil {
+				return nil, err
+			}
+			if found {
+				reached[hash] = neighbour
+			}
+		}
+	}
+
+	var neighbours []docgraph.Document
+	for _, document := range reached {
+		neighbours = append(neighbours, document)
+	}
+	sort.Slice(neighbours, func(i, j int) bool {
+		return neighbours[i].ID < neighbours[j].ID
+	})
+	return filter(neighbours, step.Filter), nil
+}
@@ -0,0 +1,146 @@
+package graph
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+func flexValue(value interface{}) *docgraph.FlexValue {
+	typeName := map[string]string{
+		"eos.Name":      "name",
+		"string":        "string",
+		"eos.Asset":     "asset",
+		"eos.TimePoint": "time_point",
+		"int64":         "int64",
+	}[fmt.Sprintf("%T", value)]
+	return &docgraph.FlexValue{BaseVariant: eos.BaseVariant{
+		TypeID: docgraph.GetVariants().TypeID(typeName),
+		Impl:   value,
+	}}
+}
+
+// testDocument returns a document with the type and label in its system group and the details,
+// given as alternating labels and values, in its details group
+func testDocument(id uint64, creator, docType, label string, details ...interface{}) docgraph.Document {
+	detailsGroup := docgraph.ContentGroup{{Label: "content_group_label", Value: flexValue("details")}}
+	for i := 0; i+1 < len(details); i += 2 {
+		detailsGroup = append(detailsGroup, docgraph.ContentItem{Label: details[i].(string), Value: flexValue(details[i+1])})
+	}
+	systemGroup := docgraph.ContentGroup{
+		{Label: "content_group_label", Value: flexValue("system")},
+		{Label: "type", Value: flexValue(eos.Name(docType))},
+		{Label: "node_label", Value: flexValue(label)},
+	}
+
+	hash := sha256.Sum256([]byte(fmt.Sprint(id, docType, label)))
+	return docgraph.Document{
+		ID:            id,
+		Hash:          eos.Checksum256(hash[:]),
+		Creator:       eos.AccountName(creator),
+		ContentGroups: []docgraph.ContentGroup{detailsGroup, systemGroup},
+	}
+}
+
+func testEdge(id uint64, from, to docgraph.Document, name string) docgraph.Edge {
+	return docgraph.Edge{ID: id, FromNode: from.Hash, ToNode: to.Hash, EdgeName: eos.Name(name)}
+}
+
+func timePoint(date string) eos.TimePoint {
+	t, _ := time.Parse("2006-01-02", date)
+	return eos.TimePoint(t.UnixNano() / 1000)
+}
+
+func asset(value string) eos.Asset {
+	a, _ := eos.NewAssetFromString(value)
+	return a
+}
+
+// testGraph is a DAO with two members, each assigned to one of two roles
+func testGraph() *Memory {
+	dho := testDocument(1, "dao.hypha", "dho", "Hypha DHO")
+	alice := testDocument(2, "dao.hypha", "member", "alice")
+	bob := testDocument(3, "dao.hypha", "member", "bob")
+	designer := testDocument(4, "alice", "role", "Designer",
+		"title", "Designer", "annual_usd_salary", asset("100000.00 USD"), "owner", eos.Name("alice"))
+	developer := testDocument(5, "alice", "role", "Developer",
+		"title", "Developer", "annual_usd_salary", asset("150000.00 USD"), "owner", eos.Name("alice"))
+	aliceDesigner := testDocument(6, "alice", "assignment", "Designer: alice",
+		"assignee", eos.Name("alice"), "time_share_x100", int64(100), "start_time", timePoint("2021-03-01"))
+	bobDeveloper := testDocument(7, "bob", "assignment", "Developer: bob",
+		"assignee", eos.Name("bob"), "time_share_x100", int64(50), "start_time", timePoint("2021-06-01"))
+
+	return NewMemory(
+		[]docgraph.Document{dho, alice, bob, designer, developer, aliceDesigner, bobDeveloper},
+		[]docgraph.Edge{
+			testEdge(11, dho, alice, "member"),
+			testEdge(12, dho, bob, "member"),
+			testEdge(13, alice, aliceDesigner, "assigned"),
+			testEdge(14, bob, bobDeveloper, "assigned"),
+			testEdge(15, aliceDesigner, designer, "role"),
+			testEdge(16, bobDeveloper, developer, "role"),
+		})
+}
+
+func documentIDs(documents []docgraph.Document) []uint64 {
+	ids := []uint64{}
+	for _, document := range documents {
+		ids = append(ids, document.ID)
+	}
+	return ids
+}
+
+func TestQueryRun(t *testing.T) {
+	source := testGraph()
+
+	tests := []struct {
+		query string
+		ids   []uint64
+	}{
+		{"", []uint64{1, 2, 3, 4, 5, 6, 7}},
+		{"type=role", []uint64{4, 5}},
+		{"type=role and details.annual_usd_salary>120000", []uint64{5}},
+		{`details.annual_usd_salary>="100000.00 USD"`, []uint64{4, 5}},
+		{"details.time_share_x100<100", []uint64{7}},
+		{"details.time_share_x100<-5", []uint64{}},
+		{"details.time_share_x100>-5", []uint64{6, 7}},
+		{"start_time>2021-04-01", []uint64{7}},
+		{"details.start_time<=2021-03-01", []uint64{6}},
+		{"label~DESIGN", []uint64{4, 6}},
+		{"creator=bob", []uint64{7}},
+		{"id>=6", []uint64{6, 7}},
+		{"type=member and (label=alice or label=carol)", []uint64{2}},
+		{"not type=member and creator=dao.hypha", []uint64{1}},
+		{"type!=assignment and details.assignee=alice", []uint64{}},
+		{"missing.field=1", []uint64{}},
+		{"details.time_share_x100>lots", []uint64{}},
+		{"type=member and label=alice -> assigned -> role", []uint64{4}},
+		{"type=role <- role type=assignment", []uint64{6, 7}},
+		{"type=role <- role details.assignee=bob", []uint64{7}},
+		{"type=role <- role <- assigned <- member", []uint64{1}},
+		{"type=dho -> member -> *", []uint64{6, 7}},
+		{"-> *", []uint64{2, 3, 4, 5, 6, 7}},
+		{"type=role -> *", []uint64{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.query, func(t *testing.T) {
+			query, err := Parse(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			documents, err := query.Run(source)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ids := documentIDs(documents); !reflect.DeepEqual(ids, test.ids) {
+				t.Errorf("got %v, want %v", ids, test.ids)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package hyperion
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/spf13/viper"
+	"github.com/tidwall/gjson"
+)
+
+// Delta is a change to a contract table row recorded by Hyperion: the row as written by an action,
+// or its removal when Present is false
+type Delta struct {
+	BlockNum   uint32
+	Timestamp  time.Time
+	Present    bool
+	PrimaryKey string
+	Data       json.RawMessage
+}
+
+// DeltaQuery selects the row changes of one contract table
+type DeltaQuery struct {
+	Code  string
+	Scope string
+	Table string
+	Limit int
+}
+
+// NewDeltaQuery returns a query for the changes to a contract table, oldest first
+func NewDeltaQuery(code, scope, table string) DeltaQuery {
+	return DeltaQuery{
+		Code:  code,
+		Scope: scope,
+		Table: table,
+		Limit: 1000,
+	}
+}
+
+// hyperionTime is the layout of the times Hyperion returns and accepts as after and before
+const hyperionTime = "2006-01-02T15:04:05.000"
+
+// Results returns up to Limit deltas from the time after on, oldest first, and when before is not
+// zero only those up to it. A zero after starts at the first delta of the table.
+func (q *DeltaQuery) Results(after, before time.Time) ([]Delta, error) {
+	params := url.Values{}
+	params.Set("code", q.Code)
+	params.Set("scope", q.Scope)
+	params.Set("table", q.Table)
+	params.Set("limit", strconv.Itoa(q.Limit))
+	params.Set("sort", "asc")
+	if !after.IsZero() {
+		params.Set("after", after.UTC().Format(hyperionTime))
+	}
+	if !before.IsZero() {
+		params.Set("before", before.UTC().Format(hyperionTime))
+	}
+	request := viper.GetString("HyperionEndpoint") + "/history/get_deltas?" + params.Encode()
+
+	resp, err := HTTPClient.Get(request)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("hyperion returned %v for %v: %v", resp.Status, request, string(body))
+	}
+
+	var deltas []Delta
+	var parseErr error
+	gjson.GetBytes(body, "deltas").ForEach(func(key, value gjson.Result) bool {
+		timestamp, err := time.Parse(hyperionTime, value.Get("timestamp").String())
+		if err != nil {
+			parseErr = fmt.Errorf("cannot parse delta timestamp: %v", err)
+			return false
+		}
+
+		deltas = append(deltas, Delta{
+			BlockNum:   uint32(value.Get("block_num").Uint()),
+			Timestamp:  timestamp,
+			Present:    value.Get("present").Bool(),
+			PrimaryKey: value.Get("primary_key").String(),
+			Data:       json.RawMessage(value.Get("data").Raw),
+		})
+		return true
+	})
+	return deltas, parseErr
+}
+
+// key identifies a delta among those of its table written at the same time
+func (d *Delta) key() string {
+	return fmt.Sprintf("%v/%v/%v/%s", d.BlockNum, d.PrimaryKey, d.Present, d.Data)
+}
+
+// Until returns all deltas up to and including the block, or the time when block is 0. Each page
+// starts at the time of the last delta of the one before, since Hyperion caps how many deltas can
+// be skipped; the deltas at that time are returned again and dropped. When more than Limit deltas
+// share one time no page can get past them, and an error is returned rather than a partial history.
+func (q *DeltaQuery) Until(block uint32, until time.Time) ([]Delta, error) {
+	return q.pages(time.Time{}, block, until)
+}
+
+// Between returns all deltas from the time after up to and including the time until, paged as
+// Until does
+func (q *DeltaQuery) Between(after, until time.Time) ([]Delta, error) {
+	return q.pages(after, 0, until)
+}
+
+func (q *DeltaQuery) pages(after time.Time, block uint32, until time.Time) ([]Delta, error) {
+	var before time.Time
+	if block == 0 {
+		before = until
+	}
+
+	var deltas []Delta
+	atAfter := make(map[string]bool)
+	for {
+		page, err := q.Results(after, before)
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, delta := range page {
+			if (block > 0 && delta.BlockNum > block) || (block == 0 && delta.Timestamp.After(until)) {
+				return deltas, nil
+			}
+			key := delta.key()
+			if atAfter[key] {
+				continue
+			}
+			if delta.Timestamp.After(after) {
+				after = delta.Timestamp
+				atAfter = make(map[string]bool)
+			}
+			atAfter[key] = true
+			deltas = append(deltas, delta)
+			added++
+		}
+
+		if added == 0 {
+			if len(page) >= q.Limit {
+				return nil, fmt.Errorf("cannot page past the %v or more deltas of %v table %v written at %v",
+					q.Limit, q.Code, q.Table, after.Format(hyperionTime))
+			}
+			return deltas, nil
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package hyperion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+var deltaEpoch = time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+type fakeDelta struct {
+	BlockNum   uint32          `json:"block_num"`
+	Timestamp  string          `json:"timestamp"`
+	Present    bool            `json:"present"`
+	PrimaryKey string          `json:"primary_key"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// serveDeltas serves get_deltas like Hyperion: after and before are inclusive time bounds, and
+// requests that skip more than maxSkip deltas are refused
+func serveDeltas(t *testing.T, deltas []fakeDelta, maxSkip int) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if skip, _ := strconv.Atoi(query.Get("skip")); skip > maxSkip {
+			http.Error(w, fmt.Sprintf(`{"message":"skip above max_skip %v"}`, maxSkip), http.StatusInternalServerError)
+			return
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		page := []fakeDelta{}
+		for _, delta := range deltas {
+			if after := query.Get("after"); after != "" && delta.Timestamp < after {
+				continue
+			}
+			if before := query.Get("before"); before != "" && delta.Timestamp > before {
+				continue
+			}
+			if len(page) < limit {
+				page = append(page, delta)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"deltas": page})
+	}))
+	t.Cleanup(server.Close)
+	viper.Set("HyperionEndpoint", server.URL)
+}
+
+// blockDeltas returns count deltas per block for the blocks, one block every half second
+func blockDeltas(blocks []uint32, count int) []fakeDelta {
+	var deltas []fakeDelta
+	for _, block := range blocks {
+		timestamp := deltaEpoch.Add(time.Duration(block) * 500 * time.Millisecond).Format(hyperionTime)
+		for i := 0; i < count; i++ {
+			deltas = append(deltas, fakeDelta{
+				BlockNum:   block,
+				Timestamp:  timestamp,
+				Present:    true,
+				PrimaryKey: strconv.Itoa(int(block)*100 + i),
+				Data:       json.RawMessage(fmt.Sprintf(`{"id":%v}`, int(block)*100+i)),
+			})
+		}
+	}
+	return deltas
+}
+
+func TestDeltasUntil(t *testing.T) {
+	var blocks []uint32
+	for block := uint32(1); block <= 40; block++ {
+		blocks = append(blocks, block)
+	}
+	// three deltas per block, so that pages of four end part way through a block
+	deltas := blockDeltas(blocks, 3)
+
+	tests := []struct {
+		name  string
+		block uint32
+		until time.Time
+		count int
+	}{
+		{"every delta", 0, deltaEpoch.Add(time.Hour), 120},
+		{"up to a block", 25, time.Time{}, 75},
+		{"up to a time", 0, deltaEpoch.Add(10 * time.Second), 60},
+		{"before the first block", 0, deltaEpoch, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			// the server refuses any skip, so the deltas can only be read by time
+			serveDeltas(t, deltas, 0)
+			query := NewDeltaQuery("dao.hypha", "dao.hypha", "documents")
+			query.Limit = 4
+
+			got, err := query.Until(test.block, test.until)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != test.count {
+				t.Fatalf("got %v deltas, want %v", len(got), test.count)
+			}
+			for i, delta := range got {
+				if want := deltas[i]; delta.PrimaryKey != want.PrimaryKey || delta.BlockNum != want.BlockNum {
+					t.Fatalf("delta %v: got %v in block %v, want %v in block %v", i, delta.PrimaryKey, delta.BlockNum,
+						want.PrimaryKey, want.BlockNum)
+				}
+			}
+		})
+	}
+}
+
+func TestDeltasUntilErrorsWhenAPageCannotAdvance(t *testing.T) {
+	// five deltas in one block cannot be paged past four at a time
+	serveDeltas(t, blockDeltas([]uint32{1, 2, 3}, 5), 0)
+	query := NewDeltaQuery("dao.hypha", "dao.hypha", "edges")
+	query.Limit = 4
+
+	deltas, err := query.Until(0, deltaEpoch.Add(time.Hour))
+	if err == nil {
+		t.Fatalf("expected an error, got %v deltas", len(deltas))
+	}
+}
+
+func TestDeltasBetween(t *testing.T) {
+	var blocks []uint32
+	for block := uint32(1); block <= 40; block++ {
+		blocks = append(blocks, block)
+	}
+	deltas := blockDeltas(blocks, 3)
+	serveDeltas(t, deltas, 0)
+	query := NewDeltaQuery("dao.hypha", "dao.hypha", "documents")
+	query.Limit = 4
+
+	// blocks 10 to 20 are written from 5 to 10 seconds after the epoch
+	got, err := query.Between(deltaEpoch.Add(5*time.Second), deltaEpoch.Add(10*time.Second))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 33 {
+		t.Fatalf("got %v deltas, want 33", len(got))
+	}
+	for i, delta := range got {
+		if want := deltas[27+i]; delta.PrimaryKey != want.PrimaryKey || delta.BlockNum != want.BlockNum {
+			t.Fatalf("delta %v: got %v in block %v, want %v in block %v", i, delta.PrimaryKey, delta.BlockNum,
+				want.PrimaryKey, want.BlockNum)
+		}
+	}
+}
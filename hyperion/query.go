@@ -1,6 +1,7 @@
 package hyperion
 
 import (
+	"fmt"
 	"io/ioutil"
 	"net/http"
 	"strconv"
@@ -11,6 +12,10 @@ import (
 	"github.com/tidwall/gjson"
 )
 
+// HTTPClient is the client used for every Hyperion request; its Transport can be
+// replaced to record or replay traffic
+var HTTPClient = &http.Client{}
+
 // Query represents an object to pass off to Hyperion to generate results
 type Query struct {
 	Action   string
@@ -20,6 +25,8 @@ type Query struct {
 	After    time.Time
 	Before   time.Time
 	Limit    int
+	Skip     int
+	Sort     string
 }
 
 // NewQuery returns a query object that can be updated and then executed
@@ -39,6 +46,9 @@ func (q *Query) Results() ([]models.QrAction, error) {
 		request += "get_transaction?id=" + q.TrxID
 	} else {
 		request += "get_actions?limit=" + strconv.Itoa(q.Limit)
+		if q.Skip > 0 {
+			request += "&skip=" + strconv.Itoa(q.Skip)
+		}
 		if q.Action != "" {
 			request += "&act.name=" + q.Action
 		}
@@ -49,22 +59,29 @@ func (q *Query) Results() ([]models.QrAction, error) {
 			request += "&account=" + q.Account
 		}
 		if !q.After.IsZero() {
-			request += "&after=" + q.After.Format("2006-01-02T15:04:05.000")
+			request += "&after=" + q.After.UTC().Format("2006-01-02T15:04:05.000")
 		}
 		if !q.Before.IsZero() {
-			request += "&before=" + q.After.Format("2006-01-02T15:04:05.000")
+			request += "&before=" + q.Before.UTC().Format("2006-01-02T15:04:05.000")
+		}
+		if q.Sort != "" {
+			request += "&sort=" + q.Sort
 		}
 	}
 
-	resp, err := http.Get(request)
+	resp, err := HTTPClient.Get(request)
 	if err != nil {
 		return nil, err
 	}
+	defer resp.Body.Close()
 
 	body, err := ioutil.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
+	if resp.StatusCode != 200 {
+		return nil, fmt.Errorf("hyperion returned %v for %v: %v", resp.Status, request, string(body))
+	}
 
 	var actions []models.QrAction
 	result := gjson.Get(string(body), "actions")
@@ -77,6 +94,10 @@ func (q *Query) Results() ([]models.QrAction, error) {
 			ActionContract: getString(value, "act.account"),
 			ActionName:     getString(value, "act.name"),
 			Data:           getString(value, "act.data"),
+			RawData:        value.Get("act.data").Raw,
+			BlockNum:       uint32(value.Get("block_num").Uint()),
+			Actor:          value.Get("act.authorization.0.actor").String(),
+			GlobalSequence: value.Get("global_sequence").Uint(),
 		}
 		actions = append(actions, action)
 		return true // keep iterating
@@ -84,6 +105,58 @@ func (q *Query) Results() ([]models.QrAction, error) {
 	return actions, nil
 }
 
+// Since returns every action matching the query from the time After on, oldest first. Each page
+// starts at the time of the last action of the one before, since Hyperion caps how many actions
+// can be skipped; the actions at that time are returned again and dropped. When more than Limit
+// actions share one time no page can get past them, and an error is returned rather than a
+// partial list.
+func (q *Query) Since() ([]models.QrAction, error) {
+	paged := *q
+	paged.Skip = 0
+	paged.Sort = "asc"
+
+	var actions []models.QrAction
+	atAfter := make(map[string]bool)
+	for {
+		page, err := paged.Results()
+		if err != nil {
+			return nil, err
+		}
+
+		added := 0
+		for _, action := range page {
+			key := actionKey(action)
+			if atAfter[key] {
+				continue
+			}
+			if action.Timestamp.After(paged.After) {
+				paged.After = action.Timestamp
+				atAfter = make(map[string]bool)
+			}
+			atAfter[key] = true
+			actions = append(actions, action)
+			added++
+		}
+
+		if added == 0 {
+			if len(page) >= paged.Limit {
+				return nil, fmt.Errorf("cannot page past the %v or more %v actions written at %v",
+					paged.Limit, q.Action, paged.After.Format("2006-01-02T15:04:05.000"))
+			}
+			return actions, nil
+		}
+	}
+}
+
+// actionKey identifies an action among those at the same time: by its global sequence, which is
+// unique on chain, or else by its transaction, name and data
+func actionKey(action models.QrAction) string {
+	if action.GlobalSequence > 0 {
+		return strconv.FormatUint(action.GlobalSequence, 10)
+	}
+	return action.TrxID + "/" + action.ActionName + "/" + action.RawData
+}
+
 func getString(result gjson.Result, element string) string {
 
 	charsToShow := 45
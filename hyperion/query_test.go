@@ -0,0 +1,129 @@
+package hyperion
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+type fakeAction struct {
+	Timestamp      string                 `json:"timestamp"`
+	BlockNum       uint32                 `json:"block_num"`
+	TrxID          string                 `json:"trx_id"`
+	GlobalSequence uint64                 `json:"global_sequence"`
+	Act            map[string]interface{} `json:"act"`
+}
+
+// serveActions serves get_actions like Hyperion: after is an inclusive time bound, the default
+// order is newest first, and any skip is refused as if it were above max_skip
+func serveActions(t *testing.T, actions []fakeAction) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		if query.Get("skip") != "" {
+			http.Error(w, `{"message":"skip above max_skip"}`, http.StatusInternalServerError)
+			return
+		}
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		page := []fakeAction{}
+		for i := range actions {
+			action := actions[i]
+			if query.Get("sort") != "asc" {
+				action = actions[len(actions)-1-i]
+			}
+			if after := query.Get("after"); after != "" && action.Timestamp < after {
+				continue
+			}
+			if len(page) < limit {
+				page = append(page, action)
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"actions": page})
+	}))
+	t.Cleanup(server.Close)
+	viper.Set("HyperionEndpoint", server.URL)
+}
+
+// castVotes returns count castvote actions per block for blocks 1 to blocks, one block every half second
+func castVotes(blocks, count int) []fakeAction {
+	var actions []fakeAction
+	for block := 1; block <= blocks; block++ {
+		timestamp := deltaEpoch.Add(time.Duration(block) * 500 * time.Millisecond).Format(hyperionTime)
+		for i := 0; i < count; i++ {
+			sequence := uint64(block*100 + i)
+			actions = append(actions, fakeAction{
+				Timestamp:      timestamp,
+				BlockNum:       uint32(block),
+				TrxID:          strconv.FormatUint(sequence, 16),
+				GlobalSequence: sequence,
+				Act: map[string]interface{}{
+					"account":       "trailservice",
+					"name":          "castvote",
+					"authorization": []map[string]string{{"actor": "alice"}},
+					"data":          map[string]interface{}{"voter": "alice"},
+				},
+			})
+		}
+	}
+	return actions
+}
+
+func TestQuerySince(t *testing.T) {
+	actions := castVotes(30, 3)
+	serveActions(t, actions)
+
+	tests := []struct {
+		name  string
+		after time.Time
+		first int
+	}{
+		{"from the start", time.Time{}, 0},
+		{"from block 11", deltaEpoch.Add(5500 * time.Millisecond), 30},
+		{"after the last block", deltaEpoch.Add(time.Hour), 90},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			query := NewQuery("castvote", "trailservice", "")
+			query.After = test.after
+			query.Limit = 4
+
+			got, err := query.Since()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(got) != len(actions)-test.first {
+				t.Fatalf("got %v actions, want %v", len(got), len(actions)-test.first)
+			}
+			for i, action := range got {
+				if want := actions[test.first+i]; action.GlobalSequence != want.GlobalSequence {
+					t.Fatalf("action %v: got sequence %v, want %v", i, action.GlobalSequence, want.GlobalSequence)
+				}
+			}
+		})
+	}
+}
+
+func TestQuerySinceErrorsWhenAPageCannotAdvance(t *testing.T) {
+	serveActions(t, castVotes(3, 5))
+	query := NewQuery("castvote", "trailservice", "")
+	query.Limit = 4
+
+	actions, err := query.Since()
+	if err == nil {
+		t.Fatalf("expected an error, got %v actions", len(actions))
+	}
+}
+
+func TestQueryResultsReportsHyperionErrors(t *testing.T) {
+	serveActions(t, castVotes(3, 1))
+	query := NewQuery("castvote", "trailservice", "")
+	query.Skip = 10
+
+	if _, err := query.Results(); err == nil {
+		t.Error("expected the refused skip to be an error")
+	}
+}
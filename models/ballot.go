@@ -3,8 +3,10 @@ package models
 import (
 	"context"
 	"errors"
+	"fmt"
 
 	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/spf13/viper"
 )
 
@@ -38,7 +40,7 @@ type Vote struct {
 }
 
 //NewBallot ...
-func NewBallot(ctx context.Context, api *eos.API, ballotName eos.Name) (*Ballot, error) {
+func NewBallot(ctx context.Context, reader chain.ChainReader, ballotName eos.Name) (*Ballot, error) {
 	var ballot []Ballot
 	var request eos.GetTableRowsRequest
 	request.Code = viper.GetString("TelosDecideContract")
@@ -48,7 +50,7 @@ func NewBallot(ctx context.Context, api *eos.API, ballotName eos.Name) (*Ballot,
 	request.LowerBound = string(ballotName)
 	request.UpperBound = string(ballotName)
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 	if err != nil {
 		return nil, err
 	}
@@ -64,7 +66,7 @@ func NewBallot(ctx context.Context, api *eos.API, ballotName eos.Name) (*Ballot,
 	voteRequest.Table = "votes"
 	voteRequest.Limit = 500
 	voteRequest.JSON = true
-	voteResponse, err := api.GetTableRows(ctx, voteRequest)
+	voteResponse, err := reader.GetTableRows(ctx, voteRequest)
 	if err != nil {
 		return nil, err
 	}
@@ -92,7 +94,7 @@ func NewBallot(ctx context.Context, api *eos.API, ballotName eos.Name) (*Ballot,
 }
 
 // GetHvoiceSupply ...
-func GetHvoiceSupply(ctx context.Context, api *eos.API) (*eos.Asset, error) {
+func GetHvoiceSupply(ctx context.Context, reader chain.ChainReader) (*eos.Asset, error) {
 	type Supply struct {
 		HvoiceSupply eos.Asset `json:"supply"`
 	}
@@ -108,10 +110,13 @@ func GetHvoiceSupply(ctx context.Context, api *eos.API) (*eos.Asset, error) {
 	request.LowerBound = viper.GetString("VoteTokenSymbol")
 	request.UpperBound = viper.GetString("VoteTokenSymbol")
 	request.JSON = true
-	response, err := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 	response.JSONToStructs(&supply)
+	if len(supply) == 0 {
+		return nil, fmt.Errorf("no %v treasury in %v", viper.GetString("VoteTokenSymbol"), viper.GetString("TelosDecideContract"))
+	}
 	return &supply[0].HvoiceSupply, nil
 }
@@ -0,0 +1,118 @@
+package models
+
+import (
+	"fmt"
+	"math"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/spf13/viper"
+)
+
+// defaultPhaseToYearRatio is the share of a year in one period, a lunar phase of about 7.4 days,
+// which the DAO contract uses to convert annual salaries to pay per period
+const defaultPhaseToYearRatio = 0.02026
+
+// defaultHvoicePerUSD is the HVOICE the DAO contract pays per USD of salary
+const defaultHvoicePerUSD = 2
+
+// Compensation is what an assignment pays per period, and over all its periods
+type Compensation struct {
+	TimeShare       float64
+	Deferred        float64
+	Periods         int64
+	USDPerPeriod    float64
+	HusdPerPeriod   eos.Asset
+	HyphaPerPeriod  eos.Asset
+	HvoicePerPeriod eos.Asset
+	SeedsPerPeriod  eos.Asset
+}
+
+// NewCompensation calculates the pay of an assignment to the role for a number of periods. The
+// time share and deferred share of pay are fractions of 1. The USD salary per period is paid in
+// HUSD for the part not deferred and in HYPHA for the deferred part, at the HYPHA price and
+// deferral factor of the settings, and HVOICE is paid on the whole salary. SEEDS is the escrow
+// alternative for the deferred part; it is zero when the settings have no SEEDS price.
+//
+// The share of a year in one period and the HVOICE paid per USD are compiled into the DAO contract
+// rather than kept in its settings; PhaseToYearRatio and HvoicePerUSD in the configuration replace
+// the contract's values for a DAO that changes them.
+func NewCompensation(role Role, settings Settings, timeShare, deferred float64, periods int64) (Compensation, error) {
+	hyphaPrice := assetFloat(settings.HyphaUSDPrice)
+	if hyphaPrice <= 0 {
+		return Compensation{}, fmt.Errorf("settings have no HYPHA price: %v", settings.HyphaUSDPrice.String())
+	}
+	phaseToYearRatio, err := configuredRate("PhaseToYearRatio", defaultPhaseToYearRatio)
+	if err != nil {
+		return Compensation{}, err
+	}
+	hvoicePerUSD, err := configuredRate("HvoicePerUSD", defaultHvoicePerUSD)
+	if err != nil {
+		return Compensation{}, err
+	}
+
+	usd := assetFloat(role.AnnualUSDSalary) * phaseToYearRatio * timeShare
+	c := Compensation{
+		TimeShare:       timeShare,
+		Deferred:        deferred,
+		Periods:         periods,
+		USDPerPeriod:    usd,
+		HusdPerPeriod:   newAsset(usd*(1-deferred), 2, "HUSD"),
+		HyphaPerPeriod:  newAsset(usd*deferred*settings.HyphaDeferralFactor/hyphaPrice, 2, "HYPHA"),
+		HvoicePerPeriod: newAsset(usd*hvoicePerUSD, 2, "HVOICE"),
+		SeedsPerPeriod:  newAsset(0, 4, "SEEDS"),
+	}
+	if seedsPrice := assetFloat(settings.SeedsUSDPrice); seedsPrice > 0 {
+		c.SeedsPerPeriod = newAsset(usd*deferred*settings.SeedsDeferralFactor/seedsPrice, 4, "SEEDS")
+	}
+	return c, nil
+}
+
+// configuredRate returns the rate set under the key in the configuration, or the default rate when
+// the key is not set
+func configuredRate(key string, defaultRate float64) (float64, error) {
+	if !viper.IsSet(key) {
+		return defaultRate, nil
+	}
+	rate := viper.GetFloat64(key)
+	if rate <= 0 {
+		return 0, fmt.Errorf("invalid %v in the configuration: %v, expected a positive number", key, viper.GetString(key))
+	}
+	return rate, nil
+}
+
+// Total returns the amount paid per period over all periods of the assignment
+func (c *Compensation) Total(perPeriod eos.Asset) eos.Asset {
+	return eos.Asset{Amount: perPeriod.Amount * eos.Int64(c.Periods), Symbol: perPeriod.Symbol}
+}
+
+// CheckTerms returns the ways an assignment with the time share and deferred share of pay, as
+// fractions of 1, breaks the role's minimums
+func (r *Role) CheckTerms(timeShare, deferred float64) []string {
+	var problems []string
+	if timeShare <= 0 || timeShare > 1 {
+		problems = append(problems, fmt.Sprintf("time share %v%% is not between 1%% and 100%%", percent(timeShare)))
+	} else if timeShare < r.MinTime {
+		problems = append(problems, fmt.Sprintf("time share %v%% is below the role's minimum of %v%%", percent(timeShare), percent(r.MinTime)))
+	}
+	if deferred < 0 || deferred > 1 {
+		problems = append(problems, fmt.Sprintf("deferred pay %v%% is not between 0%% and 100%%", percent(deferred)))
+	} else if deferred < r.MinDeferred {
+		problems = append(problems, fmt.Sprintf("deferred pay %v%% is below the role's minimum of %v%%", percent(deferred), percent(r.MinDeferred)))
+	}
+	return problems
+}
+
+func percent(fraction float64) float64 {
+	return math.Round(fraction*10000) / 100
+}
+
+func assetFloat(a eos.Asset) float64 {
+	return float64(a.Amount) / math.Pow10(int(a.Symbol.Precision))
+}
+
+func newAsset(amount float64, precision uint8, symbol string) eos.Asset {
+	return eos.Asset{
+		Amount: eos.Int64(math.Round(amount * math.Pow10(int(precision)))),
+		Symbol: eos.Symbol{Precision: precision, Symbol: symbol},
+	}
+}
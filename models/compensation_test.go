@@ -0,0 +1,125 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/spf13/viper"
+)
+
+func mustAsset(t *testing.T, text string) eos.Asset {
+	t.Helper()
+	a, err := eos.NewAssetFromString(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return a
+}
+
+func TestNewCompensation(t *testing.T) {
+	// the contract's rates apply when the configuration does not set them
+	viper.Reset()
+	role := Role{AnnualUSDSalary: mustAsset(t, "150000.00 USD")}
+	settings := Settings{
+		HyphaUSDPrice:       mustAsset(t, "0.50 USD"),
+		SeedsUSDPrice:       mustAsset(t, "0.0200 USD"),
+		HyphaDeferralFactor: 1.5,
+		SeedsDeferralFactor: 1.3,
+	}
+	noSeeds := settings
+	noSeeds.SeedsUSDPrice = eos.Asset{}
+
+	tests := []struct {
+		name                       string
+		settings                   Settings
+		timeShare, deferred        float64
+		periods                    int64
+		husd, hypha, hvoice, seeds string
+		totalHusd                  string
+	}{
+		// 150000 USD a year is 3039 USD a period at full time
+		{"full time, half deferred", settings, 1, 0.5, 12,
+			"1519.50 HUSD", "4558.50 HYPHA", "6078.00 HVOICE", "98767.5000 SEEDS", "18234.00 HUSD"},
+		{"half time, a fifth deferred", settings, 0.5, 0.2, 3,
+			"1215.60 HUSD", "911.70 HYPHA", "3039.00 HVOICE", "19753.5000 SEEDS", "3646.80 HUSD"},
+		{"nothing deferred", settings, 1, 0, 1,
+			"3039.00 HUSD", "0.00 HYPHA", "6078.00 HVOICE", "0.0000 SEEDS", "3039.00 HUSD"},
+		{"no SEEDS price", noSeeds, 1, 1, 2,
+			"0.00 HUSD", "9117.00 HYPHA", "6078.00 HVOICE", "0.0000 SEEDS", "0.00 HUSD"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			c, err := NewCompensation(role, test.settings, test.timeShare, test.deferred, test.periods)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := []string{c.HusdPerPeriod.String(), c.HyphaPerPeriod.String(), c.HvoicePerPeriod.String(),
+				c.SeedsPerPeriod.String(), c.Total(c.HusdPerPeriod).String()}
+			want := []string{test.husd, test.hypha, test.hvoice, test.seeds, test.totalHusd}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+
+	t.Run("configured rates", func(t *testing.T) {
+		viper.Set("PhaseToYearRatio", 0.04)
+		viper.Set("HvoicePerUSD", 3)
+		defer viper.Reset()
+
+		c, err := NewCompensation(role, settings, 1, 0.5, 1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if c.HusdPerPeriod.String() != "3000.00 HUSD" || c.HvoicePerPeriod.String() != "18000.00 HVOICE" {
+			t.Errorf("got %v and %v, want 3000.00 HUSD and 18000.00 HVOICE", c.HusdPerPeriod, c.HvoicePerPeriod)
+		}
+	})
+}
+
+func TestNewCompensationErrors(t *testing.T) {
+	role := Role{AnnualUSDSalary: mustAsset(t, "150000.00 USD")}
+	settings := Settings{HyphaUSDPrice: mustAsset(t, "0.50 USD"), HyphaDeferralFactor: 1.5}
+
+	tests := []struct {
+		name         string
+		settings     Settings
+		ratio, voice float64
+	}{
+		{"no HYPHA price", Settings{}, 0.02026, 2},
+		{"zero phase to year ratio", settings, 0, 2},
+		{"negative HVOICE per USD", settings, 0.02026, -1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			viper.Set("PhaseToYearRatio", test.ratio)
+			viper.Set("HvoicePerUSD", test.voice)
+			defer viper.Reset()
+			if _, err := NewCompensation(role, test.settings, 1, 0.5, 1); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestCheckTerms(t *testing.T) {
+	role := Role{MinTime: 0.5, MinDeferred: 0.2}
+
+	tests := []struct {
+		timeShare, deferred float64
+		problems            []string
+	}{
+		{1, 0.5, nil},
+		{0.5, 0.2, nil},
+		{0.4, 0.2, []string{"time share 40% is below the role's minimum of 50%"}},
+		{0.5, 0.15, []string{"deferred pay 15% is below the role's minimum of 20%"}},
+		{0, -0.1, []string{"time share 0% is not between 1% and 100%", "deferred pay -10% is not between 0% and 100%"}},
+		{1.2, 1.5, []string{"time share 120% is not between 1% and 100%", "deferred pay 150% is not between 0% and 100%"}},
+	}
+	for _, test := range tests {
+		if problems := role.CheckTerms(test.timeShare, test.deferred); !reflect.DeepEqual(problems, test.problems) {
+			t.Errorf("CheckTerms(%v, %v): got %v, want %v", test.timeShare, test.deferred, problems, test.problems)
+		}
+	}
+}
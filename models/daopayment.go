@@ -4,6 +4,7 @@ import (
 	"context"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/spf13/viper"
 )
 
@@ -18,7 +19,7 @@ type DAOPayment struct {
 }
 
 // DAOPayments returns a list of all redemption payments
-func DAOPayments(ctx context.Context, api *eos.API) []Payment {
+func DAOPayments(ctx context.Context, reader chain.ChainReader) []Payment {
 	var payments []Payment
 	var request eos.GetTableRowsRequest
 	request.Code = viper.GetString("DAOContract")
@@ -26,7 +27,7 @@ func DAOPayments(ctx context.Context, api *eos.API) []Payment {
 	request.Table = "payments"
 	request.Limit = 1000 // TODO: support dynamic number of results
 	request.JSON = true
-	response, _ := api.GetTableRows(ctx, request)
+	response, _ := reader.GetTableRows(ctx, request)
 	response.JSONToStructs(&payments)
 
 	return payments
@@ -0,0 +1,223 @@
+package models
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/hypha-dao/daoctl/util"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/viper"
+)
+
+// testdata/dao.hypha.json is hand-built in the format --fixture-record writes: a small DAO with two
+// roles, four assignments, an open proposal and 30 weekly periods. Document hashes are those the
+// contract computes from the content groups. With testdata/daoctl.yaml, which starts the calendar
+// at its first period, get calendar, get roles, get proposal and report participation
+// --telos-decide=false run against it:
+//
+//	daoctl --config models/testdata/daoctl.yaml --fixture models/testdata/dao.hypha.json get calendar
+const fixtureContract = eos.AccountName("dao.hypha")
+
+func loadFixture(t *testing.T) *chain.FixtureReader {
+	t.Helper()
+	reader, err := chain.NewFixtureReader("testdata/dao.hypha.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	viper.Set("DAOContract", string(fixtureContract))
+	viper.Set("TelosDecideContract", "trailservice")
+	viper.Set("VoteTokenSymbol", "HVOICE")
+	viper.Set("RewardToken.Symbol", "HYPHA")
+	viper.Set("RewardTokenContract", "token.hypha")
+	return reader
+}
+
+func fixtureDocument(t *testing.T, reader *chain.FixtureReader, hashPrefix string) docgraph.Document {
+	t.Helper()
+	documents, err := reader.GetAllDocuments(context.Background(), fixtureContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, document := range documents {
+		if hash := document.Hash.String(); len(hash) >= len(hashPrefix) && hash[:len(hashPrefix)] == hashPrefix {
+			return document
+		}
+	}
+	t.Fatalf("document not in fixture: %v", hashPrefix)
+	return docgraph.Document{}
+}
+
+func TestFixtureIsConsistent(t *testing.T) {
+	reader := loadFixture(t)
+	ctx := context.Background()
+	documents, err := reader.GetAllDocuments(ctx, fixtureContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edges, err := reader.GetAllEdges(ctx, fixtureContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hashes := make(map[string]bool)
+	for _, document := range documents {
+		hash := document.Hash.String()
+		if computed := util.HashContentGroups(document.ContentGroups).String(); computed != hash {
+			t.Errorf("document %v: content hashes to %v, not %v", document.ID, computed, hash)
+		}
+		hashes[hash] = true
+	}
+	for _, edge := range edges {
+		if !hashes[edge.FromNode.String()] || !hashes[edge.ToNode.String()] {
+			t.Errorf("%v edge %v links a document not in the fixture", edge.EdgeName, edge.ID)
+		}
+	}
+}
+
+func TestNewRole(t *testing.T) {
+	reader := loadFixture(t)
+
+	tests := []struct {
+		hash             string
+		id               uint64
+		title            string
+		salary           string
+		minTime          float64
+		minDeferred      float64
+		fullTimeCapacity float64
+	}{
+		{"69a6f1bb8f", 38, "Developer", "150000.00 USD", 0.5, 0.2, 3},
+		{"2cea6a122e", 39, "Designer", "100000.00 USD", 0.5, 0.2, 1},
+	}
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			role, err := NewRole(fixtureDocument(t, reader, test.hash))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if role.ID != test.id || role.Title != test.title || role.Owner != "alice" || role.Creator != "alice" {
+				t.Errorf("got role %v %q owned by %v created by %v", role.ID, role.Title, role.Owner, role.Creator)
+			}
+			if role.AnnualUSDSalary.String() != test.salary {
+				t.Errorf("annual salary: got %v, want %v", role.AnnualUSDSalary, test.salary)
+			}
+			if role.MinTime != test.minTime || role.MinDeferred != test.minDeferred || role.FullTimeCapacity != test.fullTimeCapacity {
+				t.Errorf("got min time %v, min deferred %v, capacity %v", role.MinTime, role.MinDeferred, role.FullTimeCapacity)
+			}
+		})
+	}
+}
+
+func TestNewAssignment(t *testing.T) {
+	reader := loadFixture(t)
+
+	tests := []struct {
+		hash        string
+		assignee    eos.Name
+		husd        string
+		hypha       string
+		hvoice      string
+		timeShare   float64
+		deferred    float64
+		periodCount int64
+	}{
+		{"96218188ba", "bob", "1000.00 HUSD", "200.00 HYPHA", "400.00 HVOICE", 1, 0.5, 12},
+		{"c7a1ea869a", "carol", "1000.00 HUSD", "200.00 HYPHA", "400.00 HVOICE", 0.5, 0.5, 12},
+		{"91d78106d8", "dave", "1000.00 HUSD", "200.00 HYPHA", "400.00 HVOICE", 0.8, 0.5, 12},
+		{"e8d9efca59", "erin", "800.00 HUSD", "100.00 HYPHA", "200.00 HVOICE", 0.6, 0.3, 6},
+	}
+	for _, test := range tests {
+		t.Run(string(test.assignee), func(t *testing.T) {
+			assignment, err := NewAssignment(fixtureDocument(t, reader, test.hash))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if assignment.Assigned != test.assignee || assignment.Hash.String()[:10] != test.hash {
+				t.Errorf("got assignee %v of %v", assignment.Assigned, assignment.Hash.String())
+			}
+			if assignment.HusdPerPhase.String() != test.husd || assignment.HyphaPerPhase.String() != test.hypha ||
+				assignment.HvoicePerPhase.String() != test.hvoice {
+				t.Errorf("got salary per phase %v, %v, %v", assignment.HusdPerPhase, assignment.HyphaPerPhase, assignment.HvoicePerPhase)
+			}
+			if assignment.TimeShare != test.timeShare || assignment.DeferredPay != test.deferred || assignment.PeriodCount != test.periodCount {
+				t.Errorf("got time share %v, deferred %v, periods %v", assignment.TimeShare, assignment.DeferredPay, assignment.PeriodCount)
+			}
+		})
+	}
+}
+
+func TestMembers(t *testing.T) {
+	reader := loadFixture(t)
+	members := Members(context.Background(), reader)
+
+	want := []struct {
+		account eos.Name
+		vote    string
+		reward  string
+	}{
+		{"alice", "300.00 HVOICE", "1200.00 HYPHA"},
+		{"bob", "100.00 HVOICE", "450.50 HYPHA"},
+		{"carol", "50.00 HVOICE", "75.25 HYPHA"},
+		{"dave", "25.00 HVOICE", "0.00 HYPHA"},
+		// erin is not registered as a voter and holds no reward token
+		{"erin", "0.00 HVOICE", "0.00 HYPHA"},
+	}
+	if len(members) != len(want) {
+		t.Fatalf("got %v members, want %v", len(members), len(want))
+	}
+	for i, test := range want {
+		member := members[i]
+		if member.Account != test.account || member.VoteTokenBalance.String() != test.vote || member.RewardTokenBalance.String() != test.reward {
+			t.Errorf("member %v: got %v %v %v, want %v %v %v", i, member.Account, member.VoteTokenBalance,
+				member.RewardTokenBalance, test.account, test.vote, test.reward)
+		}
+	}
+}
+
+func TestPeriodCalendar(t *testing.T) {
+	reader := loadFixture(t)
+	start := fixtureDocument(t, reader, "0ad3184d58")
+
+	period, err := NewPeriod(context.Background(), reader, fixtureContract, start)
+	if err != nil {
+		t.Fatal(err)
+	}
+	periods := period.Periods()
+	if len(periods) != 30 {
+		t.Fatalf("got %v periods, want 30", len(periods))
+	}
+	if periods[0].Label != "Week 1" || periods[29].Label != "Week 30" {
+		t.Errorf("got first and last periods %q and %q", periods[0].Label, periods[29].Label)
+	}
+	for i := 1; i < len(periods); i++ {
+		if step := periods[i].StartTime.Sub(periods[i-1].StartTime); step != 7*24*time.Hour {
+			t.Errorf("period %v starts %v after the one before", periods[i].Label, step)
+		}
+	}
+
+	tests := []struct {
+		name  string
+		at    time.Time
+		index int
+		found bool
+	}{
+		{"before the calendar", time.Date(2026, 10, 1, 23, 59, 59, 0, time.UTC), 0, false},
+		{"first instant", time.Date(2026, 10, 2, 0, 0, 0, 0, time.UTC), 0, true},
+		{"within week 2", time.Date(2026, 10, 10, 12, 0, 0, 0, time.UTC), 1, true},
+		{"start of week 3", time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC), 2, true},
+		{"within week 29", time.Date(2027, 4, 20, 0, 0, 0, 0, time.UTC), 28, true},
+		{"start of the last period", time.Date(2027, 4, 23, 0, 0, 0, 0, time.UTC), 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			index, found := PeriodAt(periods, test.at)
+			if index != test.index || found != test.found {
+				t.Errorf("got %v, %v, want %v, %v", index, found, test.index, test.found)
+			}
+		})
+	}
+}
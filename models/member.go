@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/daoctl/util"
 	"github.com/ryanuber/columnize"
 	"github.com/spf13/viper"
@@ -34,11 +35,11 @@ func (m *Member) String() string {
 }
 
 // NewMember converts a generic DAO Object to a typed Payout
-func NewMember(ctx context.Context, api *eos.API, acct eos.Name) Member {
+func NewMember(ctx context.Context, reader chain.ChainReader, acct eos.Name) Member {
 	var m Member
 	var err1 error
 	m.Account = acct
-	rewardTokenBalance, _ := api.GetCurrencyBalance(ctx, eos.AN(string(acct)), viper.GetString("RewardToken.Symbol"), eos.AN(viper.GetString("RewardTokenContract")))
+	rewardTokenBalance, _ := reader.GetCurrencyBalance(ctx, eos.AN(string(acct)), viper.GetString("RewardToken.Symbol"), eos.AN(viper.GetString("RewardTokenContract")))
 	if len(rewardTokenBalance) == 0 {
 		//fmt.Println("Reward token not found, using 0.00 " + viper.GetString("RewardToken.Symbol"))
 		m.RewardTokenBalance, err1 = eos.NewAssetFromString("0.00 " + viper.GetString("RewardToken.Symbol")) // could fail
@@ -56,11 +57,20 @@ func NewMember(ctx context.Context, api *eos.API, acct eos.Name) Member {
 	request.Scope = string(acct)
 	request.Table = "voters"
 	request.Limit = 1
+	request.LowerBound = viper.GetString("VoteTokenSymbol")
+	request.UpperBound = viper.GetString("VoteTokenSymbol")
 	request.JSON = true
-	response, _ := api.GetTableRows(ctx, request)
-	response.JSONToStructs(&tdb)
+	response, err := reader.GetTableRows(ctx, request)
+	if err == nil {
+		response.JSONToStructs(&tdb)
+	}
 
-	m.VoteTokenBalance = tdb[0].Liquid // TODO: support users that a members of multiple DAOs
+	if len(tdb) == 0 {
+		// not registered as a voter, so the member holds no vote token
+		m.VoteTokenBalance = eos.Asset{Symbol: eos.Symbol{Precision: 2, Symbol: viper.GetString("VoteTokenSymbol")}}
+		return m
+	}
+	m.VoteTokenBalance = tdb[0].Liquid
 	return m
 }
 
@@ -70,7 +80,7 @@ type MemberRecord struct {
 }
 
 // Members retrieves a list of all of the DAO members, including balances
-func Members(ctx context.Context, api *eos.API) []Member {
+func Members(ctx context.Context, reader chain.ChainReader) []Member {
 	var memberRecords []MemberRecord
 	// var memberAccounts []eos.Name
 	var request eos.GetTableRowsRequest
@@ -79,13 +89,16 @@ func Members(ctx context.Context, api *eos.API) []Member {
 	request.Table = "members"
 	request.Limit = 1000 // TODO: support dynamic number of members
 	request.JSON = true
-	response, _ := api.GetTableRows(ctx, request)
+	response, err := reader.GetTableRows(ctx, request)
+	if err != nil {
+		return nil
+	}
 	response.JSONToStructs(&memberRecords)
 
 	var members []Member
 	members = make([]Member, len(memberRecords))
 	for index, memberRecord := range memberRecords {
-		members[index] = NewMember(ctx, api, memberRecord.MemberName)
+		members[index] = NewMember(ctx, reader, memberRecord.MemberName)
 	}
 
 	return members
@@ -97,7 +110,7 @@ type ApplicantRecord struct {
 }
 
 // Applicants retrieves a list of all of the DAO members, including balances
-func Applicants(ctx context.Context, api *eos.API) []ApplicantRecord {
+func Applicants(ctx context.Context, reader chain.ChainReader) []ApplicantRecord {
 	var applicantRecords []ApplicantRecord
 	// var memberAccounts []eos.Name
 	var request eos.GetTableRowsRequest
@@ -106,7 +119,7 @@ func Applicants(ctx context.Context, api *eos.API) []ApplicantRecord {
 	request.Table = "applicants"
 	request.Limit = 1000 // TODO: support dynamic number of members
 	request.JSON = true
-	response, _ := api.GetTableRows(ctx, request)
+	response, _ := reader.GetTableRows(ctx, request)
 	response.JSONToStructs(&applicantRecords)
 	return applicantRecords
 }
@@ -0,0 +1,64 @@
+package models
+
+import (
+	"sort"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+// Participation is a member's voting over a window of periods: the proposals voted on and the
+// share of the members' vote token the member holds
+type Participation struct {
+	Account          eos.Name
+	VoteTokenBalance eos.Asset
+	Votes            int
+	Proposals        int
+	PowerShare       float64
+}
+
+// Rate returns the share of the proposals in the window the member voted on
+func (p *Participation) Rate() float64 {
+	if p.Proposals == 0 {
+		return 0
+	}
+	return float64(p.Votes) / float64(p.Proposals)
+}
+
+// Gini returns the Gini coefficient of the values: 0 when they are equal, approaching 1 as one
+// value holds the whole total
+func Gini(values []float64) float64 {
+	sorted := append([]float64{}, values...)
+	sort.Float64s(sorted)
+
+	var total, weighted float64
+	for i, value := range sorted {
+		total += value
+		weighted += float64(i+1) * value
+	}
+	if total == 0 {
+		return 0
+	}
+	n := float64(len(sorted))
+	return 2*weighted/(n*total) - (n+1)/n
+}
+
+// Nakamoto returns the smallest number of the values whose sum is more than half of the total, or
+// zero when the total is zero
+func Nakamoto(values []float64) int {
+	sorted := append([]float64{}, values...)
+	sort.Sort(sort.Reverse(sort.Float64Slice(sorted)))
+
+	var total float64
+	for _, value := range sorted {
+		total += value
+	}
+
+	var sum float64
+	for i, value := range sorted {
+		sum += value
+		if total > 0 && sum > total/2 {
+			return i + 1
+		}
+	}
+	return 0
+}
@@ -0,0 +1,51 @@
+package models
+
+import (
+	"math"
+	"testing"
+)
+
+func TestGini(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		gini   float64
+	}{
+		{"no values", nil, 0},
+		{"zero total", []float64{0, 0, 0}, 0},
+		{"equal values", []float64{5, 5, 5, 5}, 0},
+		{"a single holder", []float64{10}, 0},
+		{"one of four holds everything", []float64{0, 10, 0, 0}, 0.75},
+		{"spread", []float64{4, 1, 3, 2}, 0.25},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if gini := Gini(test.values); math.Abs(gini-test.gini) > 1e-9 {
+				t.Errorf("got %v, want %v", gini, test.gini)
+			}
+		})
+	}
+}
+
+func TestNakamoto(t *testing.T) {
+	tests := []struct {
+		name     string
+		values   []float64
+		nakamoto int
+	}{
+		{"no values", nil, 0},
+		{"zero total", []float64{0, 0}, 0},
+		{"equal values", []float64{5, 5, 5, 5}, 3},
+		{"a single holder", []float64{10}, 1},
+		{"a majority holder", []float64{40, 60}, 1},
+		{"exactly half is not a majority", []float64{50, 50}, 2},
+		{"spread", []float64{1, 2, 3, 4}, 2},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if nakamoto := Nakamoto(test.values); nakamoto != test.nakamoto {
+				t.Errorf("got %v, want %v", nakamoto, test.nakamoto)
+			}
+		})
+	}
+}
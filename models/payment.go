@@ -4,6 +4,7 @@ import (
 	"context"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 )
 
 // Attestation that a particular payment is valid and true
@@ -28,7 +29,7 @@ type Payment struct {
 }
 
 // LoadPaymentByID returns a request for the provided redemption ID
-func LoadPaymentByID(ctx context.Context, api *eos.API, ID uint64) (Payment, error) {
+func LoadPaymentByID(ctx context.Context, reader chain.ChainReader, ID uint64) (Payment, error) {
 	// var payments []Payment
 	return Payment{}, nil
 	// var err error
@@ -41,12 +42,12 @@ func LoadPaymentByID(ctx context.Context, api *eos.API, ID uint64) (Payment, err
 	// request.LowerBound = strconv.Itoa(int(ID))
 	// request.UpperBound = strconv.Itoa(int(ID))
 	// request.JSON = true
-	// response, _ := api.GetTableRows(ctx, request)
+	// response, _ := reader.GetTableRows(ctx, request)
 	// response.JSONToStructs(&payments)
 
 	// if len(payments) >= 1 {
 	// 	payments[0].NotesMap = ToMap(payments[0].NotesRaw)
-	// 	payments[0].Request, err = LoadRequestByID(ctx, api, payments[0].RequestID)
+	// 	payments[0].Request, err = LoadRequestByID(ctx, reader, payments[0].RequestID)
 	// 	if err != nil {
 	// 		fmt.Println("Warning: this payment's corresponding request is not found - this should not happen")
 	// 	}
@@ -57,7 +58,7 @@ func LoadPaymentByID(ctx context.Context, api *eos.API, ID uint64) (Payment, err
 }
 
 // Payments returns a list of all redemption payments
-func Payments(ctx context.Context, api *eos.API) []Payment {
+func Payments(ctx context.Context, reader chain.ChainReader) []Payment {
 	var payments []Payment
 	return payments
 	// var request eos.GetTableRowsRequest
@@ -66,7 +67,7 @@ func Payments(ctx context.Context, api *eos.API) []Payment {
 	// request.Table = "payments"
 	// request.Limit = 1000 // TODO: support dynamic number of results
 	// request.JSON = true
-	// response, _ := api.GetTableRows(ctx, request)
+	// response, _ := reader.GetTableRows(ctx, request)
 	// response.JSONToStructs(&payments)
 
 	// for index, p := range payments {
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/document-graph/docgraph"
 )
 
@@ -70,8 +71,8 @@ func NewPayout(d docgraph.Document, periods []Period) Payout {
 }
 
 // ProposedPayouts provides the active payout proposals
-//func ProposedPayouts(ctx context.Context, api *eos.API, periods []Period) []Payout {
-//	objects := LoadObjects(ctx, api, "proposal")
+//func ProposedPayouts(ctx context.Context, reader chain.ChainReader, periods []Period) []Payout {
+//	objects := LoadObjects(ctx, reader, "proposal")
 //	var propPayouts []Payout
 //	for index := range objects {
 //		daoObject := ToDAOObject(objects[index])
@@ -85,9 +86,9 @@ func NewPayout(d docgraph.Document, periods []Period) Payout {
 //}
 
 // Payouts provides the set of active approved payouts
-func Payouts(ctx context.Context, api *eos.API, periods []Period, scope string) []Payout {
+func Payouts(ctx context.Context, reader chain.ChainReader, periods []Period, scope string) []Payout {
 	return []Payout{}
-	// objects := LoadObjects(ctx, api, scope)
+	// objects := LoadObjects(ctx, reader, scope)
 	// var payouts []Payout
 	// for index := range objects {
 	// 	daoObject := ToDocument(objects[index])
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/document-graph/docgraph"
 )
 
@@ -18,7 +19,7 @@ type Period struct {
 	Document       docgraph.Document
 }
 
-func NewPeriod(ctx context.Context, api *eos.API, contract eos.AccountName, doc docgraph.Document) (Period, error) {
+func NewPeriod(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, doc docgraph.Document) (Period, error) {
 	p := Period{}
 	p.Document = doc
 
@@ -39,7 +40,7 @@ func NewPeriod(ctx context.Context, api *eos.API, contract eos.AccountName, doc
 	}
 	p.Label = label.String()
 
-	nextEdges, err := docgraph.GetEdgesFromDocumentWithEdge(ctx, api, contract, doc, eos.Name("next"))
+	nextEdges, err := chain.GetEdgesFromDocumentWithEdge(ctx, reader, contract, doc, eos.Name("next"))
 	if err != nil {
 		return Period{}, fmt.Errorf("error while retrieving next edge: %v", err)
 	}
@@ -50,11 +51,11 @@ func NewPeriod(ctx context.Context, api *eos.API, contract eos.AccountName, doc
 	} else {
 		// zlog.Debugf("Loading the next period as: %v", nextEdges[0].ToNode.String())
 
-		nextDocument, err := docgraph.LoadDocument(ctx, api, contract, nextEdges[0].ToNode.String())
+		nextDocument, err := reader.LoadDocument(ctx, contract, nextEdges[0].ToNode.String())
 		if err != nil {
 			return Period{}, fmt.Errorf("unable to load next edge: %v", err)
 		}
-		nextPeriod, err := NewPeriod(ctx, api, contract, nextDocument)
+		nextPeriod, err := NewPeriod(ctx, reader, contract, nextDocument)
 		if err != nil {
 			return Period{}, fmt.Errorf("unable to create next Period: %v", err)
 		}
@@ -63,7 +64,7 @@ func NewPeriod(ctx context.Context, api *eos.API, contract eos.AccountName, doc
 	return p, nil
 }
 
-func NewSinglePeriod(ctx context.Context, api *eos.API, contract eos.AccountName, doc docgraph.Document) (Period, error) {
+func NewSinglePeriod(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, doc docgraph.Document) (Period, error) {
 	p := Period{}
 	p.Document = doc
 
@@ -86,3 +87,24 @@ func NewSinglePeriod(ctx context.Context, api *eos.API, contract eos.AccountName
 
 	return p, nil
 }
+
+// Periods returns the period and the periods that follow it, in calendar order
+func (p Period) Periods() []Period {
+	periods := []Period{p}
+	for p.Next != nil {
+		p = *p.Next
+		periods = append(periods, p)
+	}
+	return periods
+}
+
+// PeriodAt returns the index of the period that contains the time. The last period of a calendar
+// only marks its end, so it contains no time.
+func PeriodAt(periods []Period, t time.Time) (int, bool) {
+	for i := 0; i < len(periods)-1; i++ {
+		if !t.Before(periods[i].StartTime) && t.Before(periods[i+1].StartTime) {
+			return i, true
+		}
+	}
+	return 0, false
+}
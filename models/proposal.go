@@ -0,0 +1,182 @@
+package models
+
+import (
+	"math"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// The options of a vote on a proposal
+const (
+	VotePass    = "pass"
+	VoteFail    = "fail"
+	VoteAbstain = "abstain"
+)
+
+// VoteTally is the vote power cast for each option on a proposal, held in the vote.tally
+// document the proposal links to
+type VoteTally struct {
+	Hash    eos.Checksum256
+	Pass    eos.Asset `content:"pass.vote_power"`
+	Fail    eos.Asset `content:"fail.vote_power"`
+	Abstain eos.Asset `content:"abstain.vote_power"`
+}
+
+// NewVoteTally converts a vote.tally document to a VoteTally
+func NewVoteTally(doc docgraph.Document) (VoteTally, error) {
+	t := VoteTally{Hash: doc.Hash}
+	err := Decode(doc, &t)
+	if err != nil {
+		return VoteTally{}, err
+	}
+	return t, nil
+}
+
+// ProposalVote is a member's vote on a proposal, held in a vote document the proposal links to
+type ProposalVote struct {
+	Hash      eos.Checksum256
+	Voter     eos.Name  `content:"vote.voter"`
+	VotePower eos.Asset `content:"vote.vote_power"`
+	Vote      string    `content:"vote.vote"`
+	Date      time.Time `content:"vote.date"`
+}
+
+// NewProposalVote converts a vote document to a ProposalVote
+func NewProposalVote(doc docgraph.Document) (ProposalVote, error) {
+	v := ProposalVote{Hash: doc.Hash}
+	err := Decode(doc, &v)
+	if err != nil {
+		return ProposalVote{}, err
+	}
+	return v, nil
+}
+
+// ProposalBallot is the vote on a proposal in the document graph: the tally and votes cast, the
+// voting period, and the quorum and alignment the DAO settings require to pass
+type ProposalBallot struct {
+	Proposal  docgraph.Document
+	Type      eos.Name
+	Title     string
+	Status    string
+	Tally     VoteTally
+	Votes     []ProposalVote
+	Start     time.Time
+	End       time.Time
+	Supply    eos.Asset
+	Quorum    float64
+	Alignment float64
+}
+
+// Cast returns the vote power cast for any option
+func (b *ProposalBallot) Cast() eos.Asset {
+	return eos.Asset{Amount: b.Tally.Pass.Amount + b.Tally.Fail.Amount + b.Tally.Abstain.Amount, Symbol: b.Tally.Pass.Symbol}
+}
+
+// QuorumVotes returns the vote power that must be cast for the ballot to count
+func (b *ProposalBallot) QuorumVotes() eos.Asset {
+	return eos.Asset{Amount: eos.Int64(math.Ceil(float64(b.Supply.Amount) * b.Quorum)), Symbol: b.Supply.Symbol}
+}
+
+// QuorumMet reports whether enough vote power was cast
+func (b *ProposalBallot) QuorumMet() bool {
+	return b.Cast().Amount >= b.QuorumVotes().Amount
+}
+
+// PassShare returns the share of the pass and fail votes that are pass votes
+func (b *ProposalBallot) PassShare() float64 {
+	decided := b.Tally.Pass.Amount + b.Tally.Fail.Amount
+	if decided == 0 {
+		return 0
+	}
+	return float64(b.Tally.Pass.Amount) / float64(decided)
+}
+
+// Aligned reports whether the pass votes reach the required alignment
+func (b *ProposalBallot) Aligned() bool {
+	decided := b.Tally.Pass.Amount + b.Tally.Fail.Amount
+	return decided > 0 && float64(b.Tally.Pass.Amount) >= float64(decided)*b.Alignment
+}
+
+// Passing reports whether the proposal would pass if the ballot closed now
+func (b *ProposalBallot) Passing() bool {
+	return b.QuorumMet() && b.Aligned()
+}
+
+// Remaining returns the voting time left at the time, zero once voting has ended
+func (b *ProposalBallot) Remaining(t time.Time) time.Duration {
+	if !b.End.After(t) {
+		return 0
+	}
+	return b.End.Sub(t)
+}
+
+// WithVotes returns a copy of the ballot with the vote power added to the option's tally
+func (b ProposalBallot) WithVotes(option string, power eos.Int64) *ProposalBallot {
+	switch option {
+	case VotePass:
+		b.Tally.Pass.Amount += power
+	case VoteFail:
+		b.Tally.Fail.Amount += power
+	case VoteAbstain:
+		b.Tally.Abstain.Amount += power
+	}
+	return &b
+}
+
+// PassVotesNeeded returns the least pass vote power that would make the ballot pass, zero when it
+// is passing, and false when no amount would
+func (b *ProposalBallot) PassVotesNeeded() (eos.Int64, bool) {
+	if b.Passing() {
+		return 0, true
+	}
+	pass, fail := float64(b.Tally.Pass.Amount), float64(b.Tally.Fail.Amount)
+	if b.Alignment > 1 || (b.Alignment == 1 && fail > 0) {
+		return 0, false
+	}
+
+	needed := float64(b.QuorumVotes().Amount - b.Cast().Amount)
+	if b.Alignment < 1 {
+		needed = math.Max(needed, (b.Alignment*(pass+fail)-pass)/(1-b.Alignment))
+	}
+	power := eos.Int64(math.Max(1, math.Ceil(needed)))
+	// absorb rounding in the float thresholds
+	for power > 1 && b.WithVotes(VotePass, power-1).Passing() {
+		power--
+	}
+	for !b.WithVotes(VotePass, power).Passing() {
+		power++
+	}
+	return power, true
+}
+
+// FailVotesNeeded returns the least fail vote power that would stop a passing ballot from passing,
+// zero when it is not passing, and false when no amount would
+func (b *ProposalBallot) FailVotesNeeded() (eos.Int64, bool) {
+	if !b.Passing() {
+		return 0, true
+	}
+	if b.Alignment <= 0 {
+		return 0, false
+	}
+	pass, fail := float64(b.Tally.Pass.Amount), float64(b.Tally.Fail.Amount)
+	power := eos.Int64(math.Max(1, math.Floor(pass/b.Alignment-pass-fail)))
+	// absorb rounding in the float thresholds
+	for power > 1 && !b.WithVotes(VoteFail, power-1).Passing() {
+		power--
+	}
+	for b.WithVotes(VoteFail, power).Passing() {
+		power++
+	}
+	return power, true
+}
+
+// SwingVoter is a member who has not voted on a proposal, with whether the member's vote could
+// change its outcome alone or as one of the fewest members that could change it together
+type SwingVoter struct {
+	Account   eos.Name
+	VotePower eos.Asset
+	Alone     bool
+	Coalition bool
+}
@@ -0,0 +1,83 @@
+package models
+
+import (
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+)
+
+// testBallot returns a ballot on a supply of 1000.00 HVOICE with the tally given in the smallest
+// unit, 0.01 HVOICE
+func testBallot(pass, fail, abstain eos.Int64, quorum, alignment float64) *ProposalBallot {
+	hvoice := eos.Symbol{Precision: 2, Symbol: "HVOICE"}
+	return &ProposalBallot{
+		Tally: VoteTally{
+			Pass:    eos.Asset{Amount: pass, Symbol: hvoice},
+			Fail:    eos.Asset{Amount: fail, Symbol: hvoice},
+			Abstain: eos.Asset{Amount: abstain, Symbol: hvoice},
+		},
+		Supply:    eos.Asset{Amount: 100000, Symbol: hvoice},
+		Quorum:    quorum,
+		Alignment: alignment,
+	}
+}
+
+func TestPassVotesNeeded(t *testing.T) {
+	tests := []struct {
+		name     string
+		ballot   *ProposalBallot
+		needed   eos.Int64
+		possible bool
+	}{
+		{"passing", testBallot(30000, 5000, 0, 0.2, 0.8), 0, true},
+		{"quorum only", testBallot(10000, 0, 0, 0.2, 0.8), 10000, true},
+		{"abstain counts towards quorum", testBallot(10000, 0, 5000, 0.2, 0.8), 5000, true},
+		{"alignment only", testBallot(30000, 10000, 0, 0.2, 0.8), 10000, true},
+		{"quorum and alignment", testBallot(1000, 4000, 0, 0.2, 0.8), 15000, true},
+		{"alignment 0, nothing cast", testBallot(0, 0, 0, 0.2, 0), 20000, true},
+		{"alignment 1 without fail votes", testBallot(5000, 0, 0, 0.2, 1), 15000, true},
+		{"alignment 1 with a fail vote", testBallot(30000, 1, 0, 0.2, 1), 0, false},
+		{"alignment above 1", testBallot(0, 0, 0, 0.2, 1.5), 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			needed, possible := test.ballot.PassVotesNeeded()
+			if needed != test.needed || possible != test.possible {
+				t.Fatalf("got %v, %v, want %v, %v", needed, possible, test.needed, test.possible)
+			}
+			if possible && needed > 0 {
+				if !test.ballot.WithVotes(VotePass, needed).Passing() || test.ballot.WithVotes(VotePass, needed-1).Passing() {
+					t.Errorf("%v pass votes is not the least that passes the ballot", needed)
+				}
+			}
+		})
+	}
+}
+
+func TestFailVotesNeeded(t *testing.T) {
+	tests := []struct {
+		name     string
+		ballot   *ProposalBallot
+		needed   eos.Int64
+		possible bool
+	}{
+		{"not passing", testBallot(10000, 0, 0, 0.2, 0.8), 0, true},
+		{"passing", testBallot(30000, 5000, 0, 0.2, 0.8), 2501, true},
+		{"passing without fail votes", testBallot(40000, 0, 0, 0.2, 0.5), 40001, true},
+		{"alignment 1", testBallot(30000, 0, 0, 0.2, 1), 1, true},
+		{"alignment 0", testBallot(30000, 0, 0, 0.2, 0), 0, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			needed, possible := test.ballot.FailVotesNeeded()
+			if needed != test.needed || possible != test.possible {
+				t.Fatalf("got %v, %v, want %v, %v", needed, possible, test.needed, test.possible)
+			}
+			if possible && needed > 0 {
+				if test.ballot.WithVotes(VoteFail, needed).Passing() || !test.ballot.WithVotes(VoteFail, needed-1).Passing() {
+					t.Errorf("%v fail votes is not the least that stops the ballot passing", needed)
+				}
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 )
 
 // RedemptionRequest is a type that represents a redemption request by a member
@@ -19,7 +20,7 @@ type RedemptionRequest struct {
 }
 
 // LoadRequestByID returns a request for the provided redemption ID
-func LoadRequestByID(ctx context.Context, api *eos.API, ID uint64) (RedemptionRequest, error) {
+func LoadRequestByID(ctx context.Context, reader chain.ChainReader, ID uint64) (RedemptionRequest, error) {
 	// var requests []RedemptionRequest
 	return RedemptionRequest{}, nil
 	// var request eos.GetTableRowsRequest
@@ -30,7 +31,7 @@ func LoadRequestByID(ctx context.Context, api *eos.API, ID uint64) (RedemptionRe
 	// request.LowerBound = strconv.Itoa(int(ID))
 	// request.UpperBound = strconv.Itoa(int(ID))
 	// request.JSON = true
-	// response, _ := api.GetTableRows(ctx, request)
+	// response, _ := reader.GetTableRows(ctx, request)
 	// response.JSONToStructs(&requests)
 
 	// if len(requests) >= 1 {
@@ -42,7 +43,7 @@ func LoadRequestByID(ctx context.Context, api *eos.API, ID uint64) (RedemptionRe
 }
 
 // Requests returns a list of all redemption requests
-func Requests(ctx context.Context, api *eos.API, all bool) []RedemptionRequest {
+func Requests(ctx context.Context, reader chain.ChainReader, all bool) []RedemptionRequest {
 	var requests []RedemptionRequest
 	return requests
 	// // var memberAccounts []eos.Name
@@ -55,7 +56,7 @@ func Requests(ctx context.Context, api *eos.API, all bool) []RedemptionRequest {
 	// request.Index = "3"
 	// request.KeyType = "i64"
 	// request.Reverse = true
-	// response, _ := api.GetTableRows(ctx, request)
+	// response, _ := reader.GetTableRows(ctx, request)
 	// response.JSONToStructs(&requests)
 
 	// for index, r := range requests {
@@ -19,15 +19,15 @@ type Role struct {
 	Creator          eos.AccountName
 	PriorID          uint64
 	Approved         bool
-	Owner            eos.Name
-	BallotName       eos.Name
-	Title            string
-	Description      string
-	URL              string
-	AnnualUSDSalary  eos.Asset
-	MinTime          float64
-	MinDeferred      float64
-	FullTimeCapacity float64
+	Owner            eos.Name  `content:"details.owner"`
+	BallotName       eos.Name  `content:"system.ballot_id"`
+	Title            string    `content:"details.title"`
+	Description      string    `content:"details.description"`
+	URL              string    `content:"details.url"`
+	AnnualUSDSalary  eos.Asset `content:"details.annual_usd_salary"`
+	MinTime          float64   `content:"details.min_time_share_x100,x100"`
+	MinDeferred      float64   `content:"details.min_deferred_x100,x100"`
+	FullTimeCapacity float64   `content:"details.fulltime_capacity_x100,x100"`
 	CreatedDate      eos.BlockTimestamp
 }
 
@@ -59,77 +59,38 @@ func NewRole(roleDoc docgraph.Document) (Role, error) {
 	r.Creator = roleDoc.Creator
 	r.CreatedDate = roleDoc.CreatedDate
 
-	titleFv, err := roleDoc.GetContentFromGroup("details", "title")
+	err := Decode(roleDoc, &r)
 	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-	r.Title = titleFv.String()
-
-	descFv, err := roleDoc.GetContentFromGroup("details", "description")
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-	r.Description = descFv.String()
-
-	ballotName, err := roleDoc.GetContentFromGroup("system", "ballot_id")
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-	r.BallotName, err = ballotName.Name()
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-
-	owner, err := roleDoc.GetContentFromGroup("details", "owner")
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-	r.Owner, err = owner.Name()
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
+		return Role{}, err
 	}
+	return r, nil
+}
 
-	annualUsdSalary, err := roleDoc.GetContentFromGroup("details", "annual_usd_salary")
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
-	r.AnnualUSDSalary, err = annualUsdSalary.Asset()
-	if err != nil {
-		return Role{}, fmt.Errorf("get content failed: %v", err)
-	}
+// RoleUtilisation is how much of a role's full time capacity its approved assignments take up,
+// as the sum of their time shares
+type RoleUtilisation struct {
+	Role
+	ActiveAssignments int
+	Active            float64
+	Upcoming          float64
+}
 
-	minTime, err := roleDoc.GetContentFromGroup("details", "min_time_share_x100")
-	if err != nil {
-		r.MinTime = 0
-	} else {
-		minTimeInt, err := minTime.Int64()
-		if err != nil {
-			return Role{}, fmt.Errorf("get content failed: %v", err)
-		}
-		r.MinTime = float64(minTimeInt) / 100
-	}
+// Committed returns the time share of the assignments that are active or have yet to start
+func (u *RoleUtilisation) Committed() float64 {
+	return u.Active + u.Upcoming
+}
 
-	fullTimeCap, err := roleDoc.GetContentFromGroup("details", "fulltime_capacity_x100")
-	if err != nil {
-		r.FullTimeCapacity = 1
-	} else {
-		ftc, err := fullTimeCap.Int64()
-		if err != nil {
-			return Role{}, fmt.Errorf("get content failed: %v", err)
-		}
-		r.FullTimeCapacity = float64(ftc) / 100
-	}
+// Remaining returns the capacity left for new assignments
+func (u *RoleUtilisation) Remaining() float64 {
+	return u.FullTimeCapacity - u.Committed()
+}
 
-	minDeferred, err := roleDoc.GetContentFromGroup("details", "min_deferred_x100")
-	if err != nil {
-		r.MinDeferred = 0
-	} else {
-		minDef, err := minDeferred.Int64()
-		if err != nil {
-			return Role{}, fmt.Errorf("get content failed: %v", err)
-		}
-		r.MinDeferred = float64(minDef) / 100
-	}
+// FTECapCost returns the annual cost of the role at full capacity
+func (u *RoleUtilisation) FTECapCost() eos.Asset {
+	return util.AssetMult(u.AnnualUSDSalary, big.NewFloat(u.FullTimeCapacity))
+}
 
-	return r, nil
+// CommittedCost returns the annual cost of the committed time share
+func (u *RoleUtilisation) CommittedCost() eos.Asset {
+	return util.AssetMult(u.AnnualUSDSalary, big.NewFloat(u.Committed()))
 }
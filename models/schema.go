@@ -0,0 +1,367 @@
+package models
+
+import (
+	"encoding/hex"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// FieldType is the FlexValue variant a content item holds
+type FieldType string
+
+// The FlexValue variants of the document graph
+const (
+	NameType        FieldType = "name"
+	StringType      FieldType = "string"
+	AssetType       FieldType = "asset"
+	TimePointType   FieldType = "time_point"
+	Int64Type       FieldType = "int64"
+	Checksum256Type FieldType = "checksum256"
+)
+
+// Field describes one content item of a document type
+type Field struct {
+	Group       string
+	Label       string
+	Type        FieldType
+	Required    bool
+	Default     string
+	Example     string
+	Description string
+}
+
+// Name returns the field's name as group.label
+func (f *Field) Name() string {
+	return f.Group + "." + f.Label
+}
+
+// Schema describes the content groups of a document type
+type Schema struct {
+	Type        eos.Name
+	Description string
+	Fields      []Field
+}
+
+// Field returns the schema's field with the group and label
+func (s *Schema) Field(group, label string) (*Field, bool) {
+	for i := range s.Fields {
+		if s.Fields[i].Group == group && s.Fields[i].Label == label {
+			return &s.Fields[i], true
+		}
+	}
+	return nil, false
+}
+
+// Groups returns the content group labels of the schema in the order they are first declared
+func (s *Schema) Groups() []string {
+	var groups []string
+	seen := make(map[string]bool)
+	for _, field := range s.Fields {
+		if !seen[field.Group] {
+			seen[field.Group] = true
+			groups = append(groups, field.Group)
+		}
+	}
+	return groups
+}
+
+var schemas = make(map[eos.Name]*Schema)
+
+// RegisterSchema adds the schema of a document type to the registry, replacing any schema
+// already registered for the type
+func RegisterSchema(schema Schema) {
+	schemas[schema.Type] = &schema
+}
+
+// GetSchema returns the registered schema of the document type
+func GetSchema(docType eos.Name) (*Schema, bool) {
+	schema, found := schemas[docType]
+	return schema, found
+}
+
+// SchemaTypes returns the document types with a registered schema, sorted
+func SchemaTypes() []eos.Name {
+	var types []eos.Name
+	for docType := range schemas {
+		types = append(types, docType)
+	}
+	sort.Slice(types, func(i, j int) bool { return types[i] < types[j] })
+	return types
+}
+
+// VariantType returns the FlexValue variant held by the value
+func VariantType(value *docgraph.FlexValue) FieldType {
+	if value == nil {
+		return "monostate"
+	}
+	switch value.Impl.(type) {
+	case eos.Name:
+		return NameType
+	case string:
+		return StringType
+	case eos.Asset:
+		return AssetType
+	case eos.TimePoint:
+		return TimePointType
+	case int64:
+		return Int64Type
+	case eos.Checksum256:
+		return Checksum256Type
+	default:
+		return "monostate"
+	}
+}
+
+var namePattern = regexp.MustCompile(`^[a-z1-5.]{1,12}$`)
+
+// ParseValue converts the text form of a value, as used for defaults and templates, into a
+// FlexValue of the field type
+func ParseValue(fieldType FieldType, text string) (*docgraph.FlexValue, error) {
+	var impl interface{}
+	switch fieldType {
+	case NameType:
+		if !namePattern.MatchString(text) {
+			return nil, fmt.Errorf("invalid name: %v, expected up to 12 characters a-z, 1-5 and .", text)
+		}
+		impl = eos.Name(text)
+	case StringType:
+		impl = text
+	case AssetType:
+		asset, err := eos.NewAssetFromString(text)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse asset: %v %v", text, err)
+		}
+		impl = asset
+	case TimePointType:
+		t, err := parseTimePoint(text)
+		if err != nil {
+			return nil, err
+		}
+		impl = eos.TimePoint(t.UnixNano() / 1000)
+	case Int64Type:
+		i, err := strconv.ParseInt(strings.TrimSpace(text), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("cannot parse integer: %v", text)
+		}
+		impl = i
+	case Checksum256Type:
+		checksum, err := hex.DecodeString(text)
+		if err != nil || len(checksum) != 32 {
+			return nil, fmt.Errorf("cannot parse checksum256: %v, expected 64 hex characters", text)
+		}
+		impl = eos.Checksum256(checksum)
+	default:
+		return nil, fmt.Errorf("unknown field type: %v", fieldType)
+	}
+	return &docgraph.FlexValue{
+		BaseVariant: eos.BaseVariant{
+			TypeID: docgraph.GetVariants().TypeID(string(fieldType)),
+			Impl:   impl,
+		}}, nil
+}
+
+func parseTimePoint(text string) (time.Time, error) {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02"} {
+		if t, err := time.Parse(layout, text); err == nil {
+			return t.UTC(), nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("cannot parse time: %v, expected a date such as 2021-03-01 or 2021-03-01T12:00:00", text)
+}
+
+// Violation is a way a document does not conform to the schema of its type
+type Violation struct {
+	ID      uint64
+	Hash    string
+	Type    eos.Name
+	Field   string
+	Problem string
+}
+
+// Validate checks the document against the schema of its type: required fields must be present
+// and every field must hold its declared variant. In strict mode documents of types without a
+// schema and content items the schema does not declare are reported too.
+func Validate(doc *docgraph.Document, strict bool) []Violation {
+	violation := func(docType eos.Name, field, problem string) Violation {
+		return Violation{ID: doc.ID, Hash: doc.Hash.String(), Type: docType, Field: field, Problem: problem}
+	}
+
+	docType, err := doc.GetType()
+	if err != nil {
+		return []Violation{violation("", "system.type", "missing document type")}
+	}
+	schema, found := GetSchema(docType)
+	if !found {
+		if strict {
+			return []Violation{violation(docType, "", "no schema for document type")}
+		}
+		return nil
+	}
+
+	var violations []Violation
+	for _, field := range schema.Fields {
+		value, err := groupContent(doc, field.Group, field.Label)
+		if err != nil || value == nil {
+			if field.Required {
+				violations = append(violations, violation(docType, field.Name(), "missing required field"))
+			}
+			continue
+		}
+		if actual := VariantType(value); actual != field.Type {
+			violations = append(violations, violation(docType, field.Name(),
+				fmt.Sprintf("expected %v, found %v", field.Type, actual)))
+		}
+	}
+
+	if strict {
+		for _, contentGroup := range doc.ContentGroups {
+			group := ""
+			if label, err := contentGroup.GetContent("content_group_label"); err == nil {
+				group = label.String()
+			}
+			for _, item := range contentGroup {
+				if item.Label == "content_group_label" {
+					continue
+				}
+				if _, declared := schema.Field(group, item.Label); !declared {
+					violations = append(violations, violation(docType, group+"."+item.Label, "field not in schema"))
+				}
+			}
+		}
+	}
+	return violations
+}
+
+// Decode fills the struct v points to from the document's content. Struct fields are mapped to
+// content items with a content tag of the form "group.label", optionally followed by ",x100" to
+// divide an int64 by 100 into a float64. Missing fields take the schema's default; a missing
+// required field is an error.
+func Decode(doc docgraph.Document, v interface{}) error {
+	target := reflect.ValueOf(v)
+	if target.Kind() != reflect.Ptr || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("cannot decode into %T, expected a pointer to a struct", v)
+	}
+	target = target.Elem()
+
+	docType, err := doc.GetType()
+	if err != nil {
+		return fmt.Errorf("cannot decode document %v: missing document type", doc.Hash.String())
+	}
+	schema, _ := GetSchema(docType)
+
+	for i := 0; i < target.NumField(); i++ {
+		tag := target.Type().Field(i).Tag.Get("content")
+		if tag == "" {
+			continue
+		}
+		name := strings.Split(tag, ",")[0]
+		x100 := strings.HasSuffix(tag, ",x100")
+		dot := strings.Index(name, ".")
+		if dot < 0 {
+			return fmt.Errorf("invalid content tag on %v: %v, expected group.label", target.Type().Field(i).Name, tag)
+		}
+		group, label := name[:dot], name[dot+1:]
+
+		var field *Field
+		if schema != nil {
+			field, _ = schema.Field(group, label)
+		}
+
+		value, err := groupContent(&doc, group, label)
+		if err != nil || value == nil {
+			switch {
+			case field != nil && field.Required:
+				return fmt.Errorf("cannot decode %v %v: missing required field %v", docType, doc.Hash.String(), name)
+			case field != nil && field.Default != "":
+				if value, err = ParseValue(field.Type, field.Default); err != nil {
+					return fmt.Errorf("invalid default for %v %v: %v", docType, name, err)
+				}
+			default:
+				continue
+			}
+		}
+
+		if err := setField(target.Field(i), value, x100); err != nil {
+			return fmt.Errorf("cannot decode %v %v field %v: %v", docType, doc.Hash.String(), name, err)
+		}
+	}
+	return nil
+}
+
+// groupContent returns the value of the item with the label in the content group with the group
+// label; unlike docgraph's GetContentFromGroup it does not match items of other groups
+func groupContent(doc *docgraph.Document, group, label string) (*docgraph.FlexValue, error) {
+	contentGroup, err := doc.GetContentGroup(group)
+	if err != nil {
+		return nil, err
+	}
+	return contentGroup.GetContent(label)
+}
+
+func setField(field reflect.Value, value *docgraph.FlexValue, x100 bool) error {
+	mismatch := func() error {
+		return fmt.Errorf("cannot store %v in %v", VariantType(value), field.Type())
+	}
+
+	switch field.Interface().(type) {
+	case string:
+		field.SetString(value.String())
+	case eos.Name, eos.AccountName:
+		name, err := value.Name()
+		if err != nil {
+			return mismatch()
+		}
+		field.SetString(string(name))
+	case eos.Asset:
+		asset, err := value.Asset()
+		if err != nil {
+			return mismatch()
+		}
+		field.Set(reflect.ValueOf(asset))
+	case eos.TimePoint:
+		timePoint, err := value.TimePoint()
+		if err != nil {
+			return mismatch()
+		}
+		field.Set(reflect.ValueOf(timePoint))
+	case time.Time:
+		timePoint, err := value.TimePoint()
+		if err != nil {
+			return mismatch()
+		}
+		field.Set(reflect.ValueOf(time.Unix(0, int64(timePoint)*1000).UTC()))
+	case eos.Checksum256:
+		checksum, ok := value.Impl.(eos.Checksum256)
+		if !ok {
+			return mismatch()
+		}
+		field.Set(reflect.ValueOf(checksum))
+	case int64:
+		i, err := value.Int64()
+		if err != nil {
+			return mismatch()
+		}
+		field.SetInt(i)
+	case float64:
+		i, err := value.Int64()
+		if err != nil {
+			return mismatch()
+		}
+		if x100 {
+			field.SetFloat(float64(i) / 100)
+		} else {
+			field.SetFloat(float64(i))
+		}
+	default:
+		return fmt.Errorf("unsupported field type %v", field.Type())
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package models
+
+// systemFields are the fields of the system content group every document carries; proposals
+// also carry the ballot they were voted on
+func systemFields(proposal bool) []Field {
+	fields := []Field{
+		{Group: "system", Label: "type", Type: NameType, Required: true, Description: "document type"},
+		{Group: "system", Label: "node_label", Type: StringType, Description: "label shown in graph views"},
+	}
+	if proposal {
+		fields = append(fields, Field{Group: "system", Label: "ballot_id", Type: NameType, Required: true, Description: "ballot the proposal was voted on"})
+	}
+	return fields
+}
+
+func init() {
+	RegisterSchema(Schema{
+		Type:        "dho",
+		Description: "root node of the DAO",
+		Fields: append([]Field{
+			{Group: "details", Label: "root_node", Type: StringType, Required: true, Description: "account of the DAO contract"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "settings",
+		Description: "DAO configuration",
+		Fields: append([]Field{
+			{Group: "details", Label: "voting_duration_sec", Type: Int64Type, Required: true, Description: "length of a ballot in seconds"},
+			{Group: "details", Label: "voting_quorum_x100", Type: Int64Type, Required: true, Description: "share of the vote supply that must vote, x100"},
+			{Group: "details", Label: "voting_alignment_x100", Type: Int64Type, Required: true, Description: "share of the votes cast that must pass, x100"},
+			{Group: "details", Label: "hypha_usd_price", Type: AssetType, Required: true, Description: "USD price of one HYPHA"},
+			{Group: "details", Label: "seeds_usd_price", Type: AssetType, Description: "USD price of one SEEDS"},
+			{Group: "details", Label: "hypha_deferral_factor_x100", Type: Int64Type, Required: true, Description: "HYPHA paid per deferred USD, x100"},
+			{Group: "details", Label: "seeds_deferral_factor_x100", Type: Int64Type, Description: "SEEDS paid per deferred USD, x100"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "period",
+		Description: "pay period of the DAO calendar",
+		Fields: append([]Field{
+			{Group: "details", Label: "start_time", Type: TimePointType, Required: true, Description: "start of the period"},
+			{Group: "details", Label: "label", Type: StringType, Required: true, Description: "name of the period"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "member",
+		Description: "enrolled member of the DAO",
+		Fields: append([]Field{
+			{Group: "details", Label: "member", Type: NameType, Required: true, Description: "member account"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "role",
+		Description: "role that members can be assigned to",
+		Fields: append([]Field{
+			{Group: "details", Label: "title", Type: StringType, Required: true, Description: "title of the role"},
+			{Group: "details", Label: "description", Type: StringType, Required: true, Description: "responsibilities of the role"},
+			{Group: "details", Label: "url", Type: StringType, Description: "link to more information"},
+			{Group: "details", Label: "owner", Type: NameType, Required: true, Description: "account proposing the role"},
+			{Group: "details", Label: "annual_usd_salary", Type: AssetType, Required: true, Example: "150000.00 USD", Description: "annual salary of a full time assignment"},
+			{Group: "details", Label: "min_time_share_x100", Type: Int64Type, Default: "0", Description: "minimum time commitment of an assignment, x100"},
+			{Group: "details", Label: "min_deferred_x100", Type: Int64Type, Default: "0", Description: "minimum share of pay deferred, x100"},
+			{Group: "details", Label: "fulltime_capacity_x100", Type: Int64Type, Default: "100", Description: "number of full time people the role can hold, x100"},
+		}, systemFields(true)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "assignment",
+		Description: "member assigned to a role for a number of periods",
+		Fields: append([]Field{
+			{Group: "details", Label: "title", Type: StringType, Required: true, Description: "title of the assignment"},
+			{Group: "details", Label: "description", Type: StringType, Required: true, Description: "what the assignee will do"},
+			{Group: "details", Label: "assignee", Type: NameType, Required: true, Description: "account assigned to the role"},
+			{Group: "details", Label: "owner", Type: NameType, Required: true, Description: "account proposing the assignment"},
+			{Group: "details", Label: "role", Type: Checksum256Type, Description: "hash of the role"},
+			{Group: "details", Label: "start_period", Type: Checksum256Type, Description: "hash of the first period"},
+			{Group: "details", Label: "period_count", Type: Int64Type, Required: true, Example: "12", Description: "number of periods"},
+			{Group: "details", Label: "time_share_x100", Type: Int64Type, Required: true, Example: "100", Description: "time commitment, x100"},
+			{Group: "details", Label: "deferred_pay_x100", Type: Int64Type, Required: true, Example: "50", Description: "share of pay deferred, x100"},
+			{Group: "details", Label: "husd_salary_per_phase", Type: AssetType, Required: true, Example: "1000.00 HUSD", Description: "HUSD paid per period"},
+			{Group: "details", Label: "hypha_salary_per_phase", Type: AssetType, Required: true, Example: "200.00 HYPHA", Description: "HYPHA paid per period"},
+			{Group: "details", Label: "hvoice_salary_per_phase", Type: AssetType, Required: true, Example: "400.00 HVOICE", Description: "HVOICE paid per period"},
+		}, systemFields(true)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "payout",
+		Description: "one time payment for a contribution",
+		Fields: append([]Field{
+			{Group: "details", Label: "title", Type: StringType, Required: true, Description: "title of the contribution"},
+			{Group: "details", Label: "description", Type: StringType, Required: true, Description: "what was contributed"},
+			{Group: "details", Label: "recipient", Type: NameType, Required: true, Description: "account to pay"},
+			{Group: "details", Label: "husd_amount", Type: AssetType, Default: "0.00 HUSD", Description: "HUSD to pay"},
+			{Group: "details", Label: "hypha_amount", Type: AssetType, Default: "0.00 HYPHA", Description: "HYPHA to pay"},
+			{Group: "details", Label: "hvoice_amount", Type: AssetType, Default: "0.00 HVOICE", Description: "HVOICE to pay"},
+			{Group: "details", Label: "seeds_instant_amount", Type: AssetType, Default: "0.0000 SEEDS", Description: "SEEDS to pay at once"},
+			{Group: "details", Label: "seeds_escrow_amount", Type: AssetType, Default: "0.0000 SEEDS", Description: "SEEDS to pay into escrow"},
+			{Group: "details", Label: "deferred_perc_x100", Type: Int64Type, Default: "0", Description: "share of pay deferred, x100"},
+			{Group: "details", Label: "instant_husd_perc_x100", Type: Int64Type, Default: "0", Description: "share of HUSD paid at once, x100"},
+		}, systemFields(true)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "badge",
+		Description: "badge that multiplies the pay of its holders",
+		Fields: append([]Field{
+			{Group: "details", Label: "title", Type: StringType, Required: true, Description: "title of the badge"},
+			{Group: "details", Label: "description", Type: StringType, Required: true, Description: "what the badge recognises"},
+			{Group: "details", Label: "icon", Type: StringType, Description: "icon shown for the badge"},
+			{Group: "details", Label: "husd_coefficient_x10000", Type: Int64Type, Default: "10000", Description: "HUSD pay multiplier, x10000"},
+			{Group: "details", Label: "hypha_coefficient_x10000", Type: Int64Type, Default: "10000", Description: "HYPHA pay multiplier, x10000"},
+			{Group: "details", Label: "hvoice_coefficient_x10000", Type: Int64Type, Default: "10000", Description: "HVOICE pay multiplier, x10000"},
+			{Group: "details", Label: "seeds_coefficient_x10000", Type: Int64Type, Default: "10000", Description: "SEEDS pay multiplier, x10000"},
+		}, systemFields(true)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "payment",
+		Description: "payment made by the DAO",
+		Fields: append([]Field{
+			{Group: "details", Label: "recipient", Type: NameType, Required: true, Description: "account paid"},
+			{Group: "details", Label: "amount", Type: AssetType, Required: true, Description: "amount paid"},
+			{Group: "details", Label: "memo", Type: StringType, Description: "memo of the transfer"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "vote.tally",
+		Description: "vote power cast per option on a proposal",
+		Fields: append([]Field{
+			{Group: "pass", Label: "vote_power", Type: AssetType, Required: true, Description: "vote power for pass"},
+			{Group: "fail", Label: "vote_power", Type: AssetType, Required: true, Description: "vote power for fail"},
+			{Group: "abstain", Label: "vote_power", Type: AssetType, Required: true, Description: "vote power for abstain"},
+		}, systemFields(false)...),
+	})
+
+	RegisterSchema(Schema{
+		Type:        "vote",
+		Description: "vote of a member on a proposal",
+		Fields: append([]Field{
+			{Group: "vote", Label: "voter", Type: NameType, Required: true, Description: "account that voted"},
+			{Group: "vote", Label: "vote_power", Type: AssetType, Required: true, Description: "vote power of the voter"},
+			{Group: "vote", Label: "vote", Type: StringType, Required: true, Description: "pass, fail or abstain"},
+			{Group: "vote", Label: "date", Type: TimePointType, Required: true, Description: "time of the vote"},
+		}, systemFields(false)...),
+	})
+}
@@ -0,0 +1,28 @@
+package models
+
+import (
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Settings is the configuration of the DAO held in its settings document
+type Settings struct {
+	Hash                eos.Checksum256
+	VotingDurationSec   int64     `content:"details.voting_duration_sec"`
+	VotingQuorum        float64   `content:"details.voting_quorum_x100,x100"`
+	VotingAlignment     float64   `content:"details.voting_alignment_x100,x100"`
+	HyphaUSDPrice       eos.Asset `content:"details.hypha_usd_price"`
+	SeedsUSDPrice       eos.Asset `content:"details.seeds_usd_price"`
+	HyphaDeferralFactor float64   `content:"details.hypha_deferral_factor_x100,x100"`
+	SeedsDeferralFactor float64   `content:"details.seeds_deferral_factor_x100,x100"`
+}
+
+// NewSettings converts the settings document to Settings
+func NewSettings(doc docgraph.Document) (Settings, error) {
+	s := Settings{Hash: doc.Hash}
+	err := Decode(doc, &s)
+	if err != nil {
+		return Settings{}, err
+	}
+	return s, nil
+}
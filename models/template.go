@@ -0,0 +1,83 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// Proposable reports whether documents of the schema's type are created by proposals, which the
+// contract gives a ballot
+func (s *Schema) Proposable() bool {
+	_, found := s.Field("system", "ballot_id")
+	return found
+}
+
+// ProposalTypes returns the document types with a schema that can be proposed, sorted
+func ProposalTypes() []eos.Name {
+	var types []eos.Name
+	for _, docType := range SchemaTypes() {
+		if schemas[docType].Proposable() {
+			types = append(types, docType)
+		}
+	}
+	return types
+}
+
+// ProposalContent builds the content groups of a proposal of the schema's type from the text of
+// its values, keyed by group and label. Each value is parsed as its field's FlexValue type. Unknown
+// fields, values that do not parse and missing required fields are all reported in one error. The
+// system group is left to the contract.
+func ProposalContent(schema *Schema, values map[string]map[string]string) ([]docgraph.ContentGroup, error) {
+	var problems []string
+	for group, items := range values {
+		for label := range items {
+			if _, declared := schema.Field(group, label); !declared || group == "system" {
+				problems = append(problems, fmt.Sprintf("%v.%v is not a field of %v", group, label, schema.Type))
+			}
+		}
+	}
+
+	var contentGroups []docgraph.ContentGroup
+	for _, group := range schema.Groups() {
+		if group == "system" {
+			continue
+		}
+
+		contentGroup := docgraph.ContentGroup{{
+			Label: "content_group_label",
+			Value: &docgraph.FlexValue{BaseVariant: eos.BaseVariant{
+				TypeID: docgraph.GetVariants().TypeID(string(StringType)),
+				Impl:   group,
+			}},
+		}}
+		for _, field := range schema.Fields {
+			if field.Group != group {
+				continue
+			}
+			text, found := values[group][field.Label]
+			if !found || text == "" {
+				if field.Required {
+					problems = append(problems, fmt.Sprintf("%v is required", field.Name()))
+				}
+				continue
+			}
+			value, err := ParseValue(field.Type, text)
+			if err != nil {
+				problems = append(problems, fmt.Sprintf("%v: %v", field.Name(), err))
+				continue
+			}
+			contentGroup = append(contentGroup, docgraph.ContentItem{Label: field.Label, Value: value})
+		}
+		contentGroups = append(contentGroups, contentGroup)
+	}
+
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid %v proposal:\n  %v", schema.Type, strings.Join(problems, "\n  "))
+	}
+	return contentGroups, nil
+}
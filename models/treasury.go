@@ -10,6 +10,7 @@ import (
 
 	eos "github.com/eoscanada/eos-go"
 	"github.com/eoscanada/eosc/cli"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/spf13/viper"
 )
 
@@ -89,17 +90,17 @@ func toAccount(in, field string) eos.AccountName {
 }
 
 // Load ...
-func Load(api *eos.API, treasuryContract, tokenContract, symbol string) Treasury {
+func Load(reader chain.ChainReader, treasuryContract, tokenContract, symbol string) Treasury {
 	var treasury Treasury
-	treasury.loadConfig(api, treasuryContract)
+	treasury.loadConfig(reader, treasuryContract)
 	// treasury.Members = make(map[eos.Name]TreasuryBalance)
-	treasury.loadMembers(api, treasuryContract, tokenContract, symbol)
+	treasury.loadMembers(reader, treasuryContract, tokenContract, symbol)
 	treasury.loadEthUSDT(viper.GetString("Treasury.EthUSDTContract"), viper.GetString("Treasury.EthUSDTAddress"))
 	treasury.loadBtcBalance("")
 	return treasury
 }
 
-func (t *Treasury) loadConfig(api *eos.API, treasuryContract string) {
+func (t *Treasury) loadConfig(reader chain.ChainReader, treasuryContract string) {
 
 	// LoadTreasConfig loads the treasury configuration from the smart contract
 	var rto []rawConfig
@@ -109,7 +110,7 @@ func (t *Treasury) loadConfig(api *eos.API, treasuryContract string) {
 	request.Table = "config"
 	request.Limit = 1
 	request.JSON = true
-	response, _ := api.GetTableRows(context.Background(), request)
+	response, _ := reader.GetTableRows(context.Background(), request)
 	response.JSONToStructs(&rto)
 
 	// bookmark known values
@@ -133,7 +134,7 @@ func (t *Treasury) loadConfig(api *eos.API, treasuryContract string) {
 	t.Config.RedemptionSymbol = &rto[0].RedemptionSymbol
 }
 
-func (t *Treasury) getRedemptionRequests(api *eos.API, treasuryContract string) map[eos.Name]eos.Asset {
+func (t *Treasury) getRedemptionRequests(reader chain.ChainReader, treasuryContract string) map[eos.Name]eos.Asset {
 	var request eos.GetTableRowsRequest
 	request.Code = treasuryContract
 	request.Scope = treasuryContract
@@ -145,7 +146,7 @@ func (t *Treasury) getRedemptionRequests(api *eos.API, treasuryContract string)
 	request.JSON = true
 
 	var rr []RedemptionRequest
-	rrResponse, err := api.GetTableRows(context.Background(), request)
+	rrResponse, err := reader.GetTableRows(context.Background(), request)
 	if err != nil {
 		panic(err)
 	}
@@ -179,12 +180,12 @@ type Scope struct {
 	Count uint64   `json:"count"`
 }
 
-func (t *Treasury) getHolders(api *eos.API, treasuryContract, tokenContract, symbol string) map[eos.Name]eos.Asset {
+func (t *Treasury) getHolders(reader chain.ChainReader, treasuryContract, tokenContract, symbol string) map[eos.Name]eos.Asset {
 	var request eos.GetTableByScopeRequest
 	request.Code = tokenContract
 	request.Table = "accounts"
 	request.Limit = 500 // TODO: move to a MaxMembers parameter or check the "more" return value
-	response, err := api.GetTableByScope(context.Background(), request)
+	response, err := reader.GetTableByScope(context.Background(), request)
 	errorCheck("get table by scope", err)
 
 	var scopes []Scope
@@ -196,7 +197,7 @@ func (t *Treasury) getHolders(api *eos.API, treasuryContract, tokenContract, sym
 	for _, scope := range scopes {
 
 		tokenHolder := eos.AccountName(scope.Scope)
-		balances, err := api.GetCurrencyBalance(context.Background(), tokenHolder, symbol, eos.AN(tokenContract))
+		balances, err := reader.GetCurrencyBalance(context.Background(), tokenHolder, symbol, eos.AN(tokenContract))
 		errorCheck("getting currency balance", err)
 
 		if len(balances) > 0 {
@@ -211,9 +212,9 @@ func (t *Treasury) getHolders(api *eos.API, treasuryContract, tokenContract, sym
 	return holders
 }
 
-func (t *Treasury) loadMembers(api *eos.API, treasuryContract, tokenContract, symbol string) {
-	holderBalances := t.getHolders(api, treasuryContract, tokenContract, symbol)
-	rrMap := t.getRedemptionRequests(api, treasuryContract)
+func (t *Treasury) loadMembers(reader chain.ChainReader, treasuryContract, tokenContract, symbol string) {
+	holderBalances := t.getHolders(reader, treasuryContract, tokenContract, symbol)
+	rrMap := t.getRedemptionRequests(reader, treasuryContract)
 	zeroHusd, _ := eos.NewAssetFromString("0.00 HUSD")
 
 	t.Members = make(map[eos.Name]Balance)
@@ -263,11 +264,11 @@ func (t *Treasury) loadBtcBalance(btcAddress string) {
 	fmt.Println("Note: Bitcoin Treasury balance not yet supported. Use the --addl-balance parameter to add the BTC balance.")
 }
 
-// func GetHusdBankBalance(api *eos.API) {
+// func GetHusdBankBalance(reader chain.ChainReader) {
 
 // 	// tokenContract := eos.AN(viper.GetString("TreasuryTokenContract")) //toAccount(viper.GetString("TreasuryTokenContract"), "TreasuryTokenContract account")
-// 	tokenHoldings := GetTokenHoldings(api, viper.GetString("TreasuryTokenContract"), viper.GetString("TreasurySymbol"))
+// 	tokenHoldings := GetTokenHoldings(reader, viper.GetString("TreasuryTokenContract"), viper.GetString("TreasurySymbol"))
 
 // 	// treasuryAccount := toAccount(viper.GetString("TreasuryContract"), "treasury contract")
-// 	// balances, err := api.GetCurrencyBalance(context.Background(), treasuryAccount, treasuryTokenContract)
+// 	// balances, err := reader.GetCurrencyBalance(context.Background(), treasuryAccount, treasuryTokenContract)
 // }
@@ -39,4 +39,8 @@ type QrAction struct {
 	ActionContract string
 	ActionName     string
 	Data           string
+	RawData        string
+	BlockNum       uint32
+	Actor          string
+	GlobalSequence uint64
 }
@@ -0,0 +1,148 @@
+package util
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/hypha-dao/document-graph/docgraph"
+	bolt "go.etcd.io/bbolt"
+)
+
+// CacheStatus summarizes the contents and freshness of a graph cache
+type CacheStatus struct {
+	FileName        string
+	Contract        string
+	Documents       int
+	Edges           int
+	DocumentsByType map[string]int
+	EdgesByName     map[string]int
+	LastSyncBlock   uint64
+	LastSyncTime    time.Time
+	LastReconcile   time.Time
+	LastDocumentID  uint64
+	HasDocumentID   bool
+}
+
+// CacheProblem is an integrity problem found while verifying a graph cache
+type CacheProblem struct {
+	Kind   string
+	ID     uint64
+	Hash   string
+	Detail string
+}
+
+// Status counts the cached documents per type and edges per name and reports when the cache was last synced
+func (gc *GraphCache) Status() (CacheStatus, error) {
+	status := CacheStatus{
+		FileName:        gc.FileName,
+		Contract:        string(gc.Contract),
+		DocumentsByType: make(map[string]int),
+		EdgesByName:     make(map[string]int),
+	}
+
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		status.LastSyncBlock = keyUint64(meta.Get(lastSyncBlockKey))
+		if syncTime := meta.Get(lastSyncTimeKey); syncTime != nil {
+			status.LastSyncTime, _ = time.Parse(time.RFC3339, string(syncTime))
+		}
+		if reconcileTime := meta.Get(lastReconcileTimeKey); reconcileTime != nil {
+			status.LastReconcile, _ = time.Parse(time.RFC3339, string(reconcileTime))
+		}
+		if id := meta.Get(lastDocumentIDKey); id != nil {
+			status.LastDocumentID = keyUint64(id)
+			status.HasDocumentID = true
+		}
+
+		status.Documents = tx.Bucket(documentsBucket).Stats().KeyN
+		status.Edges = tx.Bucket(edgesBucket).Stats().KeyN
+
+		err := tx.Bucket(typesBucket).ForEach(func(k, v []byte) error {
+			status.DocumentsByType[indexValue(k)]++
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(edgeNamesBucket).ForEach(func(k, v []byte) error {
+			status.EdgesByName[indexValue(k)]++
+			return nil
+		})
+	})
+	if err != nil {
+		return CacheStatus{}, fmt.Errorf("cannot read graph cache status: %v", err)
+	}
+	return status, nil
+}
+
+// Verify recomputes the hash of every cached document from its content groups and checks
+// that both ends of every cached edge are cached documents
+func (gc *GraphCache) Verify() ([]CacheProblem, error) {
+	var problems []CacheProblem
+
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		docs := tx.Bucket(documentsBucket)
+
+		err := docs.ForEach(func(hash, data []byte) error {
+			var document docgraph.Document
+			if err := json.Unmarshal(data, &document); err != nil {
+				problems = append(problems, CacheProblem{
+					Kind:   "unreadable document",
+					Hash:   string(hash),
+					Detail: err.Error(),
+				})
+				return nil
+			}
+
+			computed := HashContentGroups(document.ContentGroups).String()
+			if computed != string(hash) {
+				problems = append(problems, CacheProblem{
+					Kind:   "hash mismatch",
+					ID:     document.ID,
+					Hash:   string(hash),
+					Detail: "content hashes to " + computed,
+				})
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		return tx.Bucket(edgesBucket).ForEach(func(k, data []byte) error {
+			var edge docgraph.Edge
+			if err := json.Unmarshal(data, &edge); err != nil {
+				problems = append(problems, CacheProblem{
+					Kind:   "unreadable edge",
+					ID:     keyUint64(k),
+					Detail: err.Error(),
+				})
+				return nil
+			}
+
+			if docs.Get([]byte(edge.FromNode.String())) == nil {
+				problems = append(problems, CacheProblem{
+					Kind:   "dangling edge",
+					ID:     edge.ID,
+					Hash:   edge.FromNode.String(),
+					Detail: fmt.Sprintf("from node of %v edge is not cached", edge.EdgeName),
+				})
+			}
+			if docs.Get([]byte(edge.ToNode.String())) == nil {
+				problems = append(problems, CacheProblem{
+					Kind:   "dangling edge",
+					ID:     edge.ID,
+					Hash:   edge.ToNode.String(),
+					Detail: fmt.Sprintf("to node of %v edge is not cached", edge.EdgeName),
+				})
+			}
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot verify graph cache: %v", err)
+	}
+	return problems, nil
+}
@@ -0,0 +1,54 @@
+package util
+
+import (
+	"crypto/sha256"
+	"strconv"
+	"strings"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// HashContentGroups computes the hash the document graph contract assigns to a document
+// with these content groups, i.e. the sha256 of the contract's string rendering of them
+func HashContentGroups(contentGroups []docgraph.ContentGroup) eos.Checksum256 {
+	hash := sha256.Sum256([]byte(ContentGroupsToString(contentGroups)))
+	return eos.Checksum256(hash[:])
+}
+
+// ContentGroupsToString renders content groups the same way as the document graph contract
+func ContentGroupsToString(contentGroups []docgraph.ContentGroup) string {
+	groups := make([]string, len(contentGroups))
+	for i, contentGroup := range contentGroups {
+		items := make([]string, len(contentGroup))
+		for j, item := range contentGroup {
+			items[j] = contentItemToString(item)
+		}
+		groups[i] = "[" + strings.Join(items, ",") + "]"
+	}
+	return "[" + strings.Join(groups, ",") + "]"
+}
+
+// contentItemToString renders an item by its variant type, as a monostate and an int64 hold the
+// same Go type
+func contentItemToString(item docgraph.ContentItem) string {
+	value := "[monostate]"
+	if item.Value != nil {
+		_, typeName, impl := item.Value.Obtain(docgraph.GetVariants())
+		switch typeName {
+		case "name":
+			value = "[name," + string(impl.(eos.Name)) + "]"
+		case "string":
+			value = "[string," + impl.(string) + "]"
+		case "asset":
+			value = "[asset," + impl.(eos.Asset).String() + "]"
+		case "time_point":
+			value = "[time_point," + strconv.FormatUint(uint64(impl.(eos.TimePoint))/1000000, 10) + "]"
+		case "int64":
+			value = "[int64," + strconv.FormatInt(impl.(int64), 10) + "]"
+		case "checksum256":
+			value = "[checksum256," + impl.(eos.Checksum256).String() + "]"
+		}
+	}
+	return "{" + item.Label + "=" + value + "}"
+}
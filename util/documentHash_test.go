@@ -0,0 +1,80 @@
+package util
+
+import (
+	"encoding/json"
+	"testing"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// roleDocument holds one item of each variant type, as the chain's get_table_rows returns it
+const roleDocument = `{
+	"id": 24,
+	"creator": "johnnyhypha1",
+	"content_groups": [[
+		{"label": "content_group_label", "value": ["string", "details"]},
+		{"label": "title", "value": ["string", "Healer"]},
+		{"label": "annual_usd_salary", "value": ["asset", "150000.00 USD"]},
+		{"label": "start_period", "value": ["checksum256", "7706e72c29af438f309a99391fa8e8e3dcef0db438d0d24daf6fc4cf29697bff"]},
+		{"label": "start_time", "value": ["time_point", "2020-10-16T14:02:32.500"]},
+		{"label": "min_time_share_x100", "value": ["int64", 50]},
+		{"label": "deferred_perc_x100", "value": ["int64", 0]},
+		{"label": "unset", "value": ["monostate", 0]}
+	], [
+		{"label": "content_group_label", "value": ["string", "system"]},
+		{"label": "type", "value": ["name", "role"]}
+	]],
+	"created_date": "2020-10-16T14:02:32.500"
+}`
+
+func TestHashContentGroups(t *testing.T) {
+	var document docgraph.Document
+	if err := json.Unmarshal([]byte(roleDocument), &document); err != nil {
+		t.Fatal(err)
+	}
+
+	// the contract's Content::toString renders a time_point in seconds and a monostate without a value
+	rendering := "[[{content_group_label=[string,details]},{title=[string,Healer]}," +
+		"{annual_usd_salary=[asset,150000.00 USD]}," +
+		"{start_period=[checksum256,7706e72c29af438f309a99391fa8e8e3dcef0db438d0d24daf6fc4cf29697bff]}," +
+		"{start_time=[time_point,1602856952]},{min_time_share_x100=[int64,50]},{deferred_perc_x100=[int64,0]}," +
+		"{unset=[monostate]}],[{content_group_label=[string,system]},{type=[name,role]}]]"
+	if got := ContentGroupsToString(document.ContentGroups); got != rendering {
+		t.Errorf("got rendering\n%v\nwant\n%v", got, rendering)
+	}
+
+	// sha256 of the rendering above, computed with sha256sum
+	hash := "013bceba25b80461ef32f6087bf06683f169973bfe4936cabaa438587574253d"
+	if got := HashContentGroups(document.ContentGroups).String(); got != hash {
+		t.Errorf("got hash %v, want %v", got, hash)
+	}
+}
+
+func TestContentItemToString(t *testing.T) {
+	value := func(typeName string, impl interface{}) *docgraph.FlexValue {
+		return &docgraph.FlexValue{BaseVariant: eos.BaseVariant{
+			TypeID: docgraph.GetVariants().TypeID(typeName),
+			Impl:   impl,
+		}}
+	}
+	tests := []struct {
+		name string
+		item docgraph.ContentItem
+		want string
+	}{
+		{"monostate", docgraph.ContentItem{Label: "x", Value: value("monostate", int64(0))}, "{x=[monostate]}"},
+		{"int64 zero", docgraph.ContentItem{Label: "x", Value: value("int64", int64(0))}, "{x=[int64,0]}"},
+		{"negative int64", docgraph.ContentItem{Label: "x", Value: value("int64", int64(-5))}, "{x=[int64,-5]}"},
+		{"no value", docgraph.ContentItem{Label: "x"}, "{x=[monostate]}"},
+		{"name", docgraph.ContentItem{Label: "owner", Value: value("name", eos.Name("johnnyhypha1"))}, "{owner=[name,johnnyhypha1]}"},
+		{"time point", docgraph.ContentItem{Label: "t", Value: value("time_point", eos.TimePoint(1602856952500000))}, "{t=[time_point,1602856952]}"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := contentItemToString(test.item); got != test.want {
+				t.Errorf("got %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,16 +1,23 @@
 package util
 
 import (
+	"bytes"
 	"context"
-	"encoding/gob"
+	"encoding/binary"
+	"encoding/json"
 	"fmt"
-	"strconv"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/dfuse-io/logging"
 	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
 	"github.com/hypha-dao/document-graph/docgraph"
-	"github.com/patrickmn/go-cache"
+	"github.com/spf13/viper"
+	bolt "go.etcd.io/bbolt"
 	"go.uber.org/zap"
 )
 
@@ -20,120 +27,894 @@ func init() {
 	logging.Register("github.com/hypha-dao/daoctl/util", &zlog)
 }
 
+var (
+	documentsBucket   = []byte("documents")
+	documentIDsBucket = []byte("document_ids")
+	typesBucket       = []byte("types")
+	creatorsBucket    = []byte("creators")
+	labelsBucket      = []byte("labels")
+	edgesBucket       = []byte("edges")
+	edgesFromBucket   = []byte("edges_from")
+	edgesToBucket     = []byte("edges_to")
+	edgeNamesBucket   = []byte("edge_names")
+	metaBucket        = []byte("meta")
+
+	allBuckets = [][]byte{documentsBucket, documentIDsBucket, typesBucket, creatorsBucket, labelsBucket,
+		edgesBucket, edgesFromBucket, edgesToBucket, edgeNamesBucket, metaBucket}
+
+	lastSyncTimeKey      = []byte("last_sync_time")
+	lastSyncBlockKey     = []byte("last_sync_block")
+	lastDocumentIDKey    = []byte("last_document_id")
+	lastReconcileTimeKey = []byte("last_reconcile_time")
+	contractKey          = []byte("contract")
+	versionKey           = []byte("version")
+)
+
+// cacheVersion is bumped whenever the layout of the cache changes; caches written with
+// another version are cleared and rebuilt on open
+const cacheVersion = "4"
+
+// defaultReconcileInterval is how long the cache is synced incrementally before a sync reads the
+// whole document and edge tables again to pick up erasures and edges between older documents
+const defaultReconcileInterval = 24 * time.Hour
+
+// syncBatchSize is the number of table rows requested per round trip while syncing
+const syncBatchSize = 1000
+
+// GraphCache is a persistent, content-addressed local copy of a DAO's document graph.
+// Documents are stored by hash and indexed by ID, type and creator; edges are stored by
+// ID and indexed by from node, to node and edge name.
 type GraphCache struct {
-	Cache      *cache.Cache
-	DocsByType map[string][]string
+	FileName string
+	Contract eos.AccountName
+	db       *bolt.DB
 }
 
-func NewCache() *GraphCache {
-	gob.Register(eos.Name(""))
-	gob.Register(eos.Asset{})
-	gob.Register(eos.TimePoint(0))
-	gob.Register(eos.Checksum256{})
-	gob.Register(docgraph.Document{})
-	gob.Register(docgraph.Edge{})
-	gob.Register(map[string][]string{})
-	gob.Register(map[string]string{})
+// SyncResult reports what a sync added to and removed from the cache
+type SyncResult struct {
+	Documents        int
+	Edges            int
+	RemovedDocuments int
+	RemovedEdges     int
+}
 
-	gc := GraphCache{}
-	gc.Cache = cache.New(60*time.Minute, 60*time.Minute)
-	gc.DocsByType = make(map[string][]string)
-	return &gc
+// DefaultCacheFile returns the cache file for a network and contract under the user cache directory.
+// The GraphCacheFile setting overrides it.
+func DefaultCacheFile(network string, contract eos.AccountName) (string, error) {
+	if fileName := viper.GetString("GraphCacheFile"); fileName != "" {
+		return fileName, nil
+	}
+
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cannot find user cache directory: %v", err)
+	}
+	if network == "" {
+		network = "default"
+	}
+	return filepath.Join(dir, "daoctl", network, string(contract)+".db"), nil
 }
 
-func GetCache(ctx context.Context, api *eos.API, contract eos.AccountName) (*GraphCache, error) {
+// OpenGraphCache opens, creating if needed, the graph cache stored in fileName
+func OpenGraphCache(fileName string, contract eos.AccountName) (*GraphCache, error) {
+	err := os.MkdirAll(filepath.Dir(fileName), 0755)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create cache directory: %v %v", filepath.Dir(fileName), err)
+	}
 
-	gCache := NewCache()
-	err := gCache.Cache.LoadFile(".graph.cache")
+	db, err := bolt.Open(fileName, 0644, &bolt.Options{Timeout: 5 * time.Second})
 	if err != nil {
-		zlog.Info("unable to load cache, building fresh one: %v", zap.Error(err))
-		return FreshCache(ctx, api, contract)
+		return nil, fmt.Errorf("cannot open graph cache: %v %v", fileName, err)
 	}
-	var found bool
-	dbt, found := gCache.Cache.Get("DocsByType")
-	if !found {
-		zlog.Debug("DocsByType was not found in the cache, assume it is expired and building a freshie")
-		return FreshCache(ctx, api, contract)
+
+	gc := GraphCache{FileName: fileName, Contract: contract, db: db}
+	var version string
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range allBuckets {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		version = string(tx.Bucket(metaBucket).Get(versionKey))
+		return tx.Bucket(metaBucket).Put(contractKey, []byte(contract))
+	})
+	if err == nil && version != cacheVersion {
+		zlog.Debug("graph cache version changed, clearing it", zap.String("file", fileName), zap.String("version", version))
+		err = gc.Clear()
 	}
-	gCache.DocsByType = dbt.(map[string][]string)
-	zlog.Debug("cache file found, loading into memory")
-	return gCache, nil
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("cannot initialize graph cache: %v %v", fileName, err)
+	}
+
+	return &gc, nil
 }
 
-func FreshCache(ctx context.Context, api *eos.API, contract eos.AccountName) (*GraphCache, error) {
+// Close releases the cache file
+func (gc *GraphCache) Close() error {
+	return gc.db.Close()
+}
 
-	gCache := NewCache()
+// Clear removes every document and edge and the sync time from the cache
+func (gc *GraphCache) Clear() error {
+	return gc.db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range allBuckets {
+			if err := tx.DeleteBucket(bucket); err != nil && err != bolt.ErrBucketNotFound {
+				return err
+			}
+			if _, err := tx.CreateBucket(bucket); err != nil {
+				return err
+			}
+		}
+		meta := tx.Bucket(metaBucket)
+		if err := meta.Put(versionKey, []byte(cacheVersion)); err != nil {
+			return err
+		}
+		return meta.Put(contractKey, []byte(gc.Contract))
+	})
+}
 
-	documents, err := docgraph.GetAllDocuments(ctx, api, contract)
+// Sync brings the cache up to date with the documents created since the last sync: documents
+// with IDs above the document watermark are added, with the edges from and to them. Document IDs
+// are a sequence but edge IDs are hashes, so edges between older documents, and documents and
+// edges erased on chain, are only picked up by Reconcile. A cache that was never reconciled, or
+// was last reconciled longer ago than the GraphCacheReconcileInterval setting, is reconciled
+// instead.
+func (gc *GraphCache) Sync(ctx context.Context, reader chain.ChainReader) (SyncResult, error) {
+	marks, err := gc.syncMarks()
 	if err != nil {
-		return nil, fmt.Errorf("cannot get all documents: %v", err)
+		return SyncResult{}, err
+	}
+	if marks.lastReconcile.IsZero() || time.Since(marks.lastReconcile) > reconcileInterval() {
+		return gc.Reconcile(ctx, reader)
+	}
+
+	var result SyncResult
+	lowerBound := marks.lastDocumentID + 1
+	if !marks.hasDocumentID {
+		lowerBound = 0
+	}
+	lastDocumentID := marks.lastDocumentID
+	more := true
+	for more {
+		var documents []docgraph.Document
+		documents, more, err = reader.GetDocumentRange(ctx, gc.Contract, lowerBound, syncBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("cannot get documents from ID: %v %v", lowerBound, err)
+		}
+		if len(documents) == 0 {
+			break
+		}
+
+		err = gc.PutDocuments(documents...)
+		if err != nil {
+			return result, err
+		}
+		result.Documents += len(documents)
+
+		for _, document := range documents {
+			added, err := gc.syncEdgesOf(ctx, reader, document)
+			if err != nil {
+				return result, err
+			}
+			result.Edges += added
+		}
+		lastDocumentID = documents[len(documents)-1].ID
+		lowerBound = lastDocumentID + 1
+	}
+
+	if result.Documents > 0 {
+		err = gc.markDocumentID(lastDocumentID)
+		if err != nil {
+			return result, err
+		}
+	}
+	err = gc.markSyncedNow(ctx, reader)
+	if err != nil {
+		return result, err
+	}
+
+	zlog.Debug("synced graph cache", zap.Int("documents", result.Documents), zap.Int("edges", result.Edges))
+	return result, nil
+}
+
+// syncEdgesOf adds the edges from and to the document that are not cached, returning how many
+func (gc *GraphCache) syncEdgesOf(ctx context.Context, reader chain.ChainReader, document docgraph.Document) (int, error) {
+	from, err := reader.GetEdgesFromDocument(ctx, gc.Contract, document)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get edges from document: %v %v", document.Hash.String(), err)
+	}
+	to, err := reader.GetEdgesToDocument(ctx, gc.Contract, document)
+	if err != nil {
+		return 0, fmt.Errorf("cannot get edges to document: %v %v", document.Hash.String(), err)
+	}
+
+	var added []docgraph.Edge
+	err = gc.db.View(func(tx *bolt.Tx) error {
+		cached := tx.Bucket(edgesBucket)
+		seen := make(map[uint64]bool)
+		for _, edge := range append(from, to...) {
+			if !seen[edge.ID] && cached.Get(uint64Key(edge.ID)) == nil {
+				added = append(added, edge)
+			}
+			seen[edge.ID] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("cannot read cached edges: %v", err)
+	}
+	return len(added), gc.PutEdges(added...)
+}
+
+// Reconcile brings the cache in line with the chain by reading the document and edge tables in
+// full: documents and edges that are not cached, or whose ID changed, are added, and those no
+// longer on chain are removed.
+func (gc *GraphCache) Reconcile(ctx context.Context, reader chain.ChainReader) (SyncResult, error) {
+	var result SyncResult
+
+	cachedDocuments, cachedEdges, err := gc.cachedKeys()
+	if err != nil {
+		return result, err
+	}
+
+	var lowerBound uint64
+	more := true
+	for more {
+		var documents []docgraph.Document
+		documents, more, err = reader.GetDocumentRange(ctx, gc.Contract, lowerBound, syncBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("cannot get documents from ID: %v %v", lowerBound, err)
+		}
+		if len(documents) == 0 {
+			break
+		}
+
+		var changed []docgraph.Document
+		for _, document := range documents {
+			hash := document.Hash.String()
+			if id, found := cachedDocuments[hash]; !found || id != document.ID {
+				changed = append(changed, document)
+			}
+			delete(cachedDocuments, hash)
+		}
+		err = gc.PutDocuments(changed...)
+		if err != nil {
+			return result, err
+		}
+		result.Documents += len(changed)
+		lowerBound = documents[len(documents)-1].ID + 1
 	}
 
-	for _, document := range documents {
+	lowerBound = 0
+	more = true
+	for more {
+		var edges []docgraph.Edge
+		edges, more, err = reader.GetEdgeRange(ctx, gc.Contract, lowerBound, syncBatchSize)
+		if err != nil {
+			return result, fmt.Errorf("cannot get edges from ID: %v %v", lowerBound, err)
+		}
+		if len(edges) == 0 {
+			break
+		}
 
-		docType, err := document.GetType()
+		var added []docgraph.Edge
+		for _, edge := range edges {
+			if !cachedEdges[edge.ID] {
+				added = append(added, edge)
+			}
+			delete(cachedEdges, edge.ID)
+		}
+		err = gc.PutEdges(added...)
 		if err != nil {
-			return nil, fmt.Errorf("document with invalid or missing type: %v %v", document.Hash.String(), err)
+			return result, err
 		}
-		gCache.DocsByType[string(docType)] = append(gCache.DocsByType[string(docType)], document.Hash.String())
-		gCache.Cache.Set(document.Hash.String()[:5], document.Hash.String(), cache.DefaultExpiration)
-		gCache.Cache.Set(document.Hash.String(), document, cache.DefaultExpiration)
+		result.Edges += len(added)
+		lowerBound = edges[len(edges)-1].ID + 1
 	}
-	gCache.Cache.Set("DocsByType", gCache.DocsByType, cache.DefaultExpiration)
 
-	edges, err := docgraph.GetAllEdges(ctx, api, contract)
+	// whatever was cached but not seen on chain has been erased since the last reconcile
+	var removedDocuments []string
+	for hash := range cachedDocuments {
+		removedDocuments = append(removedDocuments, hash)
+	}
+	err = gc.DeleteDocuments(removedDocuments...)
 	if err != nil {
-		return nil, fmt.Errorf("cannot get all edges: %v", err)
+		return result, err
 	}
+	result.RemovedDocuments = len(removedDocuments)
 
-	for _, edge := range edges {
-		gCache.Cache.Set(strconv.Itoa(int(edge.ID)), edge, cache.DefaultExpiration)
+	var removedEdges []uint64
+	for id := range cachedEdges {
+		removedEdges = append(removedEdges, id)
 	}
+	err = gc.DeleteEdges(removedEdges...)
+	if err != nil {
+		return result, err
+	}
+	result.RemovedEdges = len(removedEdges)
 
-	err = gCache.Cache.SaveFile(".graph.cache")
+	err = gc.MarkReconciled(time.Now())
+	if err != nil {
+		return result, err
+	}
+	err = gc.markSyncedNow(ctx, reader)
 	if err != nil {
-		return nil, fmt.Errorf("cannot save cache to file .graph.cache: %v", err)
+		return result, err
 	}
 
-	return gCache, nil
+	zlog.Debug("reconciled graph cache", zap.Int("documents", result.Documents), zap.Int("edges", result.Edges),
+		zap.Int("removed-documents", result.RemovedDocuments), zap.Int("removed-edges", result.RemovedEdges))
+	return result, nil
 }
 
-func Get(ctx context.Context, api *eos.API, contract eos.AccountName, hash string) (docgraph.Document, error) {
+// reconcileInterval returns how long the cache is synced incrementally before it is reconciled
+func reconcileInterval() time.Duration {
+	if interval := viper.GetDuration("GraphCacheReconcileInterval"); interval > 0 {
+		return interval
+	}
+	return defaultReconcileInterval
+}
 
-	gc, err := GetCache(ctx, api, contract)
+// syncMarks are the document watermark and the time of the last reconcile
+type syncMarks struct {
+	lastDocumentID uint64
+	hasDocumentID  bool
+	lastReconcile  time.Time
+}
+
+func (gc *GraphCache) syncMarks() (syncMarks, error) {
+	var marks syncMarks
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if id := meta.Get(lastDocumentIDKey); id != nil {
+			marks.lastDocumentID = keyUint64(id)
+			marks.hasDocumentID = true
+		}
+		if reconcileTime := meta.Get(lastReconcileTimeKey); reconcileTime != nil {
+			marks.lastReconcile, _ = time.Parse(time.RFC3339, string(reconcileTime))
+		}
+		return nil
+	})
 	if err != nil {
-		return docgraph.Document{}, fmt.Errorf("cannot get cache: %v", err)
+		return syncMarks{}, fmt.Errorf("cannot read sync marks: %v", err)
 	}
+	return marks, nil
+}
 
-	cachedItem, found := gc.Cache.Get(hash)
-	if !found {
-		zlog.Debug("document is not found in cache; oading from blockchain", zap.String("hash", hash))
+// markDocumentID records the ID of the last document synced
+func (gc *GraphCache) markDocumentID(id uint64) error {
+	err := gc.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(metaBucket).Put(lastDocumentIDKey, uint64Key(id))
+	})
+	if err != nil {
+		return fmt.Errorf("cannot record document watermark: %v", err)
+	}
+	return nil
+}
+
+// MarkReconciled records when the cache was last brought in line with the whole chain, and sets
+// the document watermark to the highest cached document ID
+func (gc *GraphCache) MarkReconciled(reconcileTime time.Time) error {
+	err := gc.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		id, _ := tx.Bucket(documentIDsBucket).Cursor().Last()
+		if id == nil {
+			if err := meta.Delete(lastDocumentIDKey); err != nil {
+				return err
+			}
+		} else if err := meta.Put(lastDocumentIDKey, id); err != nil {
+			return err
+		}
+		return meta.Put(lastReconcileTimeKey, []byte(reconcileTime.UTC().Format(time.RFC3339)))
+	})
+	if err != nil {
+		return fmt.Errorf("cannot record reconcile time: %v", err)
+	}
+	return nil
+}
 
-		loadedDoc, err := docgraph.LoadDocument(ctx, api, contract, hash)
+// markSyncedNow records the sync time and, when the chain info can be read, the head block
+func (gc *GraphCache) markSyncedNow(ctx context.Context, reader chain.ChainReader) error {
+	var headBlock uint32
+	info, err := reader.GetInfo(ctx)
+	if err != nil {
+		zlog.Debug("cannot get chain info, sync block is not recorded", zap.Error(err))
+	} else {
+		headBlock = info.HeadBlockNum
+	}
+	return gc.MarkSynced(time.Now(), headBlock)
+}
+
+// MarkSynced records when the cache was last synced and, when known, the head block at that time
+func (gc *GraphCache) MarkSynced(syncTime time.Time, headBlock uint32) error {
+	err := gc.db.Update(func(tx *bolt.Tx) error {
+		meta := tx.Bucket(metaBucket)
+		if err := meta.Put(lastSyncTimeKey, []byte(syncTime.UTC().Format(time.RFC3339))); err != nil {
+			return err
+		}
+		if headBlock == 0 {
+			return nil
+		}
+		return meta.Put(lastSyncBlockKey, uint64Key(uint64(headBlock)))
+	})
+	if err != nil {
+		return fmt.Errorf("cannot record sync time: %v", err)
+	}
+	return nil
+}
+
+// cachedKeys returns the ID of each cached document by hash, and the set of cached edge IDs
+func (gc *GraphCache) cachedKeys() (map[string]uint64, map[uint64]bool, error) {
+	documents := make(map[string]uint64)
+	edges := make(map[uint64]bool)
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		err := tx.Bucket(documentIDsBucket).ForEach(func(id, hash []byte) error {
+			documents[string(hash)] = keyUint64(id)
+			return nil
+		})
 		if err != nil {
-			return docgraph.Document{}, fmt.Errorf("unable to load document directly from blockchain: %v %v", hash, err)
+			return err
+		}
+		return tx.Bucket(edgesBucket).ForEach(func(id, data []byte) error {
+			edges[keyUint64(id)] = true
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cannot read cached document hashes and edge IDs: %v", err)
+	}
+	return documents, edges, nil
+}
+
+// PutDocuments adds documents to the cache and its indexes, replacing any cached document with
+// the same hash
+func (gc *GraphCache) PutDocuments(documents ...docgraph.Document) error {
+	if len(documents) == 0 {
+		return nil
+	}
+	return gc.db.Update(func(tx *bolt.Tx) error {
+		for _, document := range documents {
+			data, err := json.Marshal(document)
+			if err != nil {
+				return fmt.Errorf("cannot marshal document: %v %v", document.Hash.String(), err)
+			}
+
+			hash := []byte(document.Hash.String())
+			if err := deleteDocument(tx, hash); err != nil {
+				return err
+			}
+			if err := tx.Bucket(documentsBucket).Put(hash, data); err != nil {
+				return err
+			}
+			if err := tx.Bucket(documentIDsBucket).Put(uint64Key(document.ID), hash); err != nil {
+				return err
+			}
+			for index, key := range documentIndexKeys(document) {
+				if err := tx.Bucket([]byte(index)).Put(key, hash); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// DeleteDocuments removes the documents with the hashes from the cache and its indexes
+func (gc *GraphCache) DeleteDocuments(hashes ...string) error {
+	if len(hashes) == 0 {
+		return nil
+	}
+	return gc.db.Update(func(tx *bolt.Tx) error {
+		for _, hash := range hashes {
+			if err := deleteDocument(tx, []byte(hash)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteDocument removes a cached document and the index entries made from its stored copy
+func deleteDocument(tx *bolt.Tx, hash []byte) error {
+	data := tx.Bucket(documentsBucket).Get(hash)
+	if data == nil {
+		return nil
+	}
+	var document docgraph.Document
+	if err := json.Unmarshal(data, &document); err != nil {
+		return fmt.Errorf("cannot unmarshal document: %v %v", string(hash), err)
+	}
+
+	ids := tx.Bucket(documentIDsBucket)
+	if bytes.Equal(ids.Get(uint64Key(document.ID)), hash) {
+		if err := ids.Delete(uint64Key(document.ID)); err != nil {
+			return err
 		}
-		return loadedDoc, nil
+	}
+	for index, key := range documentIndexKeys(document) {
+		if err := tx.Bucket([]byte(index)).Delete(key); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(documentsBucket).Delete(hash)
+}
+
+// documentIndexKeys returns the key of the document in each index by creator, label and type
+func documentIndexKeys(document docgraph.Document) map[string][]byte {
+	hash := document.Hash.String()
+	keys := map[string][]byte{
+		string(creatorsBucket): indexKey(string(document.Creator), hash),
+	}
+	if label := document.GetNodeLabel(); label != "" {
+		keys[string(labelsBucket)] = indexKey(strings.ToLower(label), hash)
+	}
+	docType, err := document.GetType()
+	if err != nil {
+		zlog.Debug("document with invalid or missing type is not indexed by type", zap.String("hash", hash))
 	} else {
-		zlog.Debug("pass through key found, reading 2nd level Document from cache", zap.String("hash", hash))
-		switch x := cachedItem.(type) {
-		case docgraph.Document:
-			return x, nil
-		case string:
-			cachedDocument, found := gc.Cache.Get(x)
-			if !found {
-				zlog.Debug("2nd level Document is not found in cache; loading from blockchain", zap.String("x", x))
-
-				loadedDoc, err := docgraph.LoadDocument(ctx, api, contract, x)
-				if err != nil {
-					return docgraph.Document{}, fmt.Errorf("unable to load 2nd level document directly from blockchain: %v %v", hash, err)
+		keys[string(typesBucket)] = indexKey(string(docType), hash)
+	}
+	return keys
+}
+
+// PutEdges adds edges to the cache and its indexes, replacing any cached edge with the same ID
+func (gc *GraphCache) PutEdges(edges ...docgraph.Edge) error {
+	if len(edges) == 0 {
+		return nil
+	}
+	return gc.db.Update(func(tx *bolt.Tx) error {
+		for _, edge := range edges {
+			data, err := json.Marshal(edge)
+			if err != nil {
+				return fmt.Errorf("cannot marshal edge: %v %v", edge.ID, err)
+			}
+
+			id := uint64Key(edge.ID)
+			if err := deleteEdge(tx, id); err != nil {
+				return err
+			}
+			if err := tx.Bucket(edgesBucket).Put(id, data); err != nil {
+				return err
+			}
+			for index, key := range edgeIndexKeys(edge) {
+				if err := tx.Bucket([]byte(index)).Put(key, id); err != nil {
+					return err
 				}
-				return loadedDoc, nil
 			}
-			return cachedDocument.(docgraph.Document), nil
-		default:
-			return docgraph.Document{}, fmt.Errorf("item in cache is neither a Document nor a string: %v %v", hash, err)
 		}
+		return nil
+	})
+}
+
+// DeleteEdges removes the edges with the IDs from the cache and its indexes
+func (gc *GraphCache) DeleteEdges(ids ...uint64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return gc.db.Update(func(tx *bolt.Tx) error {
+		for _, id := range ids {
+			if err := deleteEdge(tx, uint64Key(id)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// deleteEdge removes a cached edge and the index entries made from its stored copy
+func deleteEdge(tx *bolt.Tx, id []byte) error {
+	data := tx.Bucket(edgesBucket).Get(id)
+	if data == nil {
+		return nil
+	}
+	var edge docgraph.Edge
+	if err := json.Unmarshal(data, &edge); err != nil {
+		return fmt.Errorf("cannot unmarshal edge: %v %v", keyUint64(id), err)
+	}
+
+	for index, key := range edgeIndexKeys(edge) {
+		if err := tx.Bucket([]byte(index)).Delete(key); err != nil {
+			return err
+		}
+	}
+	return tx.Bucket(edgesBucket).Delete(id)
+}
+
+// edgeIndexKeys returns the key of the edge in each index by from node, to node and edge name
+func edgeIndexKeys(edge docgraph.Edge) map[string][]byte {
+	id := string(uint64Key(edge.ID))
+	return map[string][]byte{
+		string(edgesFromBucket): indexKey(edge.FromNode.String(), string(edge.EdgeName), id),
+		string(edgesToBucket):   indexKey(edge.ToNode.String(), string(edge.EdgeName), id),
+		string(edgeNamesBucket): indexKey(string(edge.EdgeName), id),
+	}
+}
+
+// GetDocument returns the cached document with the hash, and whether it was found
+func (gc *GraphCache) GetDocument(hash string) (docgraph.Document, bool, error) {
+	var document docgraph.Document
+	var found bool
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		data := tx.Bucket(documentsBucket).Get([]byte(hash))
+		if data == nil {
+			return nil
+		}
+		found = true
+		return json.Unmarshal(data, &document)
+	})
+	if err != nil {
+		return docgraph.Document{}, false, fmt.Errorf("cannot read document from cache: %v %v", hash, err)
+	}
+	return document, found, nil
+}
+
+// GetDocumentByID returns the cached document with the ID, and whether it was found
+func (gc *GraphCache) GetDocumentByID(id uint64) (docgraph.Document, bool, error) {
+	var hash []byte
+	gc.db.View(func(tx *bolt.Tx) error {
+		hash = append(hash, tx.Bucket(documentIDsBucket).Get(uint64Key(id))...)
+		return nil
+	})
+	if len(hash) == 0 {
+		return docgraph.Document{}, false, nil
+	}
+	return gc.GetDocument(string(hash))
+}
+
+// FindDocumentHashes returns the hashes of the cached documents that start with prefix
+func (gc *GraphCache) FindDocumentHashes(prefix string) ([]string, error) {
+	var hashes []string
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(documentsBucket).Cursor()
+		for k, _ := c.Seek([]byte(prefix)); k != nil && bytes.HasPrefix(k, []byte(prefix)); k, _ = c.Next() {
+			hashes = append(hashes, string(k))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot search cached document hashes: %v %v", prefix, err)
+	}
+	return hashes, nil
+}
+
+// GetAllDocuments returns every cached document ordered by ID
+func (gc *GraphCache) GetAllDocuments() ([]docgraph.Document, error) {
+	var documents []docgraph.Document
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		docs := tx.Bucket(documentsBucket)
+		return tx.Bucket(documentIDsBucket).ForEach(func(k, hash []byte) error {
+			var document docgraph.Document
+			if err := json.Unmarshal(docs.Get(hash), &document); err != nil {
+				return fmt.Errorf("cannot unmarshal document: %v %v", string(hash), err)
+			}
+			documents = append(documents, document)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read documents from cache: %v", err)
+	}
+	return documents, nil
+}
+
+// GetDocumentsByLabel returns the cached documents whose node label matches, ignoring case
+func (gc *GraphCache) GetDocumentsByLabel(label string) ([]docgraph.Document, error) {
+	return gc.getIndexedDocuments(labelsBucket, strings.ToLower(label))
+}
+
+// GetDocumentsByType returns the cached documents with the type
+func (gc *GraphCache) GetDocumentsByType(docType eos.Name) ([]docgraph.Document, error) {
+	return gc.getIndexedDocuments(typesBucket, string(docType))
+}
+
+// GetDocumentsByCreator returns the cached documents created by the account
+func (gc *GraphCache) GetDocumentsByCreator(creator eos.AccountName) ([]docgraph.Document, error) {
+	return gc.getIndexedDocuments(creatorsBucket, string(creator))
+}
+
+func (gc *GraphCache) getIndexedDocuments(index []byte, value string) ([]docgraph.Document, error) {
+	var documents []docgraph.Document
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		docs := tx.Bucket(documentsBucket)
+		prefix := indexKey(value, "")
+		c := tx.Bucket(index).Cursor()
+		for k, hash := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, hash = c.Next() {
+			var document docgraph.Document
+			if err := json.Unmarshal(docs.Get(hash), &document); err != nil {
+				return fmt.Errorf("cannot unmarshal document: %v %v", string(hash), err)
+			}
+			documents = append(documents, document)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %v index from cache: %v %v", string(index), value, err)
+	}
+	sortDocuments(documents)
+	return documents, nil
+}
+
+// GetAllEdges returns every cached edge ordered by ID
+func (gc *GraphCache) GetAllEdges() ([]docgraph.Edge, error) {
+	var edges []docgraph.Edge
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(edgesBucket).ForEach(func(k, data []byte) error {
+			var edge docgraph.Edge
+			if err := json.Unmarshal(data, &edge); err != nil {
+				return fmt.Errorf("cannot unmarshal edge: %v %v", keyUint64(k), err)
+			}
+			edges = append(edges, edge)
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read edges from cache: %v", err)
+	}
+	return edges, nil
+}
+
+// GetEdgesFrom returns the cached edges from the document, optionally only those with edgeName
+func (gc *GraphCache) GetEdgesFrom(hash string, edgeName eos.Name) ([]docgraph.Edge, error) {
+	return gc.getIndexedEdges(edgesFromBucket, hash, string(edgeName))
+}
+
+// GetEdgesTo returns the cached edges to the document, optionally only those with edgeName
+func (gc *GraphCache) GetEdgesTo(hash string, edgeName eos.Name) ([]docgraph.Edge, error) {
+	return gc.getIndexedEdges(edgesToBucket, hash, string(edgeName))
+}
+
+// GetEdgesByName returns the cached edges with the edge name
+func (gc *GraphCache) GetEdgesByName(edgeName eos.Name) ([]docgraph.Edge, error) {
+	return gc.getIndexedEdges(edgeNamesBucket, string(edgeName))
+}
+
+func (gc *GraphCache) getIndexedEdges(index []byte, values ...string) ([]docgraph.Edge, error) {
+	var nonEmpty []string
+	for _, value := range values {
+		if value != "" {
+			nonEmpty = append(nonEmpty, value)
+		}
+	}
+
+	var edges []docgraph.Edge
+	err := gc.db.View(func(tx *bolt.Tx) error {
+		all := tx.Bucket(edgesBucket)
+		prefix := indexKey(append(nonEmpty, "")...)
+		c := tx.Bucket(index).Cursor()
+		for k, id := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, id = c.Next() {
+			var edge docgraph.Edge
+			if err := json.Unmarshal(all.Get(id), &edge); err != nil {
+				return fmt.Errorf("cannot unmarshal edge: %v %v", keyUint64(id), err)
+			}
+			edges = append(edges, edge)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("cannot read %v index from cache: %v %v", string(index), strings.Join(nonEmpty, " "), err)
+	}
+	sortEdges(edges)
+	return edges, nil
+}
+
+// syncedCaches are the cache files already synced by this process, so that a command that opens
+// the cache more than once syncs it once
+var syncedCaches = make(map[string]bool)
+
+// GetCache opens the graph cache for the selected network and contract and, the first time in a
+// run, brings it up to date
+func GetCache(ctx context.Context, reader chain.ChainReader, contract eos.AccountName) (*GraphCache, error) {
+	fileName, err := DefaultCacheFile(viper.GetString("Network"), contract)
+	if err != nil {
+		return nil, err
 	}
 
+	gc, err := OpenGraphCache(fileName, contract)
+	if err != nil {
+		return nil, err
+	}
+	if syncedCaches[fileName] {
+		return gc, nil
+	}
+
+	_, err = gc.Sync(ctx, reader)
+	if err != nil {
+		gc.Close()
+		return nil, fmt.Errorf("cannot sync graph cache: %v", err)
+	}
+	syncedCaches[fileName] = true
+	return gc, nil
+}
+
+// FreshCache clears the graph cache for the selected network and contract and rebuilds it from the chain
+func FreshCache(ctx context.Context, reader chain.ChainReader, contract eos.AccountName) (*GraphCache, error) {
+	fileName, err := DefaultCacheFile(viper.GetString("Network"), contract)
+	if err != nil {
+		return nil, err
+	}
+
+	gc, err := OpenGraphCache(fileName, contract)
+	if err != nil {
+		return nil, err
+	}
+
+	err = gc.Clear()
+	if err != nil {
+		gc.Close()
+		return nil, fmt.Errorf("cannot clear graph cache: %v", err)
+	}
+
+	_, err = gc.Reconcile(ctx, reader)
+	if err != nil {
+		gc.Close()
+		return nil, fmt.Errorf("cannot sync graph cache: %v", err)
+	}
+	syncedCaches[fileName] = true
+	return gc, nil
+}
+
+// Get returns the document with the hash from the graph cache, falling back to the blockchain
+// when it is not cached
+func Get(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, hash string) (docgraph.Document, error) {
+
+	gc, err := GetCache(ctx, reader, contract)
+	if err != nil {
+		return docgraph.Document{}, fmt.Errorf("cannot get cache: %v", err)
+	}
+	defer gc.Close()
+
+	document, found, err := gc.GetDocument(hash)
+	if err != nil {
+		return docgraph.Document{}, err
+	}
+	if found {
+		return document, nil
+	}
+
+	zlog.Debug("document is not found in cache; loading from blockchain", zap.String("hash", hash))
+	loadedDoc, err := reader.LoadDocument(ctx, contract, hash)
+	if err != nil {
+		return docgraph.Document{}, fmt.Errorf("unable to load document directly from blockchain: %v %v", hash, err)
+	}
+	return loadedDoc, nil
+}
+
+func sortDocuments(documents []docgraph.Document) {
+	sort.Slice(documents, func(i, j int) bool {
+		return documents[i].ID < documents[j].ID
+	})
+}
+
+func sortEdges(edges []docgraph.Edge) {
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i].ID < edges[j].ID
+	})
+}
+
+func uint64Key(value uint64) []byte {
+	key := make([]byte, 8)
+	binary.BigEndian.PutUint64(key, value)
+	return key
+}
+
+func keyUint64(key []byte) uint64 {
+	if len(key) != 8 {
+		return 0
+	}
+	return binary.BigEndian.Uint64(key)
+}
+
+// indexKey joins the parts with a separator that cannot appear in names or hashes,
+// so that prefix scans on the leading parts are exact
+func indexKey(parts ...string) []byte {
+	return []byte(strings.Join(parts, "\x00"))
+}
+
+// indexValue returns the leading part of an index key, i.e. the indexed value
+func indexValue(key []byte) string {
+	if i := bytes.IndexByte(key, 0); i >= 0 {
+		return string(key[:i])
+	}
+	return string(key)
 }
@@ -0,0 +1,273 @@
+package util
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	eos "github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"github.com/spf13/viper"
+)
+
+const testContract = eos.AccountName("dao.hypha")
+
+func testDocument(id uint64, docType, label string) docgraph.Document {
+	value := func(typeName string, impl interface{}) *docgraph.FlexValue {
+		return &docgraph.FlexValue{BaseVariant: eos.BaseVariant{
+			TypeID: docgraph.GetVariants().TypeID(typeName),
+			Impl:   impl,
+		}}
+	}
+	groups := []docgraph.ContentGroup{{
+		{Label: "content_group_label", Value: value("string", "system")},
+		{Label: "type", Value: value("name", eos.Name(docType))},
+		{Label: "node_label", Value: value("string", label)},
+	}}
+	return docgraph.Document{
+		ID:            id,
+		Hash:          HashContentGroups(groups),
+		Creator:       "johnnyhypha1",
+		ContentGroups: groups,
+	}
+}
+
+func testEdge(id uint64, from, to docgraph.Document, name eos.Name) docgraph.Edge {
+	return docgraph.Edge{ID: id, FromNode: from.Hash, ToNode: to.Hash, EdgeName: name}
+}
+
+func openTestCache(t *testing.T) *GraphCache {
+	t.Helper()
+	gc, err := OpenGraphCache(filepath.Join(t.TempDir(), "cache.db"), testContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { gc.Close() })
+	return gc
+}
+
+func TestReconcileAddsAndEvicts(t *testing.T) {
+	ctx := context.Background()
+	dho := testDocument(1, "dho", "Hypha DHO")
+	role := testDocument(2, "role", "Designer")
+	assignment := testDocument(3, "assignment", "Designer: erin")
+
+	fixture := chain.NewFixture()
+	fixture.Documents[testContract] = []docgraph.Document{dho, role, assignment}
+	fixture.Edges[testContract] = []docgraph.Edge{
+		testEdge(9000000000000000000, dho, role, "role"),
+		testEdge(5000000000000000000, dho, assignment, "assignment"),
+		testEdge(7000000000000000000, assignment, role, "role"),
+	}
+	reader := chain.NewFixtureReaderFromFixture(fixture)
+	gc := openTestCache(t)
+
+	result, err := gc.Reconcile(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Documents: 3, Edges: 3}) {
+		t.Fatalf("first reconcile: got %+v", result)
+	}
+
+	result, err = gc.Reconcile(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{}) {
+		t.Fatalf("reconcile without changes: got %+v", result)
+	}
+
+	// the assignment is erased with its edges, and an edge with an ID below every cached one is added
+	fixture.Documents[testContract] = []docgraph.Document{dho, role}
+	fixture.Edges[testContract] = []docgraph.Edge{
+		testEdge(9000000000000000000, dho, role, "role"),
+		testEdge(10, role, dho, "dao"),
+	}
+
+	result, err = gc.Reconcile(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Edges: 1, RemovedDocuments: 1, RemovedEdges: 2}) {
+		t.Fatalf("reconcile after erase: got %+v", result)
+	}
+
+	if _, found, _ := gc.GetDocument(assignment.Hash.String()); found {
+		t.Error("erased document is still cached")
+	}
+	if _, found, _ := gc.GetDocumentByID(assignment.ID); found {
+		t.Error("erased document is still indexed by ID")
+	}
+	for _, check := range []struct {
+		name  string
+		found func() ([]docgraph.Document, error)
+	}{
+		{"type", func() ([]docgraph.Document, error) { return gc.GetDocumentsByType("assignment") }},
+		{"label", func() ([]docgraph.Document, error) { return gc.GetDocumentsByLabel("designer: erin") }},
+	} {
+		documents, err := check.found()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(documents) != 0 {
+			t.Errorf("erased document is still indexed by %v", check.name)
+		}
+	}
+
+	for _, check := range []struct {
+		name  string
+		edges func() ([]docgraph.Edge, error)
+		want  int
+	}{
+		{"from dho", func() ([]docgraph.Edge, error) { return gc.GetEdgesFrom(dho.Hash.String(), "") }, 1},
+		{"to role", func() ([]docgraph.Edge, error) { return gc.GetEdgesTo(role.Hash.String(), "") }, 1},
+		{"named role", func() ([]docgraph.Edge, error) { return gc.GetEdgesByName("role") }, 1},
+		{"named dao", func() ([]docgraph.Edge, error) { return gc.GetEdgesByName("dao") }, 1},
+		{"named assignment", func() ([]docgraph.Edge, error) { return gc.GetEdgesByName("assignment") }, 0},
+	} {
+		edges, err := check.edges()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(edges) != check.want {
+			t.Errorf("edges %v: got %v, want %v", check.name, len(edges), check.want)
+		}
+	}
+
+	status, err := gc.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if status.Documents != 2 || status.Edges != 2 || status.DocumentsByType["assignment"] != 0 {
+		t.Errorf("status after erase: got %+v", status)
+	}
+}
+
+func TestPutDocumentsReplacesIndexes(t *testing.T) {
+	gc := openTestCache(t)
+	role := testDocument(2, "role", "Designer")
+	if err := gc.PutDocuments(role); err != nil {
+		t.Fatal(err)
+	}
+
+	// the same content written again under a new ID, as after a contract migration
+	moved := role
+	moved.ID = 20
+	if err := gc.PutDocuments(moved); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, found, _ := gc.GetDocumentByID(2); found {
+		t.Error("document is still indexed by its old ID")
+	}
+	if document, found, _ := gc.GetDocumentByID(20); !found || document.ID != 20 {
+		t.Errorf("document is not indexed by its new ID: found %v", found)
+	}
+	documents, err := gc.GetDocumentsByType("role")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(documents) != 1 {
+		t.Errorf("documents by type: got %v, want 1", len(documents))
+	}
+}
+
+// countingReader counts the table reads a sync makes
+type countingReader struct {
+	*chain.FixtureReader
+	documentRanges int
+	edgeRanges     int
+}
+
+func (r *countingReader) GetDocumentRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Document, bool, error) {
+	r.documentRanges++
+	return r.FixtureReader.GetDocumentRange(ctx, contract, lowerBound, limit)
+}
+
+func (r *countingReader) GetEdgeRange(ctx context.Context, contract eos.AccountName, lowerBound uint64, limit int) ([]docgraph.Edge, bool, error) {
+	r.edgeRanges++
+	return r.FixtureReader.GetEdgeRange(ctx, contract, lowerBound, limit)
+}
+
+func TestSyncFetchesDocumentsAboveTheWatermark(t *testing.T) {
+	ctx := context.Background()
+	dho := testDocument(1, "dho", "Hypha DHO")
+	role := testDocument(2, "role", "Designer")
+	assignment := testDocument(3, "assignment", "Designer: erin")
+
+	fixture := chain.NewFixture()
+	fixture.Documents[testContract] = []docgraph.Document{dho, role}
+	fixture.Edges[testContract] = []docgraph.Edge{testEdge(900, dho, role, "role")}
+	reader := &countingReader{FixtureReader: chain.NewFixtureReaderFromFixture(fixture)}
+	gc := openTestCache(t)
+
+	// a cache that was never reconciled is reconciled by its first sync
+	result, err := gc.Sync(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Documents: 2, Edges: 1}) || reader.edgeRanges != 1 {
+		t.Fatalf("first sync: got %+v after %v edge range reads", result, reader.edgeRanges)
+	}
+
+	// a new document with an edge to it, an edge between older documents, and an erased edge
+	fixture.Documents[testContract] = []docgraph.Document{dho, role, assignment}
+	fixture.Edges[testContract] = []docgraph.Edge{
+		testEdge(500, assignment, role, "role"),
+		testEdge(700, role, dho, "dao"),
+	}
+	reader.documentRanges, reader.edgeRanges = 0, 0
+
+	result, err = gc.Sync(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Documents: 1, Edges: 1}) {
+		t.Fatalf("incremental sync: got %+v", result)
+	}
+	if reader.edgeRanges != 0 || reader.documentRanges != 1 {
+		t.Errorf("incremental sync read %v document ranges and %v edge ranges, want 1 and 0", reader.documentRanges, reader.edgeRanges)
+	}
+	status, err := gc.Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !status.HasDocumentID || status.LastDocumentID != 3 || status.Edges != 2 {
+		t.Errorf("status after incremental sync: got %+v", status)
+	}
+
+	// once the reconcile interval has passed, a sync reconciles the whole cache
+	viper.Set("GraphCacheReconcileInterval", time.Nanosecond)
+	defer viper.Set("GraphCacheReconcileInterval", 0)
+	result, err = gc.Sync(ctx, reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != (SyncResult{Edges: 1, RemovedEdges: 1}) {
+		t.Fatalf("sync after the reconcile interval: got %+v", result)
+	}
+}
+
+func TestGetCacheSyncsOncePerRun(t *testing.T) {
+	ctx := context.Background()
+	viper.Set("GraphCacheFile", filepath.Join(t.TempDir(), "cache.db"))
+	defer viper.Set("GraphCacheFile", "")
+
+	fixture := chain.NewFixture()
+	fixture.Documents[testContract] = []docgraph.Document{testDocument(1, "dho", "Hypha DHO")}
+	reader := &countingReader{FixtureReader: chain.NewFixtureReaderFromFixture(fixture)}
+
+	for i := 0; i < 3; i++ {
+		gc, err := GetCache(ctx, reader, testContract)
+		if err != nil {
+			t.Fatal(err)
+		}
+		gc.Close()
+	}
+	if reader.documentRanges != 1 {
+		t.Errorf("got %v document range reads over three opens, want 1", reader.documentRanges)
+	}
+}
@@ -0,0 +1,122 @@
+package util
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/chain"
+	"github.com/hypha-dao/document-graph/docgraph"
+	"go.uber.org/zap"
+)
+
+// MinPrefixLength is the shortest hash prefix accepted as a document reference
+const MinPrefixLength = 4
+
+var hexPattern = regexp.MustCompile("^[0-9a-fA-F]+$")
+
+// DocumentNotFoundError is returned when a reference does not match any document
+type DocumentNotFoundError struct {
+	Reference string
+}
+
+func (e *DocumentNotFoundError) Error() string {
+	return fmt.Sprintf("no document matches reference: %v", e.Reference)
+}
+
+// AmbiguousReferenceError is returned when a reference matches more than one document
+type AmbiguousReferenceError struct {
+	Reference  string
+	Candidates []docgraph.Document
+}
+
+func (e *AmbiguousReferenceError) Error() string {
+	candidates := make([]string, len(e.Candidates))
+	for i, candidate := range e.Candidates {
+		docType, _ := candidate.GetType()
+		candidates[i] = fmt.Sprintf("  %v  %-6v  %-12v  %v", candidate.Hash.String(), candidate.ID, docType, candidate.GetNodeLabel())
+	}
+	return fmt.Sprintf("reference %v is ambiguous, it matches %v documents:\n%v",
+		e.Reference, len(e.Candidates), strings.Join(candidates, "\n"))
+}
+
+// ResolveDocument finds the single cached document a reference points to. A reference is a full
+// hash, a unique hash prefix of at least MinPrefixLength characters, a document ID, or a node label.
+func (gc *GraphCache) ResolveDocument(reference string) (docgraph.Document, error) {
+	reference = strings.TrimSpace(reference)
+	if reference == "" {
+		return docgraph.Document{}, &DocumentNotFoundError{Reference: reference}
+	}
+
+	candidates := make(map[string]docgraph.Document)
+
+	if len(reference) >= MinPrefixLength && hexPattern.MatchString(reference) {
+		hashes, err := gc.FindDocumentHashes(strings.ToLower(reference))
+		if err != nil {
+			return docgraph.Document{}, err
+		}
+		for _, hash := range hashes {
+			document, found, err := gc.GetDocument(hash)
+			if err != nil {
+				return docgraph.Document{}, err
+			}
+			if found {
+				candidates[hash] = document
+			}
+		}
+	}
+
+	if id, err := strconv.ParseUint(reference, 10, 64); err == nil {
+		document, found, err := gc.GetDocumentByID(id)
+		if err != nil {
+			return docgraph.Document{}, err
+		}
+		if found {
+			candidates[document.Hash.String()] = document
+		}
+	}
+
+	labelled, err := gc.GetDocumentsByLabel(reference)
+	if err != nil {
+		return docgraph.Document{}, err
+	}
+	for _, document := range labelled {
+		candidates[document.Hash.String()] = document
+	}
+
+	switch len(candidates) {
+	case 0:
+		return docgraph.Document{}, &DocumentNotFoundError{Reference: reference}
+	case 1:
+		for _, document := range candidates {
+			return document, nil
+		}
+	}
+
+	var ambiguous []docgraph.Document
+	for _, document := range candidates {
+		ambiguous = append(ambiguous, document)
+	}
+	sortDocuments(ambiguous)
+	return docgraph.Document{}, &AmbiguousReferenceError{Reference: reference, Candidates: ambiguous}
+}
+
+// ResolveDocument syncs the graph cache and resolves the reference against it. A full hash that
+// is not cached is loaded from the blockchain.
+func ResolveDocument(ctx context.Context, reader chain.ChainReader, contract eos.AccountName, reference string) (docgraph.Document, error) {
+	gc, err := GetCache(ctx, reader, contract)
+	if err != nil {
+		return docgraph.Document{}, fmt.Errorf("cannot get cache: %v", err)
+	}
+	defer gc.Close()
+
+	document, err := gc.ResolveDocument(reference)
+	if _, notFound := err.(*DocumentNotFoundError); notFound && len(reference) == 64 && hexPattern.MatchString(reference) {
+		zlog.Debug("document is not found in cache; loading from blockchain", zap.String("hash", reference))
+		return reader.LoadDocument(ctx, contract, strings.ToLower(reference))
+	}
+	return document, err
+}
@@ -0,0 +1,45 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/backup"
+)
+
+// DocumentChangeTable is a simpleTable.Table object with the hashes of documents before and after a change
+func DocumentChangeTable(changes []backup.DocumentChange) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "ID"},
+			{Align: simpletable.AlignCenter, Text: "Node Label"},
+			{Align: simpletable.AlignCenter, Text: "Type"},
+			{Align: simpletable.AlignCenter, Text: "Hash Before"},
+			{Align: simpletable.AlignCenter, Text: "Hash After"},
+		},
+	}
+
+	for _, change := range changes {
+		nodeLabel := change.After.GetNodeLabel()
+		if nodeLabel == "" {
+			nodeLabel = "Unknown"
+		}
+		typeLabel := "Unknown"
+		if docType, err := change.After.GetType(); err == nil && docType != "" {
+			typeLabel = string(docType)
+		}
+
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(int(change.After.ID))},
+			{Align: simpletable.AlignRight, Text: nodeLabel},
+			{Align: simpletable.AlignRight, Text: typeLabel},
+			{Align: simpletable.AlignRight, Text: change.Before.Hash.String()},
+			{Align: simpletable.AlignRight, Text: change.After.Hash.String()},
+		}
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+
+	return table
+}
@@ -0,0 +1,70 @@
+package views
+
+import (
+	"sort"
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/util"
+)
+
+// CountTable is a simpleTable.Table object with a count per key, sorted by key
+func CountTable(keyHeader string, counts map[string]int) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: keyHeader},
+			{Align: simpletable.AlignCenter, Text: "Count"},
+		},
+	}
+
+	var keys []string
+	total := 0
+	for key, count := range counts {
+		keys = append(keys, key)
+		total += count
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: key},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(counts[key])},
+		}
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+
+	table.Footer = &simpletable.Footer{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: "Total"},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(total)},
+		},
+	}
+	return table
+}
+
+// CacheProblemTable is a simpleTable.Table object with graph cache integrity problems
+func CacheProblemTable(problems []util.CacheProblem) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Problem"},
+			{Align: simpletable.AlignCenter, Text: "ID"},
+			{Align: simpletable.AlignCenter, Text: "Hash"},
+			{Align: simpletable.AlignCenter, Text: "Detail"},
+		},
+	}
+
+	for _, problem := range problems {
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: problem.Kind},
+			{Align: simpletable.AlignRight, Text: strconv.FormatUint(problem.ID, 10)},
+			{Align: simpletable.AlignLeft, Text: problem.Hash},
+			{Align: simpletable.AlignLeft, Text: problem.Detail},
+		}
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+	return table
+}
@@ -0,0 +1,31 @@
+package views
+
+import (
+	"github.com/alexeyco/simpletable"
+	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+)
+
+// CompensationTable returns a table with the amount of each token an assignment pays per period
+// and in total
+func CompensationTable(c models.Compensation) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Token"},
+			{Align: simpletable.AlignCenter, Text: "Per Period"},
+			{Align: simpletable.AlignCenter, Text: "Total"},
+		},
+	}
+
+	for _, perPeriod := range []eos.Asset{c.HusdPerPeriod, c.HyphaPerPeriod, c.HvoicePerPeriod, c.SeedsPerPeriod} {
+		total := c.Total(perPeriod)
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: perPeriod.Symbol.Symbol},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&perPeriod, int(perPeriod.Symbol.Precision))},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&total, int(total.Symbol.Precision))},
+		})
+	}
+	return table
+}
@@ -0,0 +1,47 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/graph"
+	"github.com/hypha-dao/document-graph/docgraph"
+)
+
+// FieldTable is a simpleTable.Table object with documents and a column for each of the extra fields
+func FieldTable(docs []docgraph.Document, fields []string) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = docHeader()
+	for _, field := range fields {
+		table.Header.Cells = append(table.Header.Cells, &simpletable.Cell{Align: simpletable.AlignCenter, Text: field})
+	}
+
+	for i := range docs {
+		typeLabel := "Unknown"
+		if docType, found := graph.FieldValue(&docs[i], "type"); found {
+			typeLabel = graph.FormatValue(docType)
+		}
+
+		nodeLabel := "Unknown"
+		if label, found := graph.FieldValue(&docs[i], "label"); found {
+			nodeLabel = graph.FormatValue(label)
+		}
+
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(int(docs[i].ID))},
+			{Align: simpletable.AlignRight, Text: nodeLabel},
+			{Align: simpletable.AlignRight, Text: typeLabel},
+			{Align: simpletable.AlignRight, Text: docs[i].CreatedDate.Time.Format("2006 Jan 02 15:04:05")},
+			{Align: simpletable.AlignRight, Text: string(docs[i].Creator)},
+			{Align: simpletable.AlignRight, Text: docs[i].Hash.String()},
+		}
+		for _, field := range fields {
+			value, _ := graph.FieldValue(&docs[i], field)
+			r = append(r, &simpletable.Cell{Align: simpletable.AlignRight, Text: graph.FormatValue(value)})
+		}
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+
+	return table
+}
@@ -0,0 +1,37 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+)
+
+// ParticipationTable returns a table of the members' votes, participation rate and share of the
+// members' vote token
+func ParticipationTable(participations []models.Participation) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "Member"},
+			{Align: simpletable.AlignCenter, Text: "Votes Cast"},
+			{Align: simpletable.AlignCenter, Text: "Participation %"},
+			{Align: simpletable.AlignCenter, Text: "Vote Token"},
+			{Align: simpletable.AlignCenter, Text: "Power Share %"},
+		},
+	}
+
+	for index, p := range participations {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(index + 1)},
+			{Align: simpletable.AlignLeft, Text: string(p.Account)},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(p.Votes) + " / " + strconv.Itoa(p.Proposals)},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(p.Rate()*100, 'f', 1, 64)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&p.VoteTokenBalance, 2)},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(p.PowerShare*100, 'f', 2, 64)},
+		})
+	}
+	return table
+}
@@ -0,0 +1,163 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/eoscanada/eos-go"
+	"github.com/hypha-dao/daoctl/models"
+	"github.com/hypha-dao/daoctl/util"
+)
+
+// TallyTable returns a table with the number of votes and the vote power cast for each option of
+// the ballot, and each option's share of the vote power cast
+func TallyTable(ballot models.ProposalBallot) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Option"},
+			{Align: simpletable.AlignCenter, Text: "Votes"},
+			{Align: simpletable.AlignCenter, Text: "Vote Power"},
+			{Align: simpletable.AlignCenter, Text: "Share %"},
+		},
+	}
+
+	counts := make(map[string]int)
+	for _, vote := range ballot.Votes {
+		counts[vote.Vote]++
+	}
+	cast := ballot.Cast()
+
+	options := []struct {
+		name  string
+		power eos.Asset
+	}{
+		{models.VotePass, ballot.Tally.Pass},
+		{models.VoteFail, ballot.Tally.Fail},
+		{models.VoteAbstain, ballot.Tally.Abstain},
+	}
+	for _, option := range options {
+		share := "0"
+		if cast.Amount > 0 {
+			share = strconv.FormatFloat(float64(option.power.Amount)/float64(cast.Amount)*100, 'f', 1, 64)
+		}
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignLeft, Text: option.name},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(counts[option.name])},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&option.power, 2)},
+			{Align: simpletable.AlignRight, Text: share},
+		})
+	}
+
+	table.Footer = &simpletable.Footer{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: "Total"},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(len(ballot.Votes))},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&cast, 2)},
+			{},
+		},
+	}
+	return table
+}
+
+// ProposalVotesTable returns a table of the votes cast on a proposal
+func ProposalVotesTable(votes []models.ProposalVote) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "Voter"},
+			{Align: simpletable.AlignCenter, Text: "Vote"},
+			{Align: simpletable.AlignCenter, Text: "Vote Power"},
+			{Align: simpletable.AlignCenter, Text: "Date"},
+			{Align: simpletable.AlignCenter, Text: "Hash"},
+		},
+	}
+
+	for index, vote := range votes {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(index + 1)},
+			{Align: simpletable.AlignLeft, Text: string(vote.Voter)},
+			{Align: simpletable.AlignLeft, Text: vote.Vote},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&vote.VotePower, 2)},
+			{Align: simpletable.AlignRight, Text: vote.Date.Format("2006 Jan 02 15:04:05")},
+			{Align: simpletable.AlignCenter, Text: vote.Hash.String()[:5]},
+		})
+	}
+	return table
+}
+
+// ProposalTable returns a table of the ballots of proposals with their tally and whether they
+// would pass if voting closed now
+func ProposalTable(ballots []models.ProposalBallot) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "Type"},
+			{Align: simpletable.AlignCenter, Text: "Title"},
+			{Align: simpletable.AlignCenter, Text: "Proposer"},
+			{Align: simpletable.AlignCenter, Text: "Created"},
+			{Align: simpletable.AlignCenter, Text: "Vote End"},
+			{Align: simpletable.AlignCenter, Text: "Status"},
+			{Align: simpletable.AlignCenter, Text: "Pass"},
+			{Align: simpletable.AlignCenter, Text: "Fail"},
+			{Align: simpletable.AlignCenter, Text: "Abstain"},
+			{Align: simpletable.AlignCenter, Text: "Quorum"},
+			{Align: simpletable.AlignCenter, Text: "Passing"},
+			{Align: simpletable.AlignCenter, Text: "Hash"},
+		},
+	}
+
+	for index, ballot := range ballots {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(index + 1)},
+			{Align: simpletable.AlignLeft, Text: string(ballot.Type)},
+			{Align: simpletable.AlignLeft, Text: ballot.Title},
+			{Align: simpletable.AlignLeft, Text: string(ballot.Proposal.Creator)},
+			{Align: simpletable.AlignRight, Text: ballot.Start.Format("2006 Jan 02")},
+			{Align: simpletable.AlignRight, Text: ballot.End.Format("2006 Jan 02 15:04")},
+			{Align: simpletable.AlignLeft, Text: ballot.Status},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&ballot.Tally.Pass, 2)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&ballot.Tally.Fail, 2)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&ballot.Tally.Abstain, 2)},
+			{Align: simpletable.AlignCenter, Text: yesNo(ballot.QuorumMet())},
+			{Align: simpletable.AlignCenter, Text: yesNo(ballot.Passing())},
+			{Align: simpletable.AlignCenter, Text: ballot.Proposal.Hash.String()[:5]},
+		})
+	}
+	return table
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// SwingVoterTable returns a table of the members who have not voted on a proposal, marking those
+// whose vote could change its outcome
+func SwingVoterTable(voters []models.SwingVoter) *simpletable.Table {
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "#"},
+			{Align: simpletable.AlignCenter, Text: "Member"},
+			{Align: simpletable.AlignCenter, Text: "Vote Power"},
+			{Align: simpletable.AlignCenter, Text: "Swings Alone"},
+			{Align: simpletable.AlignCenter, Text: "Smallest Coalition"},
+		},
+	}
+
+	for index, voter := range voters {
+		table.Body.Cells = append(table.Body.Cells, []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(index + 1)},
+			{Align: simpletable.AlignLeft, Text: string(voter.Account)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&voter.VotePower, 2)},
+			{Align: simpletable.AlignCenter, Text: yesNo(voter.Alone)},
+			{Align: simpletable.AlignCenter, Text: yesNo(voter.Coalition)},
+		})
+	}
+	return table
+}
@@ -66,3 +66,66 @@ func RoleTable(roles []models.Role) *simpletable.Table {
 
 	return table
 }
+
+// RoleUtilisationTable returns a table with the capacity of each role, the time share of its
+// active and upcoming assignments, and its cost at full capacity against its committed cost
+func RoleUtilisationTable(utilisations []models.RoleUtilisation) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "Hash"},
+			{Align: simpletable.AlignCenter, Text: "Title"},
+			{Align: simpletable.AlignCenter, Text: "FTE Cap"},
+			{Align: simpletable.AlignCenter, Text: "Assignees"},
+			{Align: simpletable.AlignCenter, Text: "Active FTE"},
+			{Align: simpletable.AlignCenter, Text: "Upcoming FTE"},
+			{Align: simpletable.AlignCenter, Text: "Remaining FTE"},
+			{Align: simpletable.AlignCenter, Text: "Used %"},
+			{Align: simpletable.AlignCenter, Text: "FTE Cap Cost"},
+			{Align: simpletable.AlignCenter, Text: "Committed Cost"},
+		},
+	}
+
+	capCostTotal, _ := eos.NewAssetFromString("0.00 USD")
+	committedCostTotal, _ := eos.NewAssetFromString("0.00 USD")
+
+	for index := range utilisations {
+		u := &utilisations[index]
+		capCost := u.FTECapCost()
+		committedCost := u.CommittedCost()
+		capCostTotal = capCostTotal.Add(capCost)
+		committedCostTotal = committedCostTotal.Add(committedCost)
+
+		used := "n/a"
+		if u.FullTimeCapacity > 0 {
+			used = strconv.FormatFloat(u.Committed()/u.FullTimeCapacity*100, 'f', 0, 64)
+		}
+
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: u.Hash.String()[:5]},
+			{Align: simpletable.AlignLeft, Text: u.Title},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(u.FullTimeCapacity, 'f', 2, 64)},
+			{Align: simpletable.AlignRight, Text: strconv.Itoa(u.ActiveAssignments)},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(u.Active, 'f', 2, 64)},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(u.Upcoming, 'f', 2, 64)},
+			{Align: simpletable.AlignRight, Text: strconv.FormatFloat(u.Remaining(), 'f', 2, 64)},
+			{Align: simpletable.AlignRight, Text: used},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&capCost, 0)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&committedCost, 0)},
+		}
+
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+
+	table.Footer = &simpletable.Footer{
+		Cells: []*simpletable.Cell{
+			{}, {}, {}, {}, {}, {}, {},
+			{Align: simpletable.AlignRight, Text: "Subtotal"},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&capCostTotal, 0)},
+			{Align: simpletable.AlignRight, Text: util.FormatAsset(&committedCostTotal, 0)},
+		},
+	}
+
+	return table
+}
@@ -0,0 +1,35 @@
+package views
+
+import (
+	"strconv"
+
+	"github.com/alexeyco/simpletable"
+	"github.com/hypha-dao/daoctl/models"
+)
+
+// ViolationTable is a simpleTable.Table object with documents that do not conform to their schema
+func ViolationTable(violations []models.Violation) *simpletable.Table {
+
+	table := simpletable.New()
+	table.Header = &simpletable.Header{
+		Cells: []*simpletable.Cell{
+			{Align: simpletable.AlignCenter, Text: "ID"},
+			{Align: simpletable.AlignCenter, Text: "Type"},
+			{Align: simpletable.AlignCenter, Text: "Hash"},
+			{Align: simpletable.AlignCenter, Text: "Field"},
+			{Align: simpletable.AlignCenter, Text: "Problem"},
+		},
+	}
+
+	for _, violation := range violations {
+		r := []*simpletable.Cell{
+			{Align: simpletable.AlignRight, Text: strconv.FormatUint(violation.ID, 10)},
+			{Align: simpletable.AlignLeft, Text: string(violation.Type)},
+			{Align: simpletable.AlignLeft, Text: violation.Hash},
+			{Align: simpletable.AlignLeft, Text: violation.Field},
+			{Align: simpletable.AlignLeft, Text: violation.Problem},
+		}
+		table.Body.Cells = append(table.Body.Cells, r)
+	}
+	return table
+}